@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/permissions"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const importUsage = `Bruk: askeladden import -av <brukar-ID> [-godkjenn] <fil>
+
+Importerer forbodne ord frå ei CSV- eller TSV-fil med kolonnane ord, rett form og grunn.
+Nye ord ventar på godkjenning i retting-kanalen, med mindre -godkjenn er gitt
+av nokon som har både opplysar- og rettskrivarrolla.
+
+Flagg:`
+
+// runImportCommand handles the "import" subcommand, which bulk imports banned words from a wordlist.
+// It talks to Discord over REST only, to post the summary to the retting channel.
+func runImportCommand(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	importerID := flags.String("av", "", "Discord-ID-en til den som importerer orda")
+	approve := flags.Bool("godkjenn", false, "Godkjenn orda med ein gong, på vegner av både opplysar og rettskrivar")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), importUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if *importerID == "" || flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("manglar -av eller fil")
+	}
+
+	list, err := readWordlist(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	db, err := database.New(cfg)
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+	defer db.Close()
+	session, err := discordgo.New("Bot " + cfg.Discord.Token)
+	if err != nil {
+		return fmt.Errorf("could not create Discord session: %w", err)
+	}
+	askeladden := bot.New(cfg, db, session)
+
+	result, err := importWordlist(askeladden, list, *importerID, *approve)
+	if err != nil {
+		return err
+	}
+	printImportResult(os.Stdout, result)
+	return nil
+}
+
+// readWordlist parses the wordlist file at path
+func readWordlist(path string) (*bannedwords.Wordlist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list, err := bannedwords.ParseWordlist(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return list, nil
+}
+
+// importWordlist imports list on behalf of importerID, named after their Discord user if it can be looked up.
+// Approving at once is refused unless importerID holds both roles.
+func importWordlist(askeladden *bot.Bot, list *bannedwords.Wordlist, importerID string, approve bool) (*services.ImportResult, error) {
+	if approve {
+		role, err := importerRole(askeladden, importerID)
+		if err != nil {
+			return nil, err
+		}
+		if role != permissions.RoleBoth {
+			return nil, fmt.Errorf("-godkjenn krev at %s har både opplysar- og rettskrivarrolla", importerID)
+		}
+	}
+
+	importerName := importerID
+	if user, err := askeladden.Discord.User(importerID); err == nil {
+		importerName = user.Username
+	}
+	approval := &services.ApprovalService{Bot: askeladden}
+	return approval.ImportBannedWords(askeladden.Discord, list, importerID, importerName, approve)
+}
+
+// importerRole looks up the importer's roles in the guild of the retting channel, or of
+// the default channel if there is none
+func importerRole(askeladden *bot.Bot, importerID string) (permissions.UserRole, error) {
+	channelID := askeladden.Config.BannedWords.ApprovalChannelID
+	if channelID == "" {
+		channelID = askeladden.Config.Discord.DefaultChannelID
+	}
+	if channelID == "" {
+		return permissions.RoleNone, fmt.Errorf("no channel configured to find the guild of %s", importerID)
+	}
+	channel, err := askeladden.Discord.Channel(channelID)
+	if err != nil {
+		return permissions.RoleNone, fmt.Errorf("could not look up the guild of %s: %w", importerID, err)
+	}
+	return permissions.NewPermissionManager(askeladden.Config).GetUserRole(askeladden.Discord, channel.GuildID, importerID), nil
+}
+
+func printImportResult(w io.Writer, result *services.ImportResult) {
+	state := "ventar på godkjenning"
+	if result.Approved {
+		state = "godkjende"
+	}
+	fmt.Fprintf(w, "Lagt til: %d ord (%s)\n", len(result.Added), state)
+	if len(result.Duplicates) > 0 {
+		fmt.Fprintf(w, "Fanst frå før: %d ord: %s\n", len(result.Duplicates), strings.Join(result.Duplicates, ", "))
+	}
+	if len(result.Invalid) > 0 {
+		fmt.Fprintf(w, "Hoppa over: %d linjer\n", len(result.Invalid))
+		for _, line := range result.Invalid {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/fake"
+)
+
+func TestImportApproveNeedsBothRoles(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Approval.OpplysarRoleID = opplysarRole
+	cfg.BannedWords.RettskrivarRoleID = rettskrivarRole
+	cfg.BannedWords.ApprovalChannelID = rettingChannel
+	client := fake.New("bot")
+	client.AddChannel("guild", rettingChannel, "retting")
+	client.AddMember("guild", opplysar, opplysarRole)
+	client.AddMember("guild", rettskrivar, opplysarRole, rettskrivarRole)
+	db := database.NewMemory()
+	askeladden := bot.New(cfg, db, nil)
+	askeladden.Discord = client
+
+	list, err := bannedwords.ParseWordlist(strings.NewReader("ikke\nnoe\n"))
+	if err != nil {
+		t.Fatalf("ParseWordlist: %v", err)
+	}
+
+	for _, importerID := range []string{opplysar, member} {
+		if _, err := importWordlist(askeladden, list, importerID, true); err == nil {
+			t.Errorf("-godkjenn accepted for %s without both roles", importerID)
+		}
+	}
+	if words, _ := db.GetBannedWords(); len(words) != 0 {
+		t.Fatalf("refused imports added %d words", len(words))
+	}
+
+	result, err := importWordlist(askeladden, list, rettskrivar, true)
+	if err != nil || !result.Approved || len(result.Added) != 2 {
+		t.Fatalf("import by both roles = %+v, %v", result, err)
+	}
+	if n, _ := db.CountBannedWords("fully_approved"); n != 2 {
+		t.Errorf("approved words = %d, want 2", n)
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/handlers"
@@ -17,6 +20,9 @@ import (
 )
 
 func main() {
+	memoryDB := flag.Bool("memory-db", false, "Use an in-memory database instead of the configured one (data is lost on exit)")
+	flag.Parse()
+
 	// Load configuration using environment variables for file paths
 	configFile := os.Getenv("CONFIG_FILE")
 	if configFile == "" {
@@ -33,10 +39,29 @@ func main() {
 		log.Fatalf("[MAIN] Could not load configuration: %v", err)
 	}
 
+	// Subcommands run instead of the bot
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrateCommand(cfg, flag.Args()[1:]); err != nil {
+			log.Fatalf("[MIGRATE] %v", err)
+		}
+		return
+	}
+	if flag.Arg(0) == "import" {
+		if err := runImportCommand(cfg, flag.Args()[1:]); err != nil {
+			log.Fatalf("[IMPORT] %v", err)
+		}
+		return
+	}
+
 	// Opprett database-tilkobling
-	db, err := database.New(cfg)
-	if err != nil {
-		log.Fatalf("[MAIN] Could not connect to the database: %v", err)
+	var db database.DatabaseIface
+	if *memoryDB {
+		db = database.NewMemory()
+	} else {
+		db, err = database.New(cfg)
+		if err != nil {
+			log.Fatalf("[MAIN] Could not connect to the database: %v", err)
+		}
 	}
 
 	// Opprett Discord-sesjon
@@ -45,10 +70,23 @@ func main() {
 		log.Fatalf("[MAIN] Could not create Discord session: %v", err)
 	}
 
+	// Opprett bot
+	askeladden := newBot(cfg, db, session)
+
+	// Vent på avslutningssignal
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+
+	if err := run(askeladden, sc); err != nil {
+		log.Fatalf("[MAIN] %v", err)
+	}
+}
+
+// newBot creates the bot and wires its reactions and event handlers onto the session
+func newBot(cfg *config.Config, db database.DatabaseIface, session *discordgo.Session) *bot.Bot {
 	// Enable necessary intents for message content
 	session.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent | discordgo.IntentsGuildMessageReactions
 
-	// Opprett bot
 	askeladden := bot.New(cfg, db, session)
 
 	// Initialize reactions with configured emojis
@@ -62,32 +100,49 @@ func main() {
 	// Set opp hendingshandterarar
 	session.AddHandler(botHandlers.Ready)
 	session.AddHandler(botHandlers.MessageCreate)
+	session.AddHandler(botHandlers.MessageUpdate)
+	session.AddHandler(botHandlers.MessageDelete)
 	session.AddHandler(botHandlers.ReactionAdd)
 	session.AddHandler(botHandlers.ReactionRemove)
 	session.AddHandler(botHandlers.InteractionCreate)
 
+	return askeladden
+}
+
+// shutdownTimeout is how long shutdown waits for running jobs, and then for running handlers
+const shutdownTimeout = 10 * time.Second
+
+// run connects the bot, starts the scheduler and blocks until stop receives a signal or
+// the bot is shut down from inside, e.g. by ?loggav. Either way the root context is
+// cancelled, background jobs stop and running handlers get to finish before it logs off.
+func run(askeladden *bot.Bot, stop <-chan os.Signal) error {
 	// Start bot
 	if err := askeladden.Start(); err != nil {
-		log.Fatalf("[MAIN] Error running bot: %v", err)
+		return fmt.Errorf("error running bot: %w", err)
 	}
 
-	// Scheduler for daily question trigger
-	ticker := scheduleDailyQuestion(askeladden)
-	defer ticker.Stop()
+	// Scheduled jobs, among them the daily question
+	jobs := startScheduler(askeladden)
 
-	// Vent på avslutningssignal
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	select {
+	case <-stop:
+		log.Println("[MAIN] Received shutdown signal")
+		askeladden.Shutdown()
+	case <-askeladden.Context().Done():
+	}
+
+	jobs.Stop(shutdownTimeout)
+	askeladden.Drain(shutdownTimeout)
 
 	// Send goodbye message before stopping
 	if askeladden.Config.Discord.LogChannelID != "" {
-		embed := services.CreateBotEmbed(session, "🔴 Offline", "Askeladden is logging off. Goodbye! 👋", services.EmbedTypeError)
-		session.ChannelMessageSendEmbed(askeladden.Config.Discord.LogChannelID, embed)
+		embed := services.CreateBotEmbed(askeladden.Discord, "🔴 Offline", "Askeladden is logging off. Goodbye! 👋", services.EmbedTypeError)
+		askeladden.Discord.ChannelMessageSendEmbed(askeladden.Config.Discord.LogChannelID, embed)
 	}
 
 	// Stopp bot
 	if err := askeladden.Stop(); err != nil {
-		log.Fatalf("[MAIN] Error stopping bot: %v", err)
+		return fmt.Errorf("error stopping bot: %w", err)
 	}
+	return nil
 }
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/discordtest"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	logChannel       = "10"
+	generalChannel   = "11"
+	queueChannel     = "12"
+	rettingChannel   = "13"
+	grammarForum     = "14"
+	starboardChannel = "15"
+
+	opplysarRole    = "20"
+	rettskrivarRole = "21"
+	pratsamRole     = "22"
+	botRole         = "23"
+
+	member      = "30"
+	opplysar    = "31"
+	rettskrivar = "32"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// startBot runs the real wiring against a local Discord stand-in until the test ends
+func startBot(t *testing.T) (*discordtest.Server, *bot.Bot) {
+	t.Helper()
+
+	srv := discordtest.NewServer(t)
+	srv.AddChannel(logChannel, "logg")
+	srv.AddChannel(generalChannel, "generelt")
+	srv.AddChannel(queueChannel, "godkjenning")
+	srv.AddChannel(rettingChannel, "retting")
+	srv.AddForum(grammarForum, "grammatikk")
+	srv.AddChannel(starboardChannel, "stjernebrett")
+	srv.AddRole(opplysarRole, "opplysar", 3)
+	srv.AddRole(rettskrivarRole, "rettskrivar", 2)
+	srv.AddRole(pratsamRole, "pratsam", 1)
+	srv.AddRole(botRole, "Askeladden", 10)
+	srv.SetBotRoles(botRole)
+	srv.AddMember(member, "ola")
+	srv.AddMember(opplysar, "kari", opplysarRole)
+	srv.AddMember(rettskrivar, "per", rettskrivarRole, opplysarRole)
+
+	cfg := &config.Config{}
+	cfg.Discord.Token = discordtest.Token
+	cfg.Discord.Prefix = "?"
+	cfg.Discord.LogChannelID = logChannel
+	cfg.Discord.DefaultChannelID = generalChannel
+	cfg.Approval.QueueChannelID = queueChannel
+	cfg.Approval.OpplysarRoleID = opplysarRole
+	cfg.BannedWords.ApprovalChannelID = rettingChannel
+	cfg.BannedWords.RettskrivarRoleID = rettskrivarRole
+	cfg.Grammar.ChannelID = grammarForum
+	cfg.Starboard.ChannelID = starboardChannel
+	cfg.Starboard.Emoji = "⭐"
+	cfg.Starboard.Threshold = 1
+	cfg.Reactions.Question = "❓"
+
+	session, err := discordgo.New("Bot " + cfg.Discord.Token)
+	if err != nil {
+		t.Fatalf("discordgo.New: %v", err)
+	}
+	askeladden := newBot(cfg, database.NewMemory(), session)
+
+	stop := make(chan os.Signal)
+	done := make(chan error, 1)
+	go func() { done <- run(askeladden, stop) }()
+	t.Cleanup(func() {
+		close(stop)
+		if err := <-done; err != nil {
+			t.Errorf("run: %v", err)
+		}
+	})
+
+	srv.WaitForMessage(t, logChannel, discordtest.HasEmbedTitle("🟢 Online"))
+	return srv, askeladden
+}
+
+func TestEndToEnd(t *testing.T) {
+	srv, askeladden := startBot(t)
+
+	if srv.Intents()&discordgo.IntentsMessageContent == 0 {
+		t.Errorf("bot identified without the message content intent")
+	}
+
+	t.Run("question is submitted, approved and posted", func(t *testing.T) {
+		srv.SendMessage(generalChannel, member, "?spør Kva er yndlingsordet ditt?", "")
+
+		srv.WaitForMessage(t, generalChannel, discordtest.HasEmbedTitle("Spørsmål motteke"))
+		queued := srv.WaitForMessage(t, queueChannel, discordtest.HasEmbedTitle("Kva er yndlingsordet ditt?"))
+		srv.WaitFor(t, "approval question ID", func() bool {
+			question, err := askeladden.Database.GetQuestionByApprovalMessageID(queued.ID)
+			return err == nil && question != nil
+		})
+
+		click(srv, opplysar, queued, "godkjenning:godkjenn:question:1")
+		srv.WaitForMessage(t, srv.DMChannel(member), discordtest.HasEmbedTitle("Gratulerer"))
+		srv.WaitFor(t, "approval embed turning green", func() bool {
+			embeds := srv.Message(queued.ID).Embeds
+			return len(embeds) == 1 && strings.Contains(embeds[0].Description, "kari")
+		})
+
+		triggerDailyQuestion(askeladden)
+		daily := srv.WaitForMessage(t, generalChannel, discordtest.HasEmbedTitle("Dagens spørsmål"))
+		if daily.Content != "<@&"+pratsamRole+">" || daily.Embeds[0].Description != "Kva er yndlingsordet ditt?" {
+			t.Fatalf("daily question = %q / %q", daily.Content, daily.Embeds[0].Description)
+		}
+	})
+
+	t.Run("hammered word is reported, approved and warned about", func(t *testing.T) {
+		original := srv.SendMessage(generalChannel, member, "Eg veit ikke", "")
+		srv.Interact(member, &discordgo.Interaction{
+			Type:      discordgo.InteractionApplicationCommand,
+			ChannelID: generalChannel,
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:        "Rapporter feil ord",
+				CommandType: discordgo.MessageApplicationCommand,
+				TargetID:    original.ID,
+			},
+		})
+		var modalID string
+		srv.WaitFor(t, "report modal", func() bool {
+			for _, response := range srv.InteractionResponses() {
+				if response.Type == discordgo.InteractionResponseModal {
+					modalID = response.Data.CustomID
+					return true
+				}
+			}
+			return false
+		})
+
+		srv.SubmitModal(member, generalChannel, modalID, discordgo.TextInput{CustomID: "ord", Value: "ikke"})
+		srv.WaitForMessage(t, generalChannel, discordtest.HasEmbedTitle("Ord rapporterte"))
+		pending := srv.WaitForMessage(t, rettingChannel, discordtest.HasEmbedTitle("ikke"))
+
+		// per holds both roles, so one click completes the approval
+		srv.WaitFor(t, "approval word ID", func() bool {
+			word, err := askeladden.Database.GetBannedWordByApprovalMessageID(pending.ID)
+			return err == nil && word != nil
+		})
+		click(srv, rettskrivar, pending, "godkjenning:godkjenn:banned_word:1")
+		srv.WaitFor(t, "forum thread", func() bool { return len(srv.Threads()) == 1 })
+		thread := srv.Threads()[0]
+		if thread.ParentID != grammarForum || thread.Name != "ikke" {
+			t.Fatalf("thread = %+v", thread)
+		}
+		srv.WaitForMessage(t, thread.ID, discordtest.HasEmbedTitle("Grammatikkdiskusjon"))
+
+		used := srv.SendMessage(generalChannel, member, "Det veit eg ikke.", "")
+		warning := srv.WaitForMessage(t, generalChannel, func(msg *discordgo.Message) bool {
+			return msg.MessageReference != nil && msg.MessageReference.MessageID == used.ID
+		})
+		if !strings.Contains(warning.Embeds[0].Description, "<#"+thread.ID+">") {
+			t.Fatalf("warning does not link the thread: %q", warning.Embeds[0].Description)
+		}
+	})
+
+	t.Run("starboard follows the star count", func(t *testing.T) {
+		popular := srv.SendMessage(generalChannel, member, "Gode ord er gull verd", "")
+		srv.React(popular.ID, opplysar, "⭐")
+		srv.WaitForMessage(t, starboardChannel, func(msg *discordgo.Message) bool {
+			return len(msg.Embeds) == 1 && msg.Embeds[0].Description == "Gode ord er gull verd"
+		})
+
+		srv.Unreact(popular.ID, opplysar, "⭐")
+		srv.WaitFor(t, "starboard post removal", func() bool { return len(srv.Messages(starboardChannel)) == 0 })
+	})
+
+	t.Run("kjeften toggles the pratsam role", func(t *testing.T) {
+		kjeften := func(wantRoles int) {
+			before := len(srv.Messages(generalChannel))
+			srv.SendMessage(generalChannel, member, "?kjeften", "")
+			srv.WaitFor(t, "kjeften reply", func() bool {
+				return len(srv.Messages(generalChannel)) == before+2 && len(srv.Member(member).Roles) == wantRoles
+			})
+		}
+
+		kjeften(1)
+		if roles := srv.Member(member).Roles; roles[0] != pratsamRole {
+			t.Fatalf("roles = %v, want pratsam", roles)
+		}
+		kjeften(0)
+	})
+
+	t.Run("slash commands are registered and answered", func(t *testing.T) {
+		srv.WaitFor(t, "slash command registration", func() bool { return len(srv.Commands()) > 0 })
+		before := len(srv.InteractionResponses())
+
+		srv.Interact(member, &discordgo.Interaction{
+			Type:      discordgo.InteractionApplicationCommand,
+			ChannelID: generalChannel,
+			Data:      discordgo.ApplicationCommandInteractionData{Name: "kjeften"},
+		})
+		srv.WaitFor(t, "pratsam role", func() bool { return len(srv.Member(member).Roles) == 1 })
+		srv.WaitFor(t, "interaction reply", func() bool {
+			responses := srv.InteractionResponses()
+			return len(responses) == before+1 && responses[before].Type == discordgo.InteractionResponseChannelMessageWithSource
+		})
+
+		srv.Interact(member, &discordgo.Interaction{
+			Type:      discordgo.InteractionApplicationCommand,
+			ChannelID: generalChannel,
+			Data:      discordgo.ApplicationCommandInteractionData{Name: "kjeften"},
+		})
+		srv.WaitFor(t, "pratsam role removal", func() bool { return len(srv.Member(member).Roles) == 0 })
+		srv.WaitFor(t, "second interaction reply", func() bool { return len(srv.InteractionResponses()) == before+2 })
+	})
+
+	t.Run("admin commands are ignored for regular members", func(t *testing.T) {
+		before := len(srv.Messages(generalChannel))
+		srv.SendMessage(generalChannel, member, "?godkjenn neste", "")
+		srv.SendMessage(generalChannel, opplysar, "?godkjenn neste", "")
+		srv.WaitForMessage(t, generalChannel, discordtest.HasEmbedTitle("Ingen ventande"))
+
+		// Both commands ran concurrently; only the opplysar's produced a reply
+		if got := len(srv.Messages(generalChannel)) - before; got != 3 {
+			t.Fatalf("messages after two commands = %d, want the two commands and one reply", got)
+		}
+	})
+}
+
+// click presses a button on a message
+func click(srv *discordtest.Server, userID string, msg *discordgo.Message, customID string) {
+	srv.Interact(userID, &discordgo.Interaction{
+		Type:      discordgo.InteractionMessageComponent,
+		ChannelID: msg.ChannelID,
+		Message:   &discordgo.Message{ID: msg.ID},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+	})
+}
+
+func TestShutdownPostsGoodbye(t *testing.T) {
+	srv := discordtest.NewServer(t)
+	srv.AddChannel(logChannel, "logg")
+
+	cfg := &config.Config{}
+	cfg.Discord.Prefix = "?"
+	cfg.Discord.LogChannelID = logChannel
+
+	session, err := discordgo.New("Bot " + discordtest.Token)
+	if err != nil {
+		t.Fatalf("discordgo.New: %v", err)
+	}
+	askeladden := newBot(cfg, database.NewMemory(), session)
+
+	stop := make(chan os.Signal, 1)
+	done := make(chan error, 1)
+	go func() { done <- run(askeladden, stop) }()
+	srv.WaitForMessage(t, logChannel, discordtest.HasEmbedTitle("🟢 Online"))
+
+	stop <- os.Interrupt
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if messages := srv.Messages(logChannel); len(messages) != 2 || !discordtest.HasEmbedTitle("🔴 Offline")(messages[1]) {
+		t.Fatalf("log channel = %d messages, want online and offline", len(messages))
+	}
+}
+
+func TestLoggavShutsDown(t *testing.T) {
+	srv := discordtest.NewServer(t)
+	srv.AddChannel(logChannel, "logg")
+	srv.AddChannel(generalChannel, "generelt")
+	srv.AddRole(opplysarRole, "opplysar", 3)
+	srv.AddMember(opplysar, "kari", opplysarRole)
+
+	cfg := &config.Config{}
+	cfg.Discord.Prefix = "?"
+	cfg.Discord.LogChannelID = logChannel
+	cfg.Approval.OpplysarRoleID = opplysarRole
+
+	session, err := discordgo.New("Bot " + discordtest.Token)
+	if err != nil {
+		t.Fatalf("discordgo.New: %v", err)
+	}
+	askeladden := newBot(cfg, database.NewMemory(), session)
+
+	done := make(chan error, 1)
+	go func() { done <- run(askeladden, make(chan os.Signal)) }()
+	srv.WaitForMessage(t, logChannel, discordtest.HasEmbedTitle("🟢 Online"))
+
+	// ?loggav goes through the same shutdown as a signal, so the goodbye is still posted
+	srv.SendMessage(generalChannel, opplysar, "?loggav", "")
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if messages := srv.Messages(logChannel); len(messages) != 2 || !discordtest.HasEmbedTitle("🔴 Offline")(messages[1]) {
+		t.Fatalf("log channel = %d messages, want online and offline", len(messages))
+	}
+}
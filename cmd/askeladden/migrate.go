@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+)
+
+const migrateUsage = `Bruk: askeladden migrate <kommando>
+
+Kommandoar:
+  status       Vis alle migrasjonar og om dei er køyrde
+  up           Køyr alle ventande migrasjonar
+  down [n]     Rull tilbake dei n siste migrasjonane (standard 1)`
+
+// runMigrateCommand handles the "migrate" subcommand for inspecting and rolling back the schema
+func runMigrateCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "status":
+		statuses, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "ventar"
+			if status.Applied {
+				state = "køyrd " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+
+	case "up":
+		return db.Migrate()
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("ugyldig tal på steg: %q", args[1])
+			}
+		}
+		if err := db.MigrateDown(steps); err != nil {
+			return err
+		}
+		log.Printf("[MIGRATE] Rolled back %d migration(s)", steps)
+		return nil
+
+	default:
+		return fmt.Errorf("ukjend migrate-kommando %q\n\n%s", args[0], migrateUsage)
+	}
+}
@@ -3,34 +3,73 @@ package main
 import (
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/clock"
+	"askeladden/internal/database"
+	"askeladden/internal/scheduler"
+	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
+
+	"github.com/bwmarrin/discordgo"
 )
 
+// dayLayout formats the calendar day a daily question is posted on
+const dayLayout = "2006-01-02"
+
+// SchedulerState is the scheduler's working copy of what is stored in the database,
+// loaded on startup so a restart neither posts twice nor forgets the last activity
 type SchedulerState struct {
-	lastActivity    time.Time
-	lastDailyPost   time.Time
+	mu              sync.Mutex // held by the job running the daily question logic
+	clock           clock.Clock
+	lastPostDay     string // calendar day in timezone of the last daily question
 	timezone        *time.Location
-	morningTime     time.Time
-	eveningTime     time.Time
+	morningTime     time.Time // time of day only, parsed from "15:04"
+	eveningTime     time.Time // time of day only; the nighttime cutoff
 	inactivityHours time.Duration
 }
 
-// scheduleDailyQuestion sets up the advanced scheduler with timezone and inactivity support
-func scheduleDailyQuestion(b *bot.Bot) *time.Ticker {
-	if !b.Config.Scheduler.Enabled {
-		log.Println("[SCHEDULER] Scheduler is disabled in config")
-		return time.NewTicker(time.Hour) // Return a dummy ticker
-	}
+// timeOn returns the wall-clock time of day timeOfDay on the calendar day of now in the
+// scheduler's timezone. A time skipped by a daylight saving change becomes the time
+// just after it, e.g. 02:30 on the night the clocks go forward is 03:30.
+func (state *SchedulerState) timeOn(now, timeOfDay time.Time) time.Time {
+	now = now.In(state.timezone)
+	return time.Date(now.Year(), now.Month(), now.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, state.timezone)
+}
+
+// Scheduled job names, as shown and run by ?jobbar
+const (
+	dailyQuestionJob  = "dagsspørsmål"
+	fallbackJob       = "dagsspørsmål-reserve"
+	pruneWarningsJob  = "rydd-åtvaringar"
+	warningsRetention = 30 * 24 * time.Hour
+)
 
-	// Parse timezone
+// startScheduler registers the bot's jobs and starts running them in the configured timezone
+func startScheduler(b *bot.Bot) *scheduler.Scheduler {
 	timezone, err := time.LoadLocation(b.Config.Scheduler.Timezone)
 	if err != nil {
 		log.Printf("[SCHEDULER] Invalid timezone '%s', using UTC: %v", b.Config.Scheduler.Timezone, err)
 		timezone = time.UTC
 	}
 
+	jobs := scheduler.New(b.Database, timezone)
+	registerDailyQuestionJobs(b, jobs)
+	registerCleanupJobs(b, jobs, clock.Real{})
+	b.Jobs = jobs
+	jobs.Start(b.Context())
+	return jobs
+}
+
+// registerDailyQuestionJobs sets up the daily question with timezone and inactivity support,
+// checked every half hour, and the cron_string fallback for days nothing else posted by then
+func registerDailyQuestionJobs(b *bot.Bot, jobs *scheduler.Scheduler) {
+	if !b.Config.Scheduler.Enabled {
+		log.Println("[SCHEDULER] Scheduler is disabled in config")
+		return
+	}
+
 	// Parse morning and evening times
 	morningTime, err := time.Parse("15:04", b.Config.Scheduler.MorningTime)
 	if err != nil {
@@ -45,66 +84,123 @@ func scheduleDailyQuestion(b *bot.Bot) *time.Ticker {
 	}
 
 	state := &SchedulerState{
-		lastActivity:    time.Now(),
-		lastDailyPost:   time.Time{}, // Never posted
-		timezone:        timezone,
+		clock:           clock.Real{},
+		timezone:        jobs.Location(),
 		morningTime:     morningTime,
 		eveningTime:     eveningTime,
 		inactivityHours: time.Duration(b.Config.Scheduler.InactivityHours) * time.Hour,
 	}
+	loadSchedulerState(b, state)
 
 	log.Printf("[SCHEDULER] Advanced scheduler started - Timezone: %s, Morning: %s, Evening: %s, Inactivity: %v",
-		timezone.String(), b.Config.Scheduler.MorningTime, b.Config.Scheduler.EveningTime, state.inactivityHours)
-
-	// Check every 30 minutes
-	ticker := time.NewTicker(30 * time.Minute)
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				checkAndTriggerDailyQuestion(b, state)
+		state.timezone.String(), b.Config.Scheduler.MorningTime, b.Config.Scheduler.EveningTime, state.inactivityHours)
+
+	jobs.Register(scheduler.Job{
+		Name:        dailyQuestionJob,
+		Description: "Post dagens spørsmål om morgonen eller når det har vore stille lenge",
+		Schedule:    scheduler.MustParseCron("*/30 * * * *"),
+		Run: func(context.Context) error {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			checkDailyQuestion(b, state)
+			return nil
+		},
+	})
+
+	if b.Config.Scheduler.CronString == "" {
+		return
+	}
+	fallback, err := scheduler.ParseCron(b.Config.Scheduler.CronString)
+	if err != nil {
+		log.Printf("[SCHEDULER] Invalid cron_string, no fallback for the daily question: %v", err)
+		return
+	}
+	jobs.Register(scheduler.Job{
+		Name:        fallbackJob,
+		Description: "Post dagens spørsmål om det ikkje er posta enno i dag",
+		Schedule:    fallback,
+		CatchUp:     scheduler.RunMissedOnce,
+		Run: func(context.Context) error {
+			state.mu.Lock()
+			defer state.mu.Unlock()
+			fallbackDailyQuestion(b, state)
+			return nil
+		},
+	})
+}
+
+// registerCleanupJobs sets up housekeeping of old database rows, with retention counted back from clk
+func registerCleanupJobs(b *bot.Bot, jobs *scheduler.Scheduler, clk clock.Clock) {
+	jobs.Register(scheduler.Job{
+		Name:        pruneWarningsJob,
+		Description: "Gløym åtvaringar eldre enn 30 dagar",
+		Schedule:    scheduler.MustParseCron("0 4 * * *"),
+		CatchUp:     scheduler.RunMissedOnce,
+		Run: func(context.Context) error {
+			pruned, err := b.Database.PruneWarnings(clk.Now().Add(-warningsRetention))
+			if err != nil {
+				return err
 			}
-		}
-	}()
+			log.Printf("[SCHEDULER] Pruned %d old warnings", pruned)
+			return nil
+		},
+	})
+}
 
-	return ticker
+// loadSchedulerState restores the last post and activity from the database. Without a
+// stored activity the inactivity window starts now, so a fresh start does not post at once.
+func loadSchedulerState(b *bot.Bot, state *SchedulerState) {
+	stored, err := b.Database.GetSchedulerState()
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to load scheduler state, starting fresh: %v", err)
+		b.Activity.Touch(state.clock.Now())
+		return
+	}
+	state.lastPostDay = stored.LastPostDay
+	if stored.LastActivityAt != nil {
+		b.Activity.Touch(*stored.LastActivityAt)
+	} else {
+		b.Activity.Touch(state.clock.Now())
+	}
+	log.Printf("[SCHEDULER] Restored state - Last post: %q, Last activity: %s", state.lastPostDay, b.Activity.LastActivity().Format(time.RFC3339))
 }
 
 // triggerDailyQuestion handles the daily question logic.
-func triggerDailyQuestion(b *bot.Bot) {
+// It returns the question and the posted message; either is nil if nothing was posted.
+func triggerDailyQuestion(b *bot.Bot) (*database.Question, *discordgo.Message) {
 	// Retrieve least asked approved question
 	question, err := b.Database.GetLeastAskedApprovedQuestion()
 	if err != nil {
 		log.Printf("[SCHEDULER] Failed to retrieve daily question: %v", err)
-		return
+		return nil, nil
 	}
 
 	if question == nil {
 		log.Println("[SCHEDULER] No approved questions available for the day.")
-		return
+		return nil, nil
 	}
 
 	// Increment usage for the question
 	err = b.Database.IncrementQuestionUsage(question.ID)
 	if err != nil {
 		log.Printf("[SCHEDULER] Failed to update question usage: %v", err)
-		return
+		return question, nil
 	}
 
 	// Send the question to the default channel
 	if b.Config.Discord.DefaultChannelID != "" {
 		// Get guild ID from the channel
-		channel, err := b.Session.Channel(b.Config.Discord.DefaultChannelID)
+		channel, err := b.Discord.Channel(b.Config.Discord.DefaultChannelID)
 		if err != nil {
 			log.Printf("[SCHEDULER] Failed to get channel info: %v", err)
-			return
+			return question, nil
 		}
 
 		// Get pratsam role ID
 		roleID, err := services.GetPratsamRoleID(b, channel.GuildID)
 		if err != nil {
 			log.Printf("[SCHEDULER] Failed to get pratsam role ID: %v", err)
-			return
+			return question, nil
 		}
 
 		// Format role mention if role exists
@@ -113,60 +209,103 @@ func triggerDailyQuestion(b *bot.Bot) {
 			mention = "<@&" + roleID + ">"
 		}
 
-		services.SendDailyQuestion(b, question, mention)
-		log.Printf("[SCHEDULER] Daily question sent: %s", question.Question)
-	} else {
-		log.Println("[SCHEDULER] Default channel not configured.")
+		message := services.SendDailyQuestion(b, question, mention)
+		if message != nil {
+			log.Printf("[SCHEDULER] Daily question sent: %s", question.Question)
+		}
+		return question, message
 	}
+	log.Println("[SCHEDULER] Default channel not configured.")
+	return question, nil
 }
 
-// checkAndTriggerDailyQuestion implements the scheduling logic:
-// 1. Post at morning time (08:00)
-// 2. Post after 6 hours of inactivity, but only before nighttime (20:00)
+// checkDailyQuestion implements the scheduling logic:
+// 1. Post at morning time (08:00), or within half an hour of it
+// 2. Post after 6 hours of inactivity, but only between morning and nighttime (20:00)
 // 3. Stop posting once nighttime is reached
-func checkAndTriggerDailyQuestion(b *bot.Bot, state *SchedulerState) {
-	now := time.Now().In(state.timezone)
-	timeSinceLastActivity := now.Sub(state.lastActivity)
+// Times of day are wall-clock times in the scheduler's timezone on the current day,
+// and inactivity is measured in elapsed time, so daylight saving changes shift neither.
+func checkDailyQuestion(b *bot.Bot, state *SchedulerState) {
+	now := state.clock.Now().In(state.timezone)
+	lastActivity := b.Activity.LastActivity()
+	timeSinceLastActivity := now.Sub(lastActivity)
 
-	// Get current time components for comparison
-	currentTime := time.Date(0, 1, 1, now.Hour(), now.Minute(), 0, 0, time.UTC)
-	morningTime := state.morningTime
-	eveningTime := state.eveningTime // This is our "nighttime" cutoff
+	// Keep the stored activity current, so a restart continues the inactivity window
+	if !lastActivity.IsZero() {
+		if err := b.Database.RecordActivity(lastActivity); err != nil {
+			log.Printf("[SCHEDULER] Failed to record activity: %v", err)
+		}
+	}
 
 	// Check if we've already posted today
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, state.timezone)
-	hasPostedToday := state.lastDailyPost.After(todayStart)
-
-	shouldTrigger := false
-	reason := ""
-
-	// Condition 1: It's morning time and we haven't posted today yet
-	if currentTime.After(morningTime) && currentTime.Before(morningTime.Add(30*time.Minute)) && !hasPostedToday {
-		shouldTrigger = true
-		reason = fmt.Sprintf("morning schedule (%s)", b.Config.Scheduler.MorningTime)
-	}
-
-	// Condition 2: Inactivity threshold reached, but only if:
-	// - It's after morning time
-	// - It's before nighttime (evening_time)
-	// - We haven't posted today yet
-	if timeSinceLastActivity >= state.inactivityHours && !hasPostedToday {
-		if currentTime.After(morningTime) && currentTime.Before(eveningTime) {
-			shouldTrigger = true
-			reason = fmt.Sprintf("inactivity threshold (%v since last activity, before nighttime)", timeSinceLastActivity.Round(time.Minute))
-		} else if currentTime.After(eveningTime) {
-			// After nighttime - log but don't trigger
-			log.Printf("[SCHEDULER] Inactivity threshold reached (%v) but nighttime reached (%s) - waiting until tomorrow morning",
-				timeSinceLastActivity.Round(time.Minute), b.Config.Scheduler.EveningTime)
-		}
+	if state.lastPostDay == now.Format(dayLayout) {
+		return
 	}
+	morning := state.timeOn(now, state.morningTime)
+	evening := state.timeOn(now, state.eveningTime) // This is our "nighttime" cutoff
 
-	if shouldTrigger {
-		log.Printf("[SCHEDULER] Triggering daily question due to: %s", reason)
-		triggerDailyQuestion(b)
-		state.lastDailyPost = now
+	switch {
+	case !now.Before(morning) && now.Before(morning.Add(30*time.Minute)):
+		postDailyQuestion(b, state, now, fmt.Sprintf("morning schedule (%s)", b.Config.Scheduler.MorningTime))
+	case timeSinceLastActivity < state.inactivityHours:
+	case now.After(morning) && now.Before(evening):
+		postDailyQuestion(b, state, now,
+			fmt.Sprintf("inactivity threshold (%v since last activity, before nighttime)", timeSinceLastActivity.Round(time.Minute)))
+	case !now.Before(evening):
+		// After nighttime - log but don't trigger
+		log.Printf("[SCHEDULER] Inactivity threshold reached (%v) but nighttime reached (%s) - waiting until tomorrow morning",
+			timeSinceLastActivity.Round(time.Minute), b.Config.Scheduler.EveningTime)
+	}
+}
+
+// fallbackDailyQuestion posts the daily question if nothing has been posted today,
+// unless it is already nighttime
+func fallbackDailyQuestion(b *bot.Bot, state *SchedulerState) {
+	now := state.clock.Now().In(state.timezone)
+	if state.lastPostDay == now.Format(dayLayout) {
+		return
+	}
+	if !now.Before(state.timeOn(now, state.eveningTime)) {
+		log.Printf("[SCHEDULER] Fallback reached after nighttime (%s) - waiting until tomorrow", b.Config.Scheduler.EveningTime)
+		return
+	}
+	postDailyQuestion(b, state, now, fmt.Sprintf("fallback cron (%s)", b.Config.Scheduler.CronString))
+}
+
+// postDailyQuestion posts the daily question unless it has been posted today already,
+// claiming the day in the database first so that no restart or second instance posts it
+// again. If nothing gets posted the claim is released, so a later check or the fallback
+// can try again the same day.
+func postDailyQuestion(b *bot.Bot, state *SchedulerState, now time.Time, reason string) {
+	today := now.Format(dayLayout)
+	claimed, err := b.Database.ClaimDailyPost(today, now)
+	if err != nil {
+		log.Printf("[SCHEDULER] Not posting, could not claim %s: %v", today, err)
+		return
+	}
+	if !claimed {
+		log.Printf("[SCHEDULER] Daily question for %s was already posted", today)
+		state.lastPostDay = today
+		return
+	}
+
+	log.Printf("[SCHEDULER] Triggering daily question due to: %s", reason)
+	question, message := triggerDailyQuestion(b)
+	if message == nil {
+		log.Printf("[SCHEDULER] No daily question posted for %s, releasing the day", today)
+		if err := b.Database.ReleaseDailyPost(today); err != nil {
+			log.Printf("[SCHEDULER] Failed to release daily post: %v", err)
+		}
+		return
+	}
+	state.lastPostDay = today
+	if err := b.Database.RecordDailyPost(question.ID, message.ID); err != nil {
+		log.Printf("[SCHEDULER] Failed to record daily post: %v", err)
+	}
 
-		// Reset activity timer when we post
-		state.lastActivity = now
+	// Reset activity timer when we post
+	b.Activity.Touch(now)
+	if err := b.Database.RecordActivity(now); err != nil {
+		log.Printf("[SCHEDULER] Failed to record activity: %v", err)
 	}
 }
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/clock"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/fake"
+	"askeladden/internal/scheduler"
+)
+
+// schedulerTest is a bot with one approved question and the daily question scheduler
+// for a timezone, with questions at 08:00, until 20:00 and after 6 quiet hours
+type schedulerTest struct {
+	t      *testing.T
+	db     *database.MemoryDB
+	clock  *clock.Fake
+	bot    *bot.Bot
+	client *fake.Client
+	state  *SchedulerState
+}
+
+// newSchedulerTest starts the scheduler at start, quiet since then unless db says otherwise
+func newSchedulerTest(t *testing.T, timezone string, start time.Time) *schedulerTest {
+	t.Helper()
+	db := database.NewMemory()
+	id, _ := db.AddQuestion("Kva er yndlingsordet ditt?", "author", "kari", "msg", generalChannel)
+	db.ApproveQuestion(int(id), "opplysar")
+	st := &schedulerTest{t: t, db: db, clock: clock.NewFake(start)}
+	st.restart(timezone)
+	return st
+}
+
+// restart sets the scheduler up again on the same database and clock, as after a deploy
+func (st *schedulerTest) restart(timezone string) {
+	st.t.Helper()
+	cfg := &config.Config{}
+	cfg.Discord.DefaultChannelID = generalChannel
+	cfg.Scheduler.MorningTime = "08:00"
+	cfg.Scheduler.EveningTime = "20:00"
+	st.client = fake.New("bot")
+	st.client.AddChannel("guild", generalChannel, "generelt")
+	st.bot = bot.New(cfg, st.db, nil)
+	st.bot.Discord = st.client
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		st.t.Fatalf("LoadLocation: %v", err)
+	}
+	st.state = &SchedulerState{
+		clock:           st.clock,
+		timezone:        location,
+		morningTime:     timeOfDay("08:00"),
+		eveningTime:     timeOfDay("20:00"),
+		inactivityHours: 6 * time.Hour,
+	}
+	loadSchedulerState(st.bot, st.state)
+}
+
+// checkAt runs the scheduler's check at the given time and returns how many questions it posted
+func (st *schedulerTest) checkAt(now time.Time) int {
+	st.clock.Set(now)
+	before := len(st.client.SentTo(generalChannel))
+	checkDailyQuestion(st.bot, st.state)
+	return len(st.client.SentTo(generalChannel)) - before
+}
+
+func timeOfDay(text string) time.Time {
+	t, err := time.Parse("15:04", text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func oslo(t *testing.T) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	return location
+}
+
+func TestSchedulerMorning(t *testing.T) {
+	tz := oslo(t)
+	day := func(hour, minute int) time.Time { return time.Date(2024, 5, 17, hour, minute, 0, 0, tz) }
+
+	st := newSchedulerTest(t, "Europe/Oslo", day(7, 0))
+	st.bot.Activity.Record(generalChannel, "kari", day(7, 50))
+	if posted := st.checkAt(day(7, 59)); posted != 0 {
+		t.Fatalf("posted before morning")
+	}
+	// Morning posts even though people are talking, on the minute the job runs
+	if posted := st.checkAt(day(8, 0)); posted != 1 {
+		t.Fatalf("posted %d at 08:00, want 1", posted)
+	}
+	if posted := st.checkAt(day(8, 20)); posted != 0 {
+		t.Fatalf("posted again in the same morning")
+	}
+
+	// Started after the morning window with people talking: nothing until they go quiet
+	st = newSchedulerTest(t, "Europe/Oslo", day(8, 40))
+	st.bot.Activity.Record(generalChannel, "kari", day(8, 40))
+	if posted := st.checkAt(day(9, 0)); posted != 0 {
+		t.Fatalf("posted after the morning window")
+	}
+	if posted := st.checkAt(day(14, 40)); posted != 1 {
+		t.Fatalf("posted %d after six quiet hours, want 1", posted)
+	}
+}
+
+func TestSchedulerInactivity(t *testing.T) {
+	tz := oslo(t)
+	day := func(hour, minute int) time.Time { return time.Date(2024, 5, 17, hour, minute, 0, 0, tz) }
+
+	st := newSchedulerTest(t, "Europe/Oslo", day(9, 0))
+	st.bot.Activity.Record("other", "kari", day(11, 0))
+	st.bot.Activity.Record(generalChannel, "kari", day(10, 0))
+
+	// Six quiet hours after the last message in the default channel, not after the first
+	if posted := st.checkAt(day(15, 30)); posted != 0 {
+		t.Fatalf("posted before six quiet hours")
+	}
+	if stored, _ := st.db.GetSchedulerState(); !stored.LastActivityAt.Equal(day(10, 0)) {
+		t.Errorf("stored activity = %v, want the last message", stored.LastActivityAt)
+	}
+	if posted := st.checkAt(day(16, 0)); posted != 1 {
+		t.Fatalf("posted %d after six quiet hours, want 1", posted)
+	}
+	// Posting counts as activity
+	if got := st.bot.Activity.LastActivity(); !got.Equal(day(16, 0)) {
+		t.Errorf("last activity after posting = %v", got)
+	}
+}
+
+func TestSchedulerNightCutoff(t *testing.T) {
+	tz := oslo(t)
+	st := newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 16, 13, 0, 0, 0, tz))
+
+	// Quiet from 13:00, so the threshold is reached at 19:00, before nighttime
+	if posted := st.checkAt(time.Date(2024, 5, 16, 19, 0, 0, 0, tz)); posted != 1 {
+		t.Fatalf("posted %d before nighttime, want 1", posted)
+	}
+
+	st = newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 16, 15, 0, 0, 0, tz))
+	for _, check := range []time.Time{
+		time.Date(2024, 5, 16, 21, 0, 0, 0, tz),  // quiet long enough, but nighttime
+		time.Date(2024, 5, 16, 23, 30, 0, 0, tz), // still nighttime
+		time.Date(2024, 5, 17, 6, 0, 0, 0, tz),   // a new day, but before morning
+	} {
+		if posted := st.checkAt(check); posted != 0 {
+			t.Fatalf("posted at %s", check)
+		}
+	}
+	if posted := st.checkAt(time.Date(2024, 5, 17, 8, 0, 0, 0, tz)); posted != 1 {
+		t.Fatalf("posted %d the next morning, want 1", posted)
+	}
+}
+
+func TestSchedulerDST(t *testing.T) {
+	tz := oslo(t)
+	tests := []struct {
+		name    string
+		morning string
+		start   time.Time   // quiet since
+		checks  []time.Time // every check, the expected post last
+	}{
+		{
+			// Clocks go forward at 02:00 on 31 March 2024: 08:00 CEST is 06:00 UTC
+			name:    "morning after spring forward",
+			morning: "08:00",
+			start:   time.Date(2024, 3, 30, 22, 0, 0, 0, tz),
+			checks: []time.Time{
+				time.Date(2024, 3, 31, 5, 30, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 6, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// 02:30 does not exist that night, so morning is when the clock shows 03:30
+			name:    "skipped morning time",
+			morning: "02:30",
+			start:   time.Date(2024, 3, 30, 22, 0, 0, 0, tz),
+			checks: []time.Time{
+				time.Date(2024, 3, 31, 0, 30, 0, 0, time.UTC), // 01:30 CET
+				time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC),  // 03:00 CEST
+				time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC), // 03:30 CEST
+			},
+		},
+		{
+			// Clocks go back at 03:00 on 27 October 2024: 08:00 CET is 07:00 UTC
+			name:    "morning after fall back",
+			morning: "08:00",
+			start:   time.Date(2024, 10, 26, 22, 0, 0, 0, tz),
+			checks: []time.Time{
+				time.Date(2024, 10, 27, 6, 0, 0, 0, time.UTC), // 07:00 CET
+				time.Date(2024, 10, 27, 6, 30, 0, 0, time.UTC),
+				time.Date(2024, 10, 27, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// 02:30 happens twice that night; only one of them posts
+			name:    "repeated morning time",
+			morning: "02:30",
+			start:   time.Date(2024, 10, 26, 22, 0, 0, 0, tz),
+			checks: []time.Time{
+				time.Date(2024, 10, 27, 0, 30, 0, 0, time.UTC), // 02:30 CEST
+				time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC), // 02:30 CET
+			},
+		},
+		{
+			// Six quiet hours are six real hours, even with an hour skipped in between:
+			// quiet since 23:00 CET, the threshold is reached at 06:00 CEST, after the morning
+			// window has passed at 03:30 and before nighttime
+			name:    "inactivity across spring forward",
+			morning: "03:00",
+			start:   time.Date(2024, 3, 30, 23, 0, 0, 0, tz),
+			checks: []time.Time{
+				time.Date(2024, 3, 31, 3, 30, 0, 0, time.UTC), // 05:30 CEST, 5½ hours
+				time.Date(2024, 3, 31, 4, 0, 0, 0, time.UTC),  // 06:00 CEST, 6 hours
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newSchedulerTest(t, "Europe/Oslo", tt.start)
+			st.state.morningTime = timeOfDay(tt.morning)
+			for i, check := range tt.checks {
+				want := 0
+				if i == len(tt.checks)-1 {
+					want = 1
+				}
+				if posted := st.checkAt(check); posted != want {
+					t.Fatalf("posted %d at %s, want %d", posted, check.In(tz), want)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerPostsOncePerDay(t *testing.T) {
+	morning := time.Date(2024, 5, 17, 6, 10, 0, 0, time.UTC) // 08:10 in Oslo
+	st := newSchedulerTest(t, "Europe/Oslo", morning.Add(-12*time.Hour))
+	st.checkAt(morning)
+	st.checkAt(morning.Add(10 * time.Minute))
+	if sent := st.client.SentTo(generalChannel); len(sent) != 1 {
+		t.Fatalf("posted %d daily questions, want 1", len(sent))
+	}
+
+	stored, _ := st.db.GetSchedulerState()
+	posted := st.client.SentTo(generalChannel)[0]
+	if stored.LastPostDay != "2024-05-17" || stored.LastQuestionID == nil ||
+		stored.LastMessageID == nil || *stored.LastMessageID != posted.ID || !stored.LastActivityAt.Equal(morning) {
+		t.Fatalf("stored state = %+v", stored)
+	}
+
+	// After a restart the day is still taken, and the inactivity window continues
+	st.clock.Set(morning.Add(20 * time.Minute))
+	st.restart("Europe/Oslo")
+	if !st.bot.Activity.LastActivity().Equal(morning) {
+		t.Errorf("restored last activity = %v, want %v", st.bot.Activity.LastActivity(), morning)
+	}
+	if st.checkAt(morning.Add(20*time.Minute))+st.checkAt(morning.Add(7*time.Hour)) != 0 {
+		t.Fatalf("posted again after a restart")
+	}
+
+	// The next morning posts again
+	if posted := st.checkAt(morning.Add(24 * time.Hour)); posted != 1 {
+		t.Fatalf("posted %d daily questions the next day, want 1", posted)
+	}
+}
+
+func TestSchedulerRestartKeepsQuietTime(t *testing.T) {
+	tz := oslo(t)
+	st := newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 17, 9, 0, 0, 0, tz))
+	st.bot.Activity.Record(generalChannel, "kari", time.Date(2024, 5, 17, 10, 0, 0, 0, tz))
+	st.checkAt(time.Date(2024, 5, 17, 12, 0, 0, 0, tz))
+
+	// A deploy at 13:00 must not restart the six hours, which end at 16:00
+	st.clock.Set(time.Date(2024, 5, 17, 13, 0, 0, 0, tz))
+	st.restart("Europe/Oslo")
+	if posted := st.checkAt(time.Date(2024, 5, 17, 16, 0, 0, 0, tz)); posted != 1 {
+		t.Fatalf("posted %d at 16:00 after a restart, want 1", posted)
+	}
+}
+
+func TestSchedulerDayInTimezone(t *testing.T) {
+	// 08:10 on the 18th in Auckland is still the 17th in UTC
+	morning := time.Date(2024, 5, 17, 20, 10, 0, 0, time.UTC)
+	st := newSchedulerTest(t, "Pacific/Auckland", morning.Add(-time.Hour))
+	if posted := st.checkAt(morning); posted != 1 {
+		t.Fatalf("posted %d daily questions, want 1", posted)
+	}
+	if stored, _ := st.db.GetSchedulerState(); stored.LastPostDay != "2024-05-18" {
+		t.Errorf("posted on %q, want the day in Auckland", stored.LastPostDay)
+	}
+}
+
+func TestSchedulerFallback(t *testing.T) {
+	tz := oslo(t)
+	st := newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 17, 12, 0, 0, 0, tz))
+	fallback := func(now time.Time) int {
+		st.clock.Set(now)
+		before := len(st.client.SentTo(generalChannel))
+		fallbackDailyQuestion(st.bot, st.state)
+		return len(st.client.SentTo(generalChannel)) - before
+	}
+
+	if posted := fallback(time.Date(2024, 5, 17, 21, 0, 0, 0, tz)); posted != 0 {
+		t.Fatalf("fallback posted after nighttime")
+	}
+	noon := time.Date(2024, 5, 18, 12, 0, 0, 0, tz)
+	if posted := fallback(noon) + fallback(noon.Add(time.Hour)) + st.checkAt(noon.Add(7*time.Hour)); posted != 1 {
+		t.Fatalf("posted %d daily questions, want 1", posted)
+	}
+}
+
+func TestSchedulerFailedPostReleasesDay(t *testing.T) {
+	tz := oslo(t)
+	st := newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 17, 7, 0, 0, 0, tz))
+
+	st.client.Errors["ChannelMessageSend"] = errors.New("discord is down")
+	if posted := st.checkAt(time.Date(2024, 5, 17, 8, 0, 0, 0, tz)); posted != 0 {
+		t.Fatalf("posted %d while sending fails", posted)
+	}
+	if stored, _ := st.db.GetSchedulerState(); stored.LastPostDay != "" {
+		t.Fatalf("day kept after a failed post: %+v", stored)
+	}
+
+	// Once Discord is back, the fallback still posts that day, and only once
+	delete(st.client.Errors, "ChannelMessageSend")
+	st.clock.Set(time.Date(2024, 5, 17, 12, 0, 0, 0, tz))
+	fallbackDailyQuestion(st.bot, st.state)
+	fallbackDailyQuestion(st.bot, st.state)
+	if sent := st.client.SentTo(generalChannel); len(sent) != 1 {
+		t.Fatalf("fallback posted %d daily questions, want 1", len(sent))
+	}
+	if stored, _ := st.db.GetSchedulerState(); stored.LastPostDay != "2024-05-17" || stored.LastMessageID == nil {
+		t.Fatalf("stored state = %+v", stored)
+	}
+}
+
+func TestRegisterDailyQuestionJobs(t *testing.T) {
+	tests := []struct {
+		cron string
+		want []string
+	}{
+		{"", []string{dailyQuestionJob}},
+		{"0 8 * * *", []string{dailyQuestionJob, fallbackJob}},
+		{"kvar morgon", []string{dailyQuestionJob}},
+	}
+	for _, tt := range tests {
+		st := newSchedulerTest(t, "Europe/Oslo", time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC))
+		st.bot.Config.Scheduler.Enabled = true
+		st.bot.Config.Scheduler.CronString = tt.cron
+		jobs := scheduler.New(st.db, time.UTC)
+		registerDailyQuestionJobs(st.bot, jobs)
+
+		var names []string
+		for _, job := range jobs.Jobs() {
+			names = append(names, job.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("cron_string %q: jobs = %v, want %v", tt.cron, names, tt.want)
+		}
+	}
+}
+
+func TestPruneWarningsJob(t *testing.T) {
+	db := database.NewMemory()
+	db.AddWarning("original", generalChannel, "warning", generalChannel)
+	clk := clock.NewFake(time.Now())
+	jobs := scheduler.New(db, time.UTC)
+	registerCleanupJobs(bot.New(&config.Config{}, db, nil), jobs, clk)
+
+	// The cut-off follows the clock, not the time the job happens to run
+	clk.Set(time.Now().Add(warningsRetention - time.Hour))
+	if err := jobs.RunNow(context.Background(), pruneWarningsJob); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if _, err := db.GetWarning("original"); err != nil {
+		t.Fatalf("warning pruned before the retention ran out: %v", err)
+	}
+
+	clk.Set(time.Now().Add(warningsRetention + time.Hour))
+	if err := jobs.RunNow(context.Background(), pruneWarningsJob); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if _, err := db.GetWarning("original"); err == nil {
+		t.Fatalf("warning kept after the retention ran out")
+	}
+}
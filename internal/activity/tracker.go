@@ -0,0 +1,112 @@
+// Package activity keeps track of when people last talked in the default channel, which
+// the scheduler uses to post the daily question after a quiet spell.
+package activity
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultWindow is how long an author counts as recent when no window is configured
+const defaultWindow = 6 * time.Hour
+
+// Tracker records the last activity in one channel. A message only counts as activity
+// once at least MinAuthors different people have written there within the window, so
+// one person talking to themselves does not hold back the daily question. It is safe
+// for concurrent use by the message handlers and the scheduler.
+type Tracker struct {
+	mu         sync.Mutex
+	channelID  string
+	minAuthors int
+	window     time.Duration
+	last       time.Time
+	authors    map[string]time.Time // author ID -> their latest message within the window
+}
+
+// Snapshot is the state of a tracker at one point in time
+type Snapshot struct {
+	ChannelID     string
+	LastActivity  time.Time // zero if nothing has been recorded
+	RecentAuthors int       // distinct authors within Window
+	MinAuthors    int
+	Window        time.Duration
+}
+
+// NewTracker returns a tracker for channelID. minAuthors below 1 counts every message,
+// and a window of zero or less falls back to six hours.
+func NewTracker(channelID string, minAuthors int, window time.Duration) *Tracker {
+	if minAuthors < 1 {
+		minAuthors = 1
+	}
+	if window <= 0 {
+		window = defaultWindow
+	}
+	return &Tracker{
+		channelID:  channelID,
+		minAuthors: minAuthors,
+		window:     window,
+		authors:    make(map[string]time.Time),
+	}
+}
+
+// Record notes a message by authorID in channelID at the given time and reports whether
+// it counted as activity. Messages in other channels are ignored; callers filter out bots.
+func (t *Tracker) Record(channelID, authorID string, at time.Time) bool {
+	if t.channelID == "" || channelID != t.channelID {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if at.After(t.authors[authorID]) {
+		t.authors[authorID] = at
+	}
+	t.prune(at)
+	if len(t.authors) < t.minAuthors {
+		return false
+	}
+	if at.After(t.last) {
+		t.last = at
+	}
+	return true
+}
+
+// Touch moves the last activity forward to at, for the bot's own posts and for state
+// restored after a restart. Earlier times are ignored.
+func (t *Tracker) Touch(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.After(t.last) {
+		t.last = at
+	}
+}
+
+// LastActivity returns the time of the last activity, or the zero time if there has been none
+func (t *Tracker) LastActivity() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// Snapshot returns the tracker's state as of now
+func (t *Tracker) Snapshot(now time.Time) Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prune(now)
+	return Snapshot{
+		ChannelID:     t.channelID,
+		LastActivity:  t.last,
+		RecentAuthors: len(t.authors),
+		MinAuthors:    t.minAuthors,
+		Window:        t.window,
+	}
+}
+
+// prune forgets authors whose latest message is older than the window; t.mu must be held
+func (t *Tracker) prune(now time.Time) {
+	for author, at := range t.authors {
+		if now.Sub(at) > t.window {
+			delete(t.authors, author)
+		}
+	}
+}
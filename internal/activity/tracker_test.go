@@ -0,0 +1,81 @@
+package activity
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 5, 17, 10, 0, 0, 0, time.UTC)
+
+func TestRecord(t *testing.T) {
+	tracker := NewTracker("general", 0, time.Hour)
+	if !tracker.LastActivity().IsZero() {
+		t.Fatalf("new tracker has activity %v", tracker.LastActivity())
+	}
+	if tracker.Record("other", "kari", start) {
+		t.Errorf("counted a message in another channel")
+	}
+	if !tracker.Record("general", "kari", start) || !tracker.LastActivity().Equal(start) {
+		t.Errorf("last activity = %v, want %v", tracker.LastActivity(), start)
+	}
+
+	// Messages arriving out of order never move the last activity back
+	tracker.Record("general", "ola", start.Add(-time.Minute))
+	if !tracker.LastActivity().Equal(start) {
+		t.Errorf("last activity moved back to %v", tracker.LastActivity())
+	}
+}
+
+func TestRecordMinAuthors(t *testing.T) {
+	tracker := NewTracker("general", 2, time.Hour)
+	if tracker.Record("general", "kari", start) || tracker.Record("general", "kari", start.Add(time.Minute)) {
+		t.Fatalf("one author counted as activity")
+	}
+	if !tracker.Record("general", "ola", start.Add(2*time.Minute)) {
+		t.Fatalf("a second author did not count as activity")
+	}
+	if got := tracker.LastActivity(); !got.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("last activity = %v", got)
+	}
+
+	// Once kari falls out of the window, ola alone is not enough
+	later := start.Add(90 * time.Minute)
+	if tracker.Record("general", "ola", later) {
+		t.Errorf("counted activity with one recent author")
+	}
+	if snapshot := tracker.Snapshot(later); snapshot.RecentAuthors != 1 || snapshot.MinAuthors != 2 || snapshot.Window != time.Hour {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	tracker := NewTracker("general", 3, 0)
+	tracker.Touch(start)
+	tracker.Touch(start.Add(-time.Hour))
+	snapshot := tracker.Snapshot(start)
+	if !snapshot.LastActivity.Equal(start) || snapshot.RecentAuthors != 0 || snapshot.Window != defaultWindow {
+		t.Errorf("snapshot = %+v", snapshot)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	tracker := NewTracker("general", 1, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tracker.Record("general", fmt.Sprint("user", i), start.Add(time.Duration(j)*time.Second))
+				tracker.LastActivity()
+				tracker.Snapshot(start)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := tracker.LastActivity(); !got.Equal(start.Add(99 * time.Second)) {
+		t.Errorf("last activity = %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package bannedwords
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kind is the kind of banned-word entry, decided by its syntax
+type Kind int
+
+const (
+	KindWord   Kind = iota // ikke
+	KindPhrase             // i forhold til
+	KindPrefix             // forhånds*
+	KindSuffix             // *heten
+	KindRegex              // /hv(a|em)/
+)
+
+// Entry is a parsed banned-word entry
+type Entry struct {
+	Kind Kind
+	Text string // normalised text: lowercase, single spaces, without * or slashes
+
+	re *regexp.Regexp // only for KindRegex
+}
+
+// ParseEntry parses a banned-word entry.
+// A word containing spaces is a phrase, a leading or trailing * makes a suffix or
+// prefix pattern, and /.../ is a regular expression. Regular expressions are anchored:
+// a match must start and end at word boundaries.
+func ParseEntry(s string) (Entry, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/") {
+		pattern := s[1 : len(s)-1]
+		re, err := regexp.Compile(`^(?i:` + pattern + `)`)
+		if err != nil {
+			return Entry{}, fmt.Errorf("ugyldig regulært uttrykk: %w", err)
+		}
+		if re.MatchString("") {
+			return Entry{}, errors.New("det regulære uttrykket kan ikkje treffe tom tekst")
+		}
+		re.Longest()
+		return Entry{Kind: KindRegex, Text: pattern, re: re}, nil
+	}
+
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if text == "" {
+		return Entry{}, errors.New("tomt ord")
+	}
+
+	kind := KindWord
+	switch {
+	case strings.HasPrefix(text, "*") && strings.HasSuffix(text, "*"):
+		return Entry{}, errors.New("bruk * berre i starten eller slutten")
+	case strings.HasPrefix(text, "*"):
+		kind, text = KindSuffix, text[1:]
+	case strings.HasSuffix(text, "*"):
+		kind, text = KindPrefix, text[:len(text)-1]
+	case strings.Contains(text, " "):
+		kind = KindPhrase
+	}
+
+	if kind == KindPrefix || kind == KindSuffix {
+		if text == "" || strings.ContainsAny(text, " *") {
+			return Entry{}, errors.New("eit mønster må vere eitt ord med * i starten eller slutten")
+		}
+	} else if strings.Contains(text, "*") {
+		return Entry{}, errors.New("bruk * berre i starten eller slutten")
+	}
+	return Entry{Kind: kind, Text: text}, nil
+}
+
+// SplitEntries splits a list of entries separated by commas or newlines. A comma inside
+// a regular expression, as in /a{1,3}/, does not split it: an entry starting with /
+// runs to the / that is followed by a separator or the end.
+func SplitEntries(s string) []string {
+	var entries []string
+	for s != "" {
+		s = strings.TrimLeft(s, " \t\r")
+		end := strings.IndexAny(s, ",\n")
+		if strings.HasPrefix(s, "/") {
+			if closing := regexEnd(s); closing > 0 {
+				end = closing
+			}
+		}
+		if end < 0 {
+			end = len(s)
+		}
+		if entry := strings.TrimSpace(s[:end]); entry != "" {
+			entries = append(entries, entry)
+		}
+		if end == len(s) {
+			break
+		}
+		s = s[end+1:]
+	}
+	return entries
+}
+
+// regexEnd returns the index of the separator after a /.../ entry at the start of s,
+// len(s) if it ends s, or -1 if s has no closing / before a separator
+func regexEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] != '/' {
+			continue
+		}
+		rest := strings.TrimLeft(s[i+1:], " \t\r")
+		if rest == "" {
+			return len(s)
+		}
+		if rest[0] == ',' || rest[0] == '\n' {
+			return len(s) - len(rest)
+		}
+	}
+	return -1
+}
+
+// String returns the entry as it is written in the banned-word list
+func (e Entry) String() string {
+	switch e.Kind {
+	case KindPrefix:
+		return e.Text + "*"
+	case KindSuffix:
+		return "*" + e.Text
+	case KindRegex:
+		return "/" + e.Text + "/"
+	}
+	return e.Text
+}
+
+// Literal reports whether the entry matches only its own text
+func (e Entry) Literal() bool {
+	return e.Kind == KindWord || e.Kind == KindPhrase
+}
@@ -0,0 +1,300 @@
+// Package bannedwords keeps the approved banned words in memory so messages can be
+// checked without a database round-trip per word.
+package bannedwords
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"askeladden/internal/database"
+)
+
+// Match is one occurrence of a banned-word entry in a text
+type Match struct {
+	Word      *database.BannedWord
+	Entry     Entry
+	Text      string // the matched text as written
+	Inflected bool   // Text is an inflected form of the entry
+	Start     int    // byte offset of the first character
+	End       int    // byte offset just past the last character
+}
+
+// Label names the match for a warning: the entry itself for words and phrases,
+// and the matched text followed by the lemma or pattern otherwise.
+func (m Match) Label() string {
+	if m.Entry.Literal() && !m.Inflected {
+		return m.Word.Word
+	}
+	return fmt.Sprintf("%s (%s)", m.Text, m.Entry)
+}
+
+// Index holds the approved banned-word entries: words, phrases and their inflected forms
+// in a rune trie keyed by lowercase form, and prefix, suffix and regex patterns in lists
+// tried at every word. It is safe for concurrent use; Reload swaps in new contents atomically.
+type Index struct {
+	mu      sync.RWMutex
+	entries *entries
+	lexicon *Lexicon
+}
+
+type entries struct {
+	root     *node
+	byKey    map[string]*database.BannedWord
+	patterns []pattern
+}
+
+type node struct {
+	children  map[rune]*node
+	word      *database.BannedWord // set if an entry ends here
+	entry     Entry
+	inflected bool
+}
+
+type pattern struct {
+	entry Entry
+	word  *database.BannedWord
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{entries: &entries{root: &node{}, byKey: map[string]*database.BannedWord{}}}
+}
+
+// SetLexicon sets the lexicon that words are inflected with on the next Load
+func (ix *Index) SetLexicon(lex *Lexicon) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.lexicon = lex
+}
+
+// Forms returns the inflected forms a banned word is matched in: those listed on the
+// word followed by those the lexicon gives for its lemma
+func (ix *Index) Forms(bw *database.BannedWord) []string {
+	entry, err := ParseEntry(bw.Word)
+	if err != nil || !entry.Literal() {
+		return nil
+	}
+	ix.mu.RLock()
+	lex := ix.lexicon
+	ix.mu.RUnlock()
+
+	seen := map[string]bool{entry.Text: true}
+	var forms []string
+	add := func(form string) {
+		form = strings.ToLower(strings.Join(strings.Fields(form), " "))
+		if form != "" && !seen[form] {
+			seen[form] = true
+			forms = append(forms, form)
+		}
+	}
+	for _, form := range bw.Forms {
+		add(form)
+	}
+	if entry.Kind == KindWord {
+		for _, form := range lex.Forms(entry.Text) {
+			add(form)
+		}
+	}
+	return forms
+}
+
+// Reload replaces the index with the approved words in the database
+func (ix *Index) Reload(db database.DatabaseIface) error {
+	words, err := db.GetBannedWords()
+	if err != nil {
+		log.Printf("[BANNEDWORDS] Failed to load banned words: %v", err)
+		return err
+	}
+	ix.Load(words)
+	return nil
+}
+
+// Load replaces the index with the given words. Only fully approved words are indexed;
+// entries that do not parse are logged and skipped. Words and phrases also match their
+// inflected forms, unless a form is itself an entry.
+func (ix *Index) Load(words []*database.BannedWord) {
+	e := &entries{root: &node{}, byKey: make(map[string]*database.BannedWord)}
+	var literals []*database.BannedWord
+	for _, bw := range words {
+		if bw.ApprovalStatus != "fully_approved" {
+			continue
+		}
+		entry, err := ParseEntry(bw.Word)
+		if err != nil {
+			log.Printf("[BANNEDWORDS] Skipping banned word %d %q: %v", bw.ID, bw.Word, err)
+			continue
+		}
+		e.byKey[entry.String()] = bw
+		if !entry.Literal() {
+			e.patterns = append(e.patterns, pattern{entry: entry, word: bw})
+			continue
+		}
+
+		n := e.root.insert(entry.Text)
+		n.word, n.entry, n.inflected = bw, entry, false
+		literals = append(literals, bw)
+	}
+
+	forms := 0
+	for _, bw := range literals {
+		entry, _ := ParseEntry(bw.Word)
+		for _, form := range ix.Forms(bw) {
+			if n := e.root.insert(form); n.word == nil {
+				n.word, n.entry, n.inflected = bw, entry, true
+				forms++
+			}
+		}
+	}
+
+	ix.mu.Lock()
+	ix.entries = e
+	ix.mu.Unlock()
+	log.Printf("[BANNEDWORDS] Indexed %d banned words (%d patterns, %d inflected forms)", len(e.byKey), len(e.patterns), forms)
+}
+
+// insert returns the node for key, creating the path to it
+func (n *node) insert(key string) *node {
+	for _, r := range key {
+		child, exists := n.children[r]
+		if !exists {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			child = &node{}
+			n.children[r] = child
+		}
+		n = child
+	}
+	return n
+}
+
+// Len returns the number of indexed entries
+func (ix *Index) Len() int {
+	return len(ix.load().byKey)
+}
+
+func (ix *Index) load() *entries {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.entries
+}
+
+// Lookup returns the banned word whose entry is written as word, ignoring case and spacing
+func (ix *Index) Lookup(word string) (*database.BannedWord, bool) {
+	entry, err := ParseEntry(word)
+	if err != nil {
+		return nil, false
+	}
+	bw, ok := ix.load().byKey[entry.String()]
+	return bw, ok
+}
+
+// Match returns every banned-word entry in text that starts and ends at word boundaries,
+// in order and without overlaps. Phrases match across any run of whitespace. When
+// entries overlap the longest one starting at a position wins, and a word or phrase
+// beats a pattern of the same length.
+func (ix *Index) Match(text string) []Match {
+	e := ix.load()
+
+	var matches []Match
+	prev := rune(0)
+	for start := 0; start < len(text); {
+		r, width := utf8.DecodeRuneInString(text[start:])
+		if !isWordRune(r) || isWordRune(prev) {
+			prev = r
+			start += width
+			continue
+		}
+
+		if found, ok := e.matchAt(text, start); ok {
+			matches = append(matches, found)
+			prev, _ = utf8.DecodeLastRuneInString(text[:found.End])
+			start = found.End
+			continue
+		}
+		prev = r
+		start += width
+	}
+	return matches
+}
+
+// matchAt returns the longest entry matching text from start, which is at a word start
+func (e *entries) matchAt(text string, start int) (Match, bool) {
+	found := Match{End: start}
+	ok := false
+	consider := func(bw *database.BannedWord, entry Entry, inflected bool, end int) {
+		if end > found.End {
+			found = Match{Word: bw, Entry: entry, Text: text[start:end], Inflected: inflected, Start: start, End: end}
+			ok = true
+		}
+	}
+
+	n := e.root
+	for pos := start; pos < len(text); {
+		r, w := utf8.DecodeRuneInString(text[pos:])
+		if unicode.IsSpace(r) {
+			if n = n.children[' ']; n == nil {
+				break
+			}
+			for pos < len(text) {
+				if r, w = utf8.DecodeRuneInString(text[pos:]); !unicode.IsSpace(r) {
+					break
+				}
+				pos += w
+			}
+			continue
+		}
+		if n = n.children[unicode.ToLower(r)]; n == nil {
+			break
+		}
+		pos += w
+		if n.word != nil && atWordEnd(text, pos) {
+			consider(n.word, n.entry, n.inflected, pos)
+		}
+	}
+
+	if len(e.patterns) == 0 {
+		return found, ok
+	}
+	end := start
+	for end < len(text) {
+		r, w := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(r) {
+			break
+		}
+		end += w
+	}
+	token := strings.ToLower(text[start:end])
+	for _, p := range e.patterns {
+		switch p.entry.Kind {
+		case KindPrefix:
+			if strings.HasPrefix(token, p.entry.Text) {
+				consider(p.word, p.entry, false, end)
+			}
+		case KindSuffix:
+			if strings.HasSuffix(token, p.entry.Text) {
+				consider(p.word, p.entry, false, end)
+			}
+		case KindRegex:
+			if loc := p.entry.re.FindStringIndex(text[start:]); loc != nil && atWordEnd(text, start+loc[1]) {
+				consider(p.word, p.entry, false, start+loc[1])
+			}
+		}
+	}
+	return found, ok
+}
+
+// atWordEnd reports whether pos is the end of text or followed by a non-word character
+func atWordEnd(text string, pos int) bool {
+	next, _ := utf8.DecodeRuneInString(text[pos:])
+	return pos == len(text) || !isWordRune(next)
+}
+
+// isWordRune reports whether r can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,243 @@
+package bannedwords
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"askeladden/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func approved(id int, word string) *database.BannedWord {
+	return &database.BannedWord{ID: id, Word: word, ApprovalStatus: "fully_approved"}
+}
+
+func TestMatch(t *testing.T) {
+	ix := NewIndex()
+	ix.Load([]*database.BannedWord{
+		approved(1, "ikke"),
+		approved(2, "hvordan"),
+		approved(3, "ikkeno"),
+		approved(4, "bøker"),
+		{ID: 5, Word: "noe", ApprovalStatus: "pending"},
+		{ID: 6, Word: "mye", ApprovalStatus: "rejected"},
+	})
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Eg veit ikke.", []string{"ikke"}},
+		{"IKKE! Hvordan?", []string{"ikke", "hvordan"}},
+		{"(ikke)", []string{"ikke"}},
+		{"ikkeno å seie", []string{"ikkeno"}},
+		{"ikkje ikkes bikke", nil},
+		{"Dei «bøker» er fine", []string{"bøker"}},
+		{"noe mye", nil},
+		{"ikke ikke", []string{"ikke", "ikke"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, match := range ix.Match(tt.text) {
+			if span := strings.ToLower(tt.text[match.Start:match.End]); span != match.Word.Word {
+				t.Errorf("Match(%q) span %q does not cover %q", tt.text, span, match.Word.Word)
+			}
+			got = append(got, match.Word.Word)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Match(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	if ix.Len() != 4 {
+		t.Errorf("Len = %d, want only the 4 approved words", ix.Len())
+	}
+	if bw, ok := ix.Lookup("Ikke"); !ok || bw.ID != 1 {
+		t.Errorf("Lookup(Ikke) = %+v, %v", bw, ok)
+	}
+	if _, ok := ix.Lookup("ikk"); ok {
+		t.Errorf("Lookup matched a prefix")
+	}
+}
+
+func TestMatchPatterns(t *testing.T) {
+	ix := NewIndex()
+	ix.Load([]*database.BannedWord{
+		approved(1, "ikke"),
+		approved(2, "ikke sant"),
+		approved(3, "i forhold til"),
+		approved(4, "*heten"),
+		approved(5, "forhånds*"),
+		approved(6, "/hv(a|em|or)/"),
+		approved(7, "/ha(r|dde) [a-zæøå]+et/"),
+	})
+
+	tests := []struct {
+		text string
+		want []string // matched text
+	}{
+		{"Ikke  sant?", []string{"Ikke  sant"}},
+		{"ikke santa", []string{"ikke"}},
+		{"Det er bra i forhold\ntil i fjor", []string{"i forhold\ntil"}},
+		{"i forhold", nil},
+		{"Kjærligheten og heten", []string{"Kjærligheten", "heten"}},
+		{"forhåndsvisning", []string{"forhåndsvisning"}},
+		{"Hva? Hvem? Hvordan?", []string{"Hva", "Hvem"}},
+		{"eg har kastet ballen", []string{"har kastet"}},
+		{"eg har kastat", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, match := range ix.Match(tt.text) {
+			if match.Text != tt.text[match.Start:match.End] {
+				t.Errorf("Match(%q) Text %q does not equal its span", tt.text, match.Text)
+			}
+			got = append(got, match.Text)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Match(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	matches := ix.Match("Sannheten er ikke sant")
+	if len(matches) != 2 || matches[0].Label() != "Sannheten (*heten)" || matches[1].Label() != "ikke sant" {
+		t.Errorf("labels = %+v", matches)
+	}
+	if bw, ok := ix.Lookup("Ikke   Sant"); !ok || bw.ID != 2 {
+		t.Errorf("Lookup(Ikke   Sant) = %+v, %v", bw, ok)
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind Kind
+		out  string
+	}{
+		{" Ikke ", KindWord, "ikke"},
+		{"Ikke   sant", KindPhrase, "ikke sant"},
+		{"*Heten", KindSuffix, "*heten"},
+		{"forhånds*", KindPrefix, "forhånds*"},
+		{"/Hv(a|em)/", KindRegex, "/Hv(a|em)/"},
+	}
+	for _, tt := range tests {
+		entry, err := ParseEntry(tt.in)
+		if err != nil || entry.Kind != tt.kind || entry.String() != tt.out {
+			t.Errorf("ParseEntry(%q) = %v %q, %v; want %v %q", tt.in, entry.Kind, entry, err, tt.kind, tt.out)
+		}
+	}
+
+	for _, in := range []string{"", "*", "*heit*", "ik*ke", "*ikke sant", "/(/", "/a*/"} {
+		if _, err := ParseEntry(in); err == nil {
+			t.Errorf("ParseEntry(%q) succeeded", in)
+		}
+	}
+}
+
+func TestSplitEntries(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"ikke, ikke sant,, *heten", []string{"ikke", "ikke sant", "*heten"}},
+		{"ikke\nforhånds*\n", []string{"ikke", "forhånds*"}},
+		{"/a{1,3}h/, ikke", []string{"/a{1,3}h/", "ikke"}},
+		{"ikke, /hv(a|em),? da/ ", []string{"ikke", "/hv(a|em),? da/"}},
+		{"/a, b", []string{"/a", "b"}}, // no closing slash: split as usual
+	}
+	for _, tt := range tests {
+		if got := SplitEntries(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitEntries(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	db := database.NewMemory()
+	id, err := db.AddBannedWordPending("ikke", "", "u1", "ola", "", "")
+	if err != nil {
+		t.Fatalf("AddBannedWordPending: %v", err)
+	}
+
+	ix := NewIndex()
+	if err := ix.Reload(db); err != nil || ix.Len() != 0 {
+		t.Fatalf("Reload with a pending word = %d words, %v", ix.Len(), err)
+	}
+
+	if err := db.ApproveBannedWordCombined(int(id), []string{"o1"}, []string{"r1"}); err != nil {
+		t.Fatalf("ApproveBannedWordCombined: %v", err)
+	}
+	if err := ix.Reload(db); err != nil || len(ix.Match("ikke")) != 1 {
+		t.Fatalf("approved word not matched after Reload: %v", err)
+	}
+
+	db.RemoveBannedWord("ikke")
+	if err := ix.Reload(db); err != nil || len(ix.Match("ikke")) != 0 {
+		t.Fatalf("removed word still matched after Reload: %v", err)
+	}
+}
+
+// benchmarkWords returns n approved words and a 50 word message containing two of them
+func benchmarkWords(n int) ([]*database.BannedWord, string) {
+	words := make([]*database.BannedWord, n)
+	for i := range words {
+		words[i] = approved(i+1, fmt.Sprintf("ord%dikke", i))
+	}
+	message := strings.Repeat("Eg skriv ei lang melding på nynorsk, ", 6) +
+		"men ord1ikke og ord7ikke sneik seg inn. Det er ikkje så farleg, sjølv om det er kjekt å vite!"
+	return words, message
+}
+
+func BenchmarkMatch(b *testing.B) {
+	for _, n := range []int{10, 1000, 10000} {
+		b.Run(fmt.Sprintf("words=%d", n), func(b *testing.B) {
+			words, message := benchmarkWords(n)
+			ix := NewIndex()
+			ix.Load(words)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if len(ix.Match(message)) != 2 {
+					b.Fatal("expected two matches")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkPerTokenLookup is the previous approach, one database lookup per token,
+// against the in-memory store. Against MySQL every lookup is also a network round-trip.
+func BenchmarkPerTokenLookup(b *testing.B) {
+	words, message := benchmarkWords(1000)
+	db := database.NewMemory()
+	for _, bw := range words {
+		db.AddBannedWord(bw.Word, "", "u1")
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, token := range strings.Fields(strings.ToLower(message)) {
+			db.IsBannedWord(strings.Trim(token, ".,!?;:()[]{}\"'"))
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	words, _ := benchmarkWords(10000)
+	ix := NewIndex()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ix.Load(words)
+	}
+}
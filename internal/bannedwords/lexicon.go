@@ -0,0 +1,139 @@
+package bannedwords
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Lexicon maps lemmas to their inflected forms, so banning a lemma flags every form of it
+type Lexicon struct {
+	forms map[string][]string // lowercase lemma -> lowercase forms, without the lemma itself
+}
+
+// LoadLexicon reads a lexicon file, see ParseLexicon for the formats
+func LoadLexicon(path string) (*Lexicon, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lex, err := ParseLexicon(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	log.Printf("[BANNEDWORDS] Loaded lexicon with %d lemmas from %s", lex.Len(), path)
+	return lex, nil
+}
+
+// ParseLexicon reads tab-separated lines of the form "lemma<TAB>form", where further
+// columns and lines starting with # are ignored. A Norsk Ordbank fullformsliste export
+// is recognised by its header (LEMMA_ID, OPPSLAG and BOY_NUMMER columns); there the
+// lemma is the form with BOY_NUMMER 1 and forms are grouped by LEMMA_ID.
+// Homographs share one entry, so banning "tak" flags the forms of every "tak".
+func ParseLexicon(r io.Reader) (*Lexicon, error) {
+	lex := &Lexicon{forms: make(map[string][]string)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var ordbank *ordbankColumns
+	lemmaForms := make(map[string][]string) // Ordbank LEMMA_ID -> forms
+	lemmaNames := make(map[string][]string) // Ordbank LEMMA_ID -> base forms
+
+	first := true
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if first {
+			first = false
+			if ordbank = parseOrdbankHeader(fields); ordbank != nil {
+				continue
+			}
+		}
+
+		if ordbank == nil {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: want lemma and form separated by a tab", lineNo)
+			}
+			lex.add(fields[0], fields[1])
+			continue
+		}
+
+		if len(fields) <= ordbank.max {
+			return nil, fmt.Errorf("line %d: want at least %d columns", lineNo, ordbank.max+1)
+		}
+		id, form := fields[ordbank.lemmaID], fields[ordbank.form]
+		lemmaForms[id] = append(lemmaForms[id], form)
+		if strings.TrimSpace(fields[ordbank.inflection]) == "1" {
+			lemmaNames[id] = append(lemmaNames[id], form)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for id, names := range lemmaNames {
+		for _, lemma := range names {
+			for _, form := range lemmaForms[id] {
+				lex.add(lemma, form)
+			}
+		}
+	}
+	return lex, nil
+}
+
+// ordbankColumns are the column indexes used from a Norsk Ordbank fullformsliste
+type ordbankColumns struct {
+	lemmaID, form, inflection, max int
+}
+
+func parseOrdbankHeader(fields []string) *ordbankColumns {
+	index := make(map[string]int)
+	for i, field := range fields {
+		index[strings.ToUpper(strings.TrimSpace(field))] = i
+	}
+	lemmaID, ok1 := index["LEMMA_ID"]
+	form, ok2 := index["OPPSLAG"]
+	inflection, ok3 := index["BOY_NUMMER"]
+	if !ok1 || !ok2 || !ok3 {
+		return nil
+	}
+	return &ordbankColumns{lemmaID: lemmaID, form: form, inflection: inflection, max: max(lemmaID, form, inflection)}
+}
+
+func (lex *Lexicon) add(lemma, form string) {
+	lemma = strings.ToLower(strings.TrimSpace(lemma))
+	form = strings.ToLower(strings.TrimSpace(form))
+	if lemma == "" || form == "" || form == lemma {
+		return
+	}
+	for _, existing := range lex.forms[lemma] {
+		if existing == form {
+			return
+		}
+	}
+	lex.forms[lemma] = append(lex.forms[lemma], form)
+}
+
+// Forms returns the inflected forms of lemma, not including the lemma itself
+func (lex *Lexicon) Forms(lemma string) []string {
+	if lex == nil {
+		return nil
+	}
+	return lex.forms[strings.ToLower(lemma)]
+}
+
+// Len returns the number of lemmas
+func (lex *Lexicon) Len() int {
+	if lex == nil {
+		return 0
+	}
+	return len(lex.forms)
+}
@@ -0,0 +1,63 @@
+package bannedwords
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"askeladden/internal/database"
+)
+
+func TestLoadLexicon(t *testing.T) {
+	tests := []struct {
+		file, lemma string
+		want        []string
+	}{
+		{"testdata/lexicon.tsv", "Bok", []string{"boka", "boken", "bøker", "bøkene", "bokens"}},
+		{"testdata/lexicon.tsv", "ikke", nil},
+		{"testdata/fullformsliste.txt", "hus", []string{"huset", "husa"}},
+		{"testdata/fullformsliste.txt", "gjøre", []string{"gjør", "gjorde"}},
+		{"testdata/fullformsliste.txt", "huset", nil},
+	}
+	for _, tt := range tests {
+		lex, err := LoadLexicon(tt.file)
+		if err != nil {
+			t.Fatalf("LoadLexicon(%s): %v", tt.file, err)
+		}
+		if got := lex.Forms(tt.lemma); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: Forms(%q) = %v, want %v", tt.file, tt.lemma, got, tt.want)
+		}
+	}
+
+	if _, err := ParseLexicon(strings.NewReader("bok boka\n")); err == nil {
+		t.Errorf("ParseLexicon accepted a line without a tab")
+	}
+	if _, err := LoadLexicon("testdata/missing.tsv"); err == nil {
+		t.Errorf("LoadLexicon accepted a missing file")
+	}
+}
+
+func TestMatchInflectedForms(t *testing.T) {
+	lex, err := LoadLexicon("testdata/lexicon.tsv")
+	if err != nil {
+		t.Fatalf("LoadLexicon: %v", err)
+	}
+	ix := NewIndex()
+	ix.SetLexicon(lex)
+	hvem := approved(3, "hvem")
+	hvem.Forms = []string{"hvems", "Hvem  sin"}
+	ix.Load([]*database.BannedWord{approved(1, "bok"), approved(2, "bøker"), hvem})
+
+	var got []string
+	for _, match := range ix.Match("Bøkene og bokens bøker, hvem sin bok? Hvems!") {
+		got = append(got, match.Label())
+	}
+	want := "[Bøkene (bok) bokens (bok) bøker hvem sin (hvem) bok Hvems (hvem)]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("labels = %v, want %s", got, want)
+	}
+
+	if forms := ix.Forms(hvem); fmt.Sprint(forms) != "[hvems hvem sin]" {
+		t.Errorf("Forms(hvem) = %v", forms)
+	}
+}
@@ -0,0 +1,37 @@
+package bannedwords
+
+import "regexp"
+
+// skipped matches the parts of a Discord message that are not the author's own prose,
+// in the order they are blanked out. Code goes first so nothing inside it is read as
+// markdown; quotes go last so a link or mention with quotes in it is blanked whole.
+var skipped = []*regexp.Regexp{
+	regexp.MustCompile("(?s)```.*?```"),                               // code blocks
+	regexp.MustCompile("(?s)``.+?``"),                                 // inline code with double backticks
+	regexp.MustCompile("`[^`]+`"),                                     // inline code
+	regexp.MustCompile(`(?ms)^>>> .*`),                                // block quote to the end of the message
+	regexp.MustCompile(`(?m)^> .*$`),                                  // quoted line
+	regexp.MustCompile(`<?https?://[^\s>]+>?`),                        // links, also as the target of [text](url)
+	regexp.MustCompile(`<(?:@[!&]?|#)\d+>`),                           // user, role and channel mentions
+	regexp.MustCompile(`<a?:\w+:\d+>`),                                // custom emoji
+	regexp.MustCompile(`<t:-?\d+(?::[tTdDfFR])?>`),                    // timestamps
+	regexp.MustCompile(`</[\w -]+:\d+>`),                              // slash command mentions
+	regexp.MustCompile(`:[\w+-]+:`),                                   // emoji shortcodes
+	regexp.MustCompile(`«[^»\n]*»|“[^”\n]*”|„[^“”\n]*[“”]|"[^"\n]*"`), // words being talked about, not used
+}
+
+// Prose returns content with code, quotes, links, mentions, emoji and quoted words
+// blanked out, so only what the author wrote in their own voice is matched. Blanked
+// bytes are NUL, which is neither a word character nor whitespace, so byte offsets
+// into the result are valid in content and phrases never match across a blanked region.
+func Prose(content string) string {
+	text := []byte(content)
+	for _, re := range skipped {
+		for _, loc := range re.FindAllIndex(text, -1) {
+			for i := loc[0]; i < loc[1]; i++ {
+				text[i] = 0
+			}
+		}
+	}
+	return string(text)
+}
@@ -0,0 +1,76 @@
+package bannedwords
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"askeladden/internal/database"
+)
+
+// corpusCase is one message from testdata/corpus.txt
+type corpusCase struct {
+	want    []string
+	message string
+}
+
+func readCorpus(t *testing.T) []corpusCase {
+	t.Helper()
+	data, err := os.ReadFile("testdata/corpus.txt")
+	if err != nil {
+		t.Fatalf("reading corpus: %v", err)
+	}
+
+	var cases []corpusCase
+	for _, block := range strings.Split(string(data), "=== want:")[1:] {
+		header, message, _ := strings.Cut(block, "\n")
+		var want []string
+		for _, word := range strings.Split(header, ",") {
+			if word = strings.TrimSpace(word); word != "" {
+				want = append(want, word)
+			}
+		}
+		cases = append(cases, corpusCase{want: want, message: strings.TrimRight(message, "\n")})
+	}
+	return cases
+}
+
+func TestProseCorpus(t *testing.T) {
+	ix := NewIndex()
+	ix.Load([]*database.BannedWord{
+		approved(1, "ikke"),
+		approved(2, "noe"),
+		approved(3, "ikke sant"),
+		approved(4, "*heten"),
+		approved(5, "hvordan"),
+	})
+
+	cases := readCorpus(t)
+	if len(cases) < 15 {
+		t.Fatalf("corpus has %d cases", len(cases))
+	}
+	for _, tc := range cases {
+		prose := Prose(tc.message)
+		if len(prose) != len(tc.message) {
+			t.Fatalf("Prose changed the length of %q", tc.message)
+		}
+		var got []string
+		for _, match := range ix.Match(prose) {
+			if match.Text != tc.message[match.Start:match.End] {
+				t.Errorf("span %d-%d of %q is %q, not %q", match.Start, match.End, tc.message, tc.message[match.Start:match.End], match.Text)
+			}
+			got = append(got, strings.ReplaceAll(match.Text, "  ", " "))
+		}
+		if strings.Join(got, ", ") != strings.Join(tc.want, ", ") {
+			t.Errorf("message:\n%s\nmatched %q, want %q", tc.message, got, tc.want)
+		}
+	}
+}
+
+func TestProse(t *testing.T) {
+	content := "sjå `ikke` og <@1> her"
+	want := "sjå \x00\x00\x00\x00\x00\x00 og \x00\x00\x00\x00 her"
+	if got := Prose(content); got != want {
+		t.Errorf("Prose(%q) = %q, want %q", content, got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package bannedwords
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxWordlistRows is the most words one wordlist may import
+const MaxWordlistRows = 1000
+
+// WordlistRow is one word to import
+type WordlistRow struct {
+	Line         int
+	Word         string // normalised entry, see Entry.String
+	Replacements []string
+	Reason       string
+}
+
+// Wordlist is a parsed wordlist file
+type Wordlist struct {
+	Rows     []WordlistRow
+	Invalid  []string // "linje 3: tomt ord", one per row that could not be read
+	Repeated []string // words listed more than once; only the first row is kept
+}
+
+const byteOrderMark = "\xef\xbb\xbf"
+
+// wordlistHeaders are first cells that mark the first line as a header
+var wordlistHeaders = []string{"word", "ord", "bokmål", "bokmal"}
+
+// ParseWordlist reads a spreadsheet export with one word per line and the columns
+// word, replacement and reason, where only the word is required. Columns are separated
+// by tabs, semicolons or commas, whichever the first line with columns uses; a header line and
+// lines starting with # are skipped. Replacements are split like SplitReplacements.
+func ParseWordlist(r io.Reader) (*Wordlist, error) {
+	buffered := bufio.NewReader(r)
+	// Spreadsheets often save UTF-8 with a byte order mark
+	if bom, _ := buffered.Peek(len(byteOrderMark)); string(bom) == byteOrderMark {
+		buffered.Discard(len(byteOrderMark))
+	}
+	sample, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = wordlistDelimiter(string(sample))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	// Leading space would also swallow empty tab-separated cells
+	reader.TrimLeadingSpace = reader.Comma != '\t'
+
+	list := &Wordlist{}
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("kunne ikkje lese fila: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(i int) string {
+			if i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if len(list.Rows) == 0 && len(list.Invalid) == 0 && isWordlistHeader(cell(0)) {
+			continue
+		}
+
+		entry, err := ParseEntry(cell(0))
+		if err != nil {
+			list.Invalid = append(list.Invalid, fmt.Sprintf("linje %d: %v", line, err))
+			continue
+		}
+		word := entry.String()
+		if seen[word] {
+			list.Repeated = append(list.Repeated, word)
+			continue
+		}
+		if len(list.Rows) == MaxWordlistRows {
+			return nil, fmt.Errorf("fila har meir enn %d ord", MaxWordlistRows)
+		}
+		seen[word] = true
+		list.Rows = append(list.Rows, WordlistRow{
+			Line:         line,
+			Word:         word,
+			Replacements: SplitReplacements(cell(1)),
+			Reason:       cell(2),
+		})
+	}
+	return list, nil
+}
+
+// wordlistDelimiter picks the column separator from the first line of the sample that
+// has one, skipping comments and quoted text, so that a separator inside a reason does
+// not decide it. When counts tie, tabs win over semicolons and semicolons over commas.
+func wordlistDelimiter(sample string) rune {
+	for _, line := range strings.Split(sample, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		counts := make(map[rune]int)
+		quoted := false
+		for _, r := range line {
+			switch {
+			case r == '"':
+				quoted = !quoted
+			case !quoted:
+				counts[r]++
+			}
+		}
+		best := rune(0)
+		for _, candidate := range []rune{'\t', ';', ','} {
+			if counts[candidate] > counts[best] {
+				best = candidate
+			}
+		}
+		if best != 0 {
+			return best
+		}
+	}
+	return ','
+}
+
+func isWordlistHeader(cell string) bool {
+	for _, header := range wordlistHeaders {
+		if strings.EqualFold(cell, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitReplacements splits alternatives written as "noko / noka" or "noko, noka"
+func SplitReplacements(text string) []string {
+	var replacements []string
+	for _, replacement := range strings.FieldsFunc(text, func(r rune) bool { return r == '/' || r == ',' }) {
+		if replacement = strings.Join(strings.Fields(replacement), " "); replacement != "" {
+			replacements = append(replacements, replacement)
+		}
+	}
+	return replacements
+}
@@ -0,0 +1,71 @@
+package bannedwords
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseWordlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		want     string // word [replacements] reason, one per row
+		invalid  []string
+		repeated []string
+	}{
+		{
+			name: "tsv with header",
+			file: "ord\trett form\tgrunn\nIkke\tikkje\tBokmål\nhvordan\tkorleis / kossen\n",
+			want: "ikke [ikkje] Bokmål|hvordan [korleis kossen] ",
+		},
+		{
+			name: "csv with quotes and comments",
+			file: "\xef\xbb\xbf# eksportert frå rekneark\nnoe,\"noko, noka\",\"Bokmål, sjå «noko»\"\n\nikke sant,\n",
+			want: "noe [noko noka] Bokmål, sjå «noko»|ikke sant [] ",
+		},
+		{
+			name: "semicolons from a spreadsheet",
+			file: "bokmål;nynorsk\r\nmye;mykje\r\n*heten;*heita\r\n",
+			want: "mye [mykje] |*heten [*heita] ",
+		},
+		{
+			name: "semicolon inside a comma separated reason",
+			file: "# grunn; valfri\nikke,ikkje,Bokmål; skriv ikkje\nnoe,noko,\n",
+			want: "ikke [ikkje] Bokmål; skriv ikkje|noe [noko] ",
+		},
+		{
+			name:     "invalid and repeated rows",
+			file:     "ikke\n\tikkje\n*ikk*\nIKKE\tikkje\nnoe\n",
+			want:     "ikke [] |noe [] ",
+			invalid:  []string{"linje 2: tomt ord", "linje 3: bruk * berre i starten eller slutten"},
+			repeated: []string{"ikke"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := ParseWordlist(strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("ParseWordlist: %v", err)
+			}
+			var rows []string
+			for _, row := range list.Rows {
+				rows = append(rows, fmt.Sprintf("%s %v %s", row.Word, row.Replacements, row.Reason))
+			}
+			if got := strings.Join(rows, "|"); got != tt.want {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+			if fmt.Sprint(list.Invalid) != fmt.Sprint(tt.invalid) || fmt.Sprint(list.Repeated) != fmt.Sprint(tt.repeated) {
+				t.Errorf("invalid = %q, repeated = %q; want %q, %q", list.Invalid, list.Repeated, tt.invalid, tt.repeated)
+			}
+		})
+	}
+
+	var long strings.Builder
+	for i := 0; i <= MaxWordlistRows; i++ {
+		fmt.Fprintf(&long, "ord%d\n", i)
+	}
+	if _, err := ParseWordlist(strings.NewReader(long.String())); err == nil {
+		t.Errorf("ParseWordlist accepted %d words", MaxWordlistRows+1)
+	}
+}
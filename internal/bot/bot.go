@@ -2,26 +2,63 @@ package bot
 
 import (
 	"log"
+	"time"
 
+	"askeladden/internal/activity"
+	"askeladden/internal/bannedwords"
 	"askeladden/internal/config"
 	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/scheduler"
 	"github.com/bwmarrin/discordgo"
 )
 
 // Bot represents the main bot structure.
+// Session is the gateway connection; everything else talks to Discord through Discord,
+// which tests replace with a fake. BannedWords mirrors the approved banned words in
+// Database and must be reloaded whenever they change. Activity is fed by messages in
+// the default channel and read by the scheduler. Jobs is nil until the scheduler starts.
+// Background work runs under Context and stops when Shutdown cancels it.
 type Bot struct {
-	Session  *discordgo.Session
-	Config   *config.Config
-	Database *database.DB
+	Session     *discordgo.Session
+	Discord     discord.Client
+	Config      *config.Config
+	Database    database.DatabaseIface
+	BannedWords *bannedwords.Index
+	Activity    *activity.Tracker
+	Jobs        *scheduler.Scheduler
+
+	lifecycle *lifecycle
 }
 
 // New creates a new Bot instance.
-func New(cfg *config.Config, db *database.DB, session *discordgo.Session) *Bot {
-	return &Bot{
-		Session:  session,
-		Config:   cfg,
-		Database: db,
+func New(cfg *config.Config, db database.DatabaseIface, session *discordgo.Session) *Bot {
+	b := &Bot{
+		Session:     session,
+		Config:      cfg,
+		Database:    db,
+		BannedWords: bannedwords.NewIndex(),
+		Activity:    activity.NewTracker("", 0, 0),
+		lifecycle:   newLifecycle(),
+	}
+	if session != nil {
+		b.Discord = discord.Wrap(session)
+	}
+	if cfg != nil {
+		b.Activity = activity.NewTracker(cfg.Discord.DefaultChannelID, cfg.Scheduler.ActivityMinAuthors,
+			time.Duration(cfg.Scheduler.InactivityHours)*time.Hour)
 	}
+	if cfg != nil && cfg.BannedWords.LexiconFile != "" {
+		lex, err := bannedwords.LoadLexicon(cfg.BannedWords.LexiconFile)
+		if err != nil {
+			log.Printf("[BOT] Failed to load lexicon, inflected forms will not be matched: %v", err)
+		}
+		b.BannedWords.SetLexicon(lex)
+	}
+	if db != nil {
+		b.BannedWords.Reload(db)
+	}
+	return b
 }
 
 // Start starts the bot.
@@ -49,8 +86,11 @@ func (b *Bot) Stop() error {
 		b.Database.Close()
 	}
 
+	if b.Session == nil {
+		return nil
+	}
 	return b.Session.Close()
 }
 
 // Note: Direct field access is preferred in Go for simplicity
-// Bot fields (Session, Config, Database) are exported for direct access
+// Bot fields (Session, Discord, Config, Database, BannedWords) are exported for direct access
@@ -1,22 +1,30 @@
 package handlers
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"askeladden/internal/bannedwords"
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
 	"askeladden/internal/commands"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
 	"askeladden/internal/reactions"
 	"github.com/bwmarrin/discordgo"
 )
 
 // Handler struct holds the bot instance and services.
+// Event methods ignore the session discordgo passes in and talk to Discord
+// through Bot.Discord, so tests can drive them with a fake client. Each one is
+// tracked with Bot.Track, so shutdown waits for it and drops events arriving later.
 type Handler struct {
-	Bot            *bot.Bot
-	Services       *services.BotServices
-	warnedChannels map[string]bool
+	Bot      *bot.Bot
+	Services *services.BotServices
+	warnings *warningThrottle
 }
 
 // New creates a new Handler instance.
@@ -29,27 +37,64 @@ func New(b *bot.Bot) *Handler {
 	return &Handler{
 		Bot:      b,
 		Services: botServices,
+		warnings: newWarningThrottle(),
 	}
 }
 
 // Ready handles the ready event.
-func (h *Handler) Ready(s *discordgo.Session, event *discordgo.Ready) {
+func (h *Handler) Ready(_ *discordgo.Session, event *discordgo.Ready) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
 	log.Println("[BOT] Askeladden is connected and ready.")
 	if h.Bot.Config.Discord.LogChannelID != "" {
 		embed := services.CreateBotEmbed(s, "🟢 Online", "Askeladden is online and ready! ✨", services.EmbedTypeSuccess)
 		s.ChannelMessageSendEmbed(h.Bot.Config.Discord.LogChannelID, embed)
 	}
+
+	// Reconcile slash commands with the command registry on every connect
+	var appID string
+	switch {
+	case event.Application != nil && event.Application.ID != "":
+		appID = event.Application.ID
+	case event.User != nil:
+		appID = event.User.ID // a bot's application ID is its user ID
+	default:
+		log.Printf("[BOT] Ready event without application, skipping slash command registration")
+		return
+	}
+	if err := commands.RegisterApplicationCommands(s, appID); err != nil {
+		log.Printf("[BOT] %v", err)
+	}
 }
 
 // MessageCreate handles new messages.
-func (h *Handler) MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+func (h *Handler) MessageCreate(_ *discordgo.Session, m *discordgo.MessageCreate) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
 	// Ignore all messages created by the bot itself
-	if s.State.User.ID == m.Author.ID {
+	if discord.IsSelf(s, m.Author.ID) {
 		return
 	}
 
 	log.Printf("[DEBUG] Received message: '%s', prefix: '%s'", m.Content, h.Bot.Config.Discord.Prefix)
 
+	// Any message from a person counts towards keeping the channel active, commands too
+	if !m.Author.Bot {
+		at := m.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		h.Bot.Activity.Record(m.ChannelID, m.Author.ID, at)
+	}
+
 	// Handle commands (messages with prefix)
 	if strings.HasPrefix(m.Content, h.Bot.Config.Discord.Prefix) {
 		// Extract command and arguments
@@ -71,16 +116,55 @@ func (h *Handler) MessageCreate(s *discordgo.Session, m *discordgo.MessageCreate
 		return
 	}
 
-	// Handle non-command messages (replies, etc.)
-	h.handleNonCommandMessage(s, m)
-
 	// Check for banned words in the message
-	h.checkForBannedWords(s, m)
+	h.checkForBannedWords(s, m.Message)
+}
+
+// MessageUpdate re-checks edited messages, so fixing a banned word clears the warning
+// and editing one in is still caught.
+func (h *Handler) MessageUpdate(_ *discordgo.Session, m *discordgo.MessageUpdate) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
+	// Partial updates, e.g. for link previews, may come without an author
+	if m.Author == nil || discord.IsSelf(s, m.Author.ID) {
+		return
+	}
+	if strings.HasPrefix(m.Content, h.Bot.Config.Discord.Prefix) {
+		return
+	}
+	h.recheckBannedWords(s, m.Message)
+}
+
+// MessageDelete removes the warning about a deleted message
+func (h *Handler) MessageDelete(_ *discordgo.Session, m *discordgo.MessageDelete) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	warning, err := h.Bot.Database.GetWarning(m.ID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to look up warning for deleted message %s: %v", m.ID, err)
+		}
+		return
+	}
+	h.removeWarning(h.Bot.Discord, warning)
 }
 
 // ReactionAdd handles when a user reacts to a message.
-func (h *Handler) ReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
-	if r.UserID == s.State.User.ID {
+func (h *Handler) ReactionAdd(_ *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
+	if discord.IsSelf(s, r.UserID) {
 		return
 	}
 
@@ -102,8 +186,14 @@ func (h *Handler) ReactionAdd(s *discordgo.Session, r *discordgo.MessageReaction
 }
 
 // ReactionRemove handles when a user removes a reaction from a message.
-func (h *Handler) ReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
-	if r.UserID == s.State.User.ID {
+func (h *Handler) ReactionRemove(_ *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
+	if discord.IsSelf(s, r.UserID) {
 		return
 	}
 
@@ -118,228 +208,100 @@ func (h *Handler) ReactionRemove(s *discordgo.Session, r *discordgo.MessageReact
 	reactions.MatchAndRunReactionRemove(r.Emoji.Name, s, r, h.Bot)
 }
 
-// promptForIncorrectWord prompts the user to provide the incorrect word(s)
-func (h *Handler) promptForIncorrectWord(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
-	log.Printf("User %s reported an incorrect word in message %s", r.UserID, r.MessageID)
-	_, err := s.ChannelMessage(r.ChannelID, r.MessageID)
-	if err != nil {
-		log.Printf("Error fetching message: %v", err)
-		return
-	}
+// promptForIncorrectWord points a user who hammered a message to the report command
+func (h *Handler) promptForIncorrectWord(s discord.Client, r *discordgo.MessageReactionAdd) {
+	log.Printf("User %s hammered message %s", r.UserID, r.MessageID)
 
-	// Store the original hammered message info in the prompt for later reference
 	promptEmbed := services.NewEmbedBuilder().
 		SetTitle("🚨 Rapporter feil ord").
-		SetDescription(fmt.Sprintf("Ver snill og svar med ord som er feil, skilde med komma viss det er fleire.\n\n[Hopp til opphavleg melding](https://discord.com/channels/%s/%s/%s)", r.GuildID, r.ChannelID, r.MessageID)).
+		SetDescription(fmt.Sprintf("<@%s>, høgreklikk på meldinga og vel **Appar → %s** for å rapportere ord som er feil.", r.UserID, commands.ReportWordCommandName)).
 		SetColorByType(services.EmbedTypeError).
 		Build()
 
 	s.ChannelMessageSendEmbed(r.ChannelID, promptEmbed)
 }
 
-// handleNonCommandMessage processes non-command messages like replies
-func (h *Handler) handleNonCommandMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
-	log.Printf("[DEBUG] Processing non-command message: %s", m.Content)
-
-	// Check if this is a reply to a bot message (indicating user is responding to hammer emoji prompt)
-	if m.ReferencedMessage != nil && m.ReferencedMessage.Author.ID == s.State.User.ID {
-		log.Printf("[DEBUG] User %s replied to bot message with: %s", m.Author.ID, m.Content)
-
-		// Check if the referenced message was a "Rapporter feil ord" prompt
-		if len(m.ReferencedMessage.Embeds) > 0 &&
-			(strings.Contains(m.ReferencedMessage.Embeds[0].Title, "Report Incorrect Word") || strings.Contains(m.ReferencedMessage.Embeds[0].Title, "Rapporter feil ord")) {
-			h.processIncorrectWordReport(s, m)
-		}
+// checkForBannedWords checks if a message contains banned words and shows warnings.
+// Code, quotes, links, mentions and quoted words are not checked, and warnings are
+// throttled per user and channel and delivered the way the author chose with ?rettleiing.
+func (h *Handler) checkForBannedWords(s discord.Client, m *discordgo.Message) {
+	if !h.detectsIn(s, m.ChannelID) {
+		return
 	}
-}
-
-// processIncorrectWordReport processes the user's response to the incorrect word prompt
-func (h *Handler) processIncorrectWordReport(s *discordgo.Session, m *discordgo.MessageCreate) {
-	log.Printf("[DEBUG] Processing incorrect word report from user %s: %s", m.Author.ID, m.Content)
 
-	// Parse the words from the message (comma-separated)
-	words := strings.Split(m.Content, ",")
-
-	for i, word := range words {
-		words[i] = strings.TrimSpace(word)
+	hits, forumThreads := h.findBannedWords(m)
+	if len(hits) == 0 {
+		return
 	}
 
-	// Filter out empty words
-	var validWords []string
-	for _, word := range words {
-		if word != "" {
-			validWords = append(validWords, word)
-		}
+	mode, err := h.Bot.Database.GetWarningMode(m.Author.ID)
+	if err != nil {
+		log.Printf("Failed to get warning mode, warning in channel: %v", err)
 	}
-
-	if len(validWords) == 0 {
-		log.Printf("No valid words found in report")
+	if mode == database.WarningModeOff {
 		return
 	}
 
-	// Extract original hammered message info from the embed description jump link
-	originalChannelID := ""
-	originalMessageID := ""
-	if m.ReferencedMessage != nil && len(m.ReferencedMessage.Embeds) > 0 {
-		// Parse jump link from description: https://discord.com/channels/GUILD_ID/CHANNEL_ID/MESSAGE_ID
-		description := m.ReferencedMessage.Embeds[0].Description
-
-		// Look for Discord jump link pattern
-		if strings.Contains(description, "discord.com/channels/") {
-			// Find the URL in the description
-			startIdx := strings.Index(description, "https://discord.com/channels/")
-			if startIdx != -1 {
-				// Extract everything after the base URL
-				urlPart := description[startIdx+len("https://discord.com/channels/"):]
-				// Find the end of the URL (usually a closing parenthesis for markdown link)
-				endIdx := strings.Index(urlPart, ")")
-				if endIdx != -1 {
-					urlPart = urlPart[:endIdx]
-				}
-
-				// Split by / to get guild/channel/message IDs
-				parts := strings.Split(urlPart, "/")
-				if len(parts) == 3 {
-					// parts[0] = guild_id, parts[1] = channel_id, parts[2] = message_id
-					originalChannelID = parts[1]
-					originalMessageID = parts[2]
-				}
-			}
-		}
-
-		// Fallback: try to parse from footer if jump link parsing failed
-		if originalChannelID == "" && originalMessageID == "" && m.ReferencedMessage.Embeds[0].Footer != nil {
-			// Parse footer text: "CHANNEL_ID|MESSAGE_ID" or old format "Channel: CHANNEL_ID | Message: MESSAGE_ID"
-			footerText := m.ReferencedMessage.Embeds[0].Footer.Text
-			if strings.Contains(footerText, "|") && !strings.Contains(footerText, " | ") {
-				// New format: "CHANNEL_ID|MESSAGE_ID"
-				parts := strings.Split(footerText, "|")
-				if len(parts) == 2 {
-					originalChannelID = parts[0]
-					originalMessageID = parts[1]
-				}
-			} else if strings.Contains(footerText, " | ") {
-				// Old format: "Channel: CHANNEL_ID | Message: MESSAGE_ID"
-				footerParts := strings.Split(footerText, " | ")
-				if len(footerParts) == 2 {
-					if strings.HasPrefix(footerParts[0], "Channel: ") {
-						originalChannelID = strings.TrimPrefix(footerParts[0], "Channel: ")
-					}
-					if strings.HasPrefix(footerParts[1], "Message: ") {
-						originalMessageID = strings.TrimPrefix(footerParts[1], "Message: ")
-					}
-				}
-			}
-		}
+	throttledChannel := m.ChannelID
+	if mode == database.WarningModeDM {
+		throttledChannel = ""
 	}
-	log.Printf("Extracted original message info - Channel: %s, Message: %s", originalChannelID, originalMessageID)
-
-	// Create a variable to hold the thread, but don't create it yet
-	// thread := h.Services.Approval.PostBannedWordReport(s, validWords, m.Author.ID, originalMessageID)
-
-	// Add words to database and update with forum thread ID if one was created
-	var newWords []string
-	var existingWords []string
-
-	for _, word := range validWords {
-		// Check if word already exists
-		isBanned, _, err := h.Bot.Database.IsBannedWord(word)
-		if err != nil {
-			log.Printf("Error checking if word '%s' exists: %v", word, err)
-			continue
-		}
-
-		if isBanned {
-			existingWords = append(existingWords, word)
-			// Update existing word with forum thread ID if we created one
-			// if thread != nil {
-			// 	err = h.Bot.Database.UpdateBannedWordThread(word, thread.ID)
-			// 	if err != nil {
-			// 		log.Printf("Error updating forum thread for word '%s': %v", word, err)
-			// 	}
-			// }
-		} else {
-			newWords = append(newWords, word)
-			// Add new word as pending approval with forum thread ID if we created one
-			// if thread != nil {
-			// 	forumThreadID = thread.ID
-			// }
-
-			wordID, err := h.Bot.Database.AddBannedWordPending(word, "Reported via hammer emoji", m.Author.ID, m.Author.Username, "", fmt.Sprintf("%s|%s", originalChannelID, originalMessageID))
-			if err != nil {
-				log.Printf("Error adding pending banned word '%s': %v", word, err)
-			} else {
-				log.Printf("Added pending banned word: %s with ID %d", word, wordID)
-				// Post to retting channel for approval
-				h.Services.Approval.PostPendingBannedWordToRettingChannel(wordID)
-			}
-		}
+	userCooldown, channelCooldown := h.warningCooldowns()
+	if !h.warnings.allow(m.Author.ID, throttledChannel, userCooldown, channelCooldown) {
+		log.Printf("Not warning user %s in channel %s again so soon", m.Author.ID, m.ChannelID)
+		return
 	}
 
-	// Send confirmation with appropriate messaging
-	var confirmText string
-	if len(newWords) > 0 && len(existingWords) > 0 {
-		confirmText = fmt.Sprintf("Takk! Nye ord lagt til: %s. Finst allereie: %s", strings.Join(newWords, ", "), strings.Join(existingWords, ", "))
-	} else if len(newWords) > 0 {
-		confirmText = fmt.Sprintf("Takk! Desse orda har blitt lagt til som forbodne: %s", strings.Join(newWords, ", "))
+	var warning *discordgo.Message
+	if mode == database.WarningModeDM {
+		warning = h.sendBannedWordDM(s, m, hits, forumThreads)
 	} else {
-		confirmText = fmt.Sprintf("Alle orda finst allereie i lista over forbodne ord: %s", strings.Join(existingWords, ", "))
+		warning = h.sendBannedWordWarning(s, m, hits, forumThreads)
 	}
-
-	if len(newWords) > 0 {
-		confirmText += "\n\nEi diskusjonstråd vil bli oppretta etter godkjenning. Sjekk grammatikkforumet seinare."
-	} else {
-		confirmText += "\n\nSjå eksisterande diskusjonar i grammatikkforumet for desse orda."
+	if warning != nil {
+		h.Bot.Database.AddWarning(m.ID, m.ChannelID, warning.ID, warning.ChannelID)
 	}
-
-	confirmEmbed := services.CreateSuccessEmbed("Ord rapporterte", confirmText)
-
-	s.ChannelMessageSendEmbed(m.ChannelID, confirmEmbed)
 }
 
-// checkForBannedWords checks if a message contains banned words and shows warnings
-func (h *Handler) checkForBannedWords(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Skip checking replies to bot messages (to avoid warning on reporting flow)
-	if m.ReferencedMessage != nil && m.ReferencedMessage.Author.ID == s.State.User.ID {
-		return
-	}
-
-	// Convert message to lowercase and split into words
-	messageWords := strings.Fields(strings.ToLower(m.Content))
-	var foundBannedWords []string
+// findBannedWords returns the banned words in a message, once each, and the forum
+// threads discussing them
+func (h *Handler) findBannedWords(m *discordgo.Message) ([]services.BannedWordHit, []string) {
+	var hits []services.BannedWordHit
 	var forumThreads []string
+	seenLabels := make(map[string]bool)
+	seenThreads := make(map[int]bool)
 
-	for _, word := range messageWords {
-		// Clean word of punctuation
-		cleanWord := strings.Trim(word, ".,!?;:()[]{}\"'")
-
-		isBanned, bannedWord, err := h.Bot.Database.IsBannedWord(cleanWord)
-		if err != nil {
-			log.Printf("Error checking banned word '%s': %v", cleanWord, err)
+	for _, match := range h.Bot.BannedWords.Match(bannedwords.Prose(m.Content)) {
+		label := match.Label()
+		if seenLabels[strings.ToLower(label)] {
 			continue
 		}
+		seenLabels[strings.ToLower(label)] = true
 
-		if isBanned {
-			foundBannedWords = append(foundBannedWords, cleanWord)
-			if bannedWord.ForumThreadID != nil {
-				forumThreads = append(forumThreads, *bannedWord.ForumThreadID)
-			}
-			log.Printf("Detected banned word '%s' in message from user %s", cleanWord, m.Author.ID)
+		hits = append(hits, services.BannedWordHit{Word: label, Replacements: match.Word.Replacements})
+		if match.Word.ForumThreadID != nil && *match.Word.ForumThreadID != "" && !seenThreads[match.Word.ID] {
+			seenThreads[match.Word.ID] = true
+			forumThreads = append(forumThreads, *match.Word.ForumThreadID)
 		}
+		log.Printf("Detected banned word '%s' at %d-%d in message from user %s", label, match.Start, match.End, m.Author.ID)
 	}
+	return hits, forumThreads
+}
 
-	if len(foundBannedWords) > 0 {
-		h.sendBannedWordWarning(s, m, foundBannedWords, forumThreads)
+// bannedWordWarningEmbed builds the warning about a message, with a link back to it when sent by DM
+func bannedWordWarningEmbed(m *discordgo.Message, hits []services.BannedWordHit, forumThreads []string, dm bool) *discordgo.MessageEmbed {
+	warningEmbed := services.CreateBannedWordWarningEmbed(hits, forumThreads)
+	if dm {
+		warningEmbed.Description += fmt.Sprintf("\n\n[Hopp til meldinga di](https://discord.com/channels/%s/%s/%s)", m.GuildID, m.ChannelID, m.ID)
 	}
+	return warningEmbed
 }
 
-// sendBannedWordWarning sends a warning about detected banned words
-func (h *Handler) sendBannedWordWarning(s *discordgo.Session, m *discordgo.MessageCreate, bannedWords []string, forumThreads []string) {
-	warningEmbed := services.CreateBannedWordWarningEmbed(bannedWords, forumThreads)
-
+// sendBannedWordWarning sends a warning about detected banned words and returns it
+func (h *Handler) sendBannedWordWarning(s discord.Client, m *discordgo.Message, hits []services.BannedWordHit, forumThreads []string) *discordgo.Message {
 	// Send as a reply to the original message
 	reply := &discordgo.MessageSend{
-		Embed: warningEmbed,
+		Embed: bannedWordWarningEmbed(m, hits, forumThreads, false),
 		Reference: &discordgo.MessageReference{
 			MessageID: m.ID,
 			ChannelID: m.ChannelID,
@@ -347,17 +309,72 @@ func (h *Handler) sendBannedWordWarning(s *discordgo.Session, m *discordgo.Messa
 		},
 	}
 
-	_, err := s.ChannelMessageSendComplex(m.ChannelID, reply)
+	warning, err := s.ChannelMessageSendComplex(m.ChannelID, reply)
 	if err != nil {
 		log.Printf("Failed to send banned word warning: %v", err)
+		return nil
+	}
+	return warning
+}
+
+// sendBannedWordDM sends the warning to the author by direct message, linking the message
+func (h *Handler) sendBannedWordDM(s discord.Client, m *discordgo.Message, hits []services.BannedWordHit, forumThreads []string) *discordgo.Message {
+	channel, err := s.UserChannelCreate(m.Author.ID)
+	if err != nil {
+		log.Printf("Failed to open DM for banned word warning: %v", err)
+		return nil
+	}
+
+	warning, err := s.ChannelMessageSendEmbed(channel.ID, bannedWordWarningEmbed(m, hits, forumThreads, true))
+	if err != nil {
+		log.Printf("Failed to send banned word warning by DM: %v", err)
+		return nil
 	}
+	return warning
 }
 
-// InteractionCreate handles button clicks and other interactions
-func (h *Handler) InteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+// InteractionCreate handles slash commands, button clicks and other interactions
+func (h *Handler) InteractionCreate(_ *discordgo.Session, i *discordgo.InteractionCreate) {
+	done, ok := h.Bot.Track()
+	if !ok {
+		return
+	}
+	defer done()
+	s := h.Bot.Discord
+	if i.Type == discordgo.InteractionApplicationCommand {
+		h.handleApplicationCommand(s, i)
+		return
+	}
+
+	if i.Type == discordgo.InteractionModalSubmit {
+		customID := i.ModalSubmitData().CustomID
+		switch {
+		case strings.HasPrefix(customID, services.ApprovalButtonPrefix):
+			h.Services.Approval.HandleApprovalInteraction(s, i)
+		case strings.HasPrefix(customID, commands.ReportWordModalPrefix):
+			commands.HandleReportWordModal(s, i, h.Bot)
+		}
+		return
+	}
+
 	if i.Type == discordgo.InteractionMessageComponent {
 		customID := i.MessageComponentData().CustomID
 
+		if strings.HasPrefix(customID, services.ApprovalButtonPrefix) {
+			h.Services.Approval.HandleApprovalInteraction(s, i)
+			return
+		}
+
+		if strings.HasPrefix(customID, commands.HelpPagePrefix) {
+			commands.HandleHelpPage(s, i, h.Bot)
+			return
+		}
+
+		if strings.HasPrefix(customID, commands.WordListPagePrefix) {
+			commands.HandleWordListPage(s, i, h.Bot)
+			return
+		}
+
 		if customID == "confirm_clear_database" {
 			// Check if the user is an admin
 			if !h.Services.Approval.UserHasOpplysarRole(s, i.GuildID, i.Member.User.ID) {
@@ -405,3 +422,35 @@ func (h *Handler) InteractionCreate(s *discordgo.Session, i *discordgo.Interacti
 		}
 	}
 }
+
+// handleApplicationCommand runs a slash command, applying the same admin check as prefix commands
+func (h *Handler) handleApplicationCommand(s discord.Client, i *discordgo.InteractionCreate) {
+	name := i.ApplicationCommandData().Name
+	if name == commands.ReportWordCommandName {
+		commands.HandleReportWordCommand(s, i, h.Bot)
+		return
+	}
+
+	if commands.IsAdminCommand(name) {
+		userID := ""
+		if i.Member != nil && i.Member.User != nil {
+			userID = i.Member.User.ID
+		}
+		if userID == "" || !h.Services.Approval.UserHasOpplysarRole(s, i.GuildID, userID) {
+			err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: "Denne kommandoen er berre for opplysarar.",
+					Flags:   discordgo.MessageFlagsEphemeral,
+				},
+			})
+			if err != nil {
+				log.Printf("Failed to send interaction response: %v", err)
+			}
+			return
+		}
+	}
+
+	log.Printf("[DEBUG] Running application command: '%s'", name)
+	commands.MatchAndRunApplicationCommand(s, i, h.Bot)
+}
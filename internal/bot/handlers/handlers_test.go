@@ -0,0 +1,975 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"askeladden/internal/activity"
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/fake"
+	"askeladden/internal/reactions"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	testGuild       = "guild"
+	testChannel     = "general"
+	testRetting     = "retting"
+	testQueue       = "queue"
+	testForum       = "grammatikk"
+	testOpplysar    = "opplysar-role"
+	testRettskrivar = "rettskrivar-role"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// newTestHandler returns a handler wired to an in-memory database and a recording fake
+func newTestHandler(t *testing.T) (*Handler, *fake.Client) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Discord.Prefix = "?"
+	cfg.Approval.OpplysarRoleID = testOpplysar
+	cfg.Approval.QueueChannelID = testQueue
+	cfg.BannedWords.ApprovalChannelID = testRetting
+	cfg.BannedWords.RettskrivarRoleID = testRettskrivar
+	cfg.Grammar.ChannelID = testForum
+	cfg.Starboard.Emoji = "⭐"
+	cfg.Reactions.Question = "❓"
+
+	client := fake.New("bot")
+	client.AddChannel(testGuild, testChannel, "general")
+	client.AddChannel(testGuild, testRetting, "retting")
+	client.AddChannel(testGuild, testQueue, "godkjenning")
+	client.AddChannel(testGuild, testForum, "grammatikk")
+	client.AddUser("reporter", "ola")
+	client.AddUser("opplysar", "kari")
+	client.AddUser("rettskrivar", "per")
+	client.AddMember(testGuild, "reporter")
+	client.AddMember(testGuild, "opplysar", testOpplysar)
+	client.AddMember(testGuild, "rettskrivar", testRettskrivar)
+
+	b := bot.New(cfg, database.NewMemory(), nil)
+	b.Discord = client
+	reactions.InitializeReactions(b)
+	return New(b), client
+}
+
+func reactionAdd(userID, channelID, messageID, emoji string) *discordgo.MessageReactionAdd {
+	return &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID:    userID,
+		ChannelID: channelID,
+		MessageID: messageID,
+		GuildID:   testGuild,
+		Emoji:     discordgo.Emoji{Name: emoji},
+	}}
+}
+
+// buttonClick is a click on a message button by a guild member
+func buttonClick(userID, channelID, messageID, customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "click-" + userID + "-" + customID,
+		Type:      discordgo.InteractionMessageComponent,
+		GuildID:   testGuild,
+		ChannelID: channelID,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Message:   &discordgo.Message{ID: messageID, ChannelID: channelID},
+		Data:      discordgo.MessageComponentInteractionData{CustomID: customID, ComponentType: discordgo.ButtonComponent},
+	}}
+}
+
+// buttonIDs returns the custom IDs of the buttons in a message's components
+func buttonIDs(components []discordgo.MessageComponent) []string {
+	var ids []string
+	for _, component := range components {
+		if row, ok := component.(discordgo.ActionsRow); ok {
+			for _, button := range row.Components {
+				ids = append(ids, button.(discordgo.Button).CustomID)
+			}
+		}
+	}
+	return ids
+}
+
+// lastEphemeral returns the content of the last interaction response, failing unless it was ephemeral
+func lastEphemeral(t *testing.T, client *fake.Client) string {
+	t.Helper()
+	if len(client.InteractionResponses) == 0 {
+		t.Fatalf("no interaction response")
+	}
+	data := client.InteractionResponses[len(client.InteractionResponses)-1].Response.Data
+	if data == nil || data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+		t.Fatalf("interaction response is not ephemeral: %+v", data)
+	}
+	return data.Content
+}
+
+func messageCreate(id, userID, content string, replyTo *discordgo.Message) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:                id,
+		ChannelID:         testChannel,
+		GuildID:           testGuild,
+		Content:           content,
+		Author:            &discordgo.User{ID: userID, Username: userID},
+		ReferencedMessage: replyTo,
+	}}
+}
+
+func TestHammerFlow(t *testing.T) {
+	h, client := newTestHandler(t)
+	client.AddMessage(&discordgo.Message{ID: "orig", ChannelID: testChannel, GuildID: testGuild, Content: "Eg veit ikke"})
+
+	// 1. Hammering a message points the reporter to the context menu command
+	h.ReactionAdd(nil, reactionAdd("reporter", testChannel, "orig", "🔨"))
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 || !strings.Contains(sent[0].Embeds[0].Description, "Rapporter feil ord") {
+		t.Fatalf("hammer hint not sent: %+v", sent)
+	}
+
+	// 2. The context menu command opens a modal that carries the reported message
+	h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "report",
+		Type:      discordgo.InteractionApplicationCommand,
+		GuildID:   testGuild,
+		ChannelID: testChannel,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "reporter", Username: "ola"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:        "Rapporter feil ord",
+			CommandType: discordgo.MessageApplicationCommand,
+			TargetID:    "orig",
+		},
+	}})
+	modal := client.InteractionResponses[len(client.InteractionResponses)-1].Response
+	if modal.Type != discordgo.InteractionResponseModal || modal.Data.CustomID != "rapporter_ord:"+testChannel+":orig" {
+		t.Fatalf("report response = %+v, want a modal for the message", modal)
+	}
+
+	// 3. Submitting the modal stores the words as pending and posts them for approval
+	h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "report-submit",
+		Type:      discordgo.InteractionModalSubmit,
+		GuildID:   testGuild,
+		ChannelID: testChannel,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "reporter", Username: "ola"}},
+		Data: discordgo.ModalSubmitInteractionData{CustomID: modal.Data.CustomID, Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: "ord", Value: "ikke, , IKKE"}}},
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: "rett", Value: "ikkje"}}},
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: "grunn", Value: "Bokmål"}}},
+		}},
+	}})
+
+	words, err := h.Bot.Database.GetBannedWords()
+	if err != nil || len(words) != 1 {
+		t.Fatalf("GetBannedWords = %+v, %v; want one word", words, err)
+	}
+	word := words[0]
+	if word.Word != "ikke" || word.Reason != "Bokmål" || word.ApprovalStatus != "pending" || word.OriginalMessageID == nil || *word.OriginalMessageID != testChannel+"|orig" {
+		t.Fatalf("stored word = %+v", word)
+	}
+
+	queued := client.SentTo(testRetting)
+	if len(queued) != 1 || queued[0].Embeds[0].Title != "ikke" {
+		t.Fatalf("approval channel = %+v, want one post for ikke", queued)
+	}
+	approvalMessage := queued[0]
+	if word.ApprovalMessageID == nil || *word.ApprovalMessageID != approvalMessage.ID {
+		t.Fatalf("approval message ID = %v, want %s", word.ApprovalMessageID, approvalMessage.ID)
+	}
+
+	confirmation := client.InteractionResponses[len(client.InteractionResponses)-1].Response.Data
+	if confirmation.Flags&discordgo.MessageFlagsEphemeral == 0 || !strings.Contains(confirmation.Embeds[0].Description, "ikke") {
+		t.Fatalf("confirmation = %+v, want an ephemeral reply naming the word", confirmation)
+	}
+
+	if fields := approvalMessage.Embeds[0].Fields; len(fields) != 1 || fields[0].Name != "Rett form" || fields[0].Value != "ikkje" {
+		t.Fatalf("approval fields = %+v, want the suggested replacement", fields)
+	}
+
+	buttons := buttonIDs(approvalMessage.Components)
+	if len(buttons) != 3 || buttons[0] != "godkjenning:godkjenn:banned_word:1" {
+		t.Fatalf("approval buttons = %v", buttons)
+	}
+
+	// 4. A member without either role is turned away
+	h.InteractionCreate(nil, buttonClick("reporter", testRetting, approvalMessage.ID, buttons[0]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "Berre opplysarar og rettskrivarar") {
+		t.Fatalf("response to reporter = %q", got)
+	}
+
+	// 5. A rettskrivar alone is recorded, but the word still needs an opplysar
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, approvalMessage.ID, buttons[0]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "opplysar") {
+		t.Fatalf("response to rettskrivar = %q", got)
+	}
+	if len(client.Edits) != 1 || len(client.Edits[0].Components) != 1 || !strings.Contains(client.Edits[0].Embeds[0].Description, "Rettskrivar-godkjenning: per") {
+		t.Fatalf("edits after rettskrivar approval = %+v, want the summary updated with buttons kept", client.Edits)
+	}
+
+	// 6. The opplysar completes the combined approval and a forum thread is opened
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, approvalMessage.ID, buttons[0]))
+
+	_, approved, err := h.Bot.Database.IsBannedWord("ikke")
+	if err != nil || approved.ApprovalStatus != "fully_approved" {
+		t.Fatalf("word after approval = %+v, %v", approved, err)
+	}
+	if len(client.Threads) != 1 || client.Threads[0].ParentID != testForum || client.Threads[0].Name != "ikke" {
+		t.Fatalf("threads = %+v, want one in the grammar forum", client.Threads)
+	}
+	thread := client.Threads[0]
+	if approved.ForumThreadID == nil || *approved.ForumThreadID != thread.ID {
+		t.Fatalf("forum thread ID = %v, want %s", approved.ForumThreadID, thread.ID)
+	}
+	if discussion := client.SentTo(thread.ID); len(discussion) != 1 || !strings.Contains(discussion[0].Embeds[0].Description, "rapporterte") {
+		t.Fatalf("discussion embed = %+v", discussion)
+	}
+	if len(client.Edits) != 2 || client.Edits[1].MessageID != approvalMessage.ID || len(client.Edits[1].Components) != 0 {
+		t.Fatalf("edits = %+v, want the approval message updated without buttons", client.Edits)
+	}
+	summary := client.Edits[1].Embeds[0].Description
+	if !strings.Contains(summary, "kari") || !strings.Contains(summary, "per") {
+		t.Fatalf("approval summary = %q, want both approvers", summary)
+	}
+
+	// 7. Using the word now gets a warning reply that links the thread
+	h.MessageCreate(nil, messageCreate("later", "reporter", "Eg veit ikke!", nil))
+	sent = client.SentTo(testChannel)
+	warning := sent[len(sent)-1]
+	if warning.MessageReference == nil || warning.MessageReference.MessageID != "later" {
+		t.Fatalf("warning is not a reply: %+v", warning)
+	}
+	if !strings.Contains(warning.Embeds[0].Description, "**ikke** → **ikkje**") {
+		t.Fatalf("warning does not suggest the replacement: %q", warning.Embeds[0].Description)
+	}
+	if !strings.Contains(warning.Embeds[0].Description, "<#"+thread.ID+">") {
+		t.Fatalf("warning does not link the thread: %q", warning.Embeds[0].Description)
+	}
+}
+
+func TestReportRegexWithComma(t *testing.T) {
+	h, client := newTestHandler(t)
+	h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "report-submit",
+		Type:      discordgo.InteractionModalSubmit,
+		GuildID:   testGuild,
+		ChannelID: testChannel,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "reporter", Username: "ola"}},
+		Data: discordgo.ModalSubmitInteractionData{CustomID: "rapporter_ord:" + testChannel + ":orig", Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: "ord", Value: "/a{1,3}h/, ikke"}}},
+		}},
+	}})
+
+	words, err := h.Bot.Database.GetBannedWords()
+	if err != nil || len(words) != 2 {
+		t.Fatalf("GetBannedWords = %+v, %v; want two words", words, err)
+	}
+	stored := map[string]bool{words[0].Word: true, words[1].Word: true}
+	if !stored["/a{1,3}h/"] || !stored["ikke"] {
+		t.Fatalf("stored words = %v, want the regex and ikke", stored)
+	}
+	if queued := client.SentTo(testRetting); len(queued) != 2 {
+		t.Fatalf("approval channel = %+v, want a post for each word", queued)
+	}
+}
+
+func TestPhraseAndPatternWarnings(t *testing.T) {
+	h, client := newTestHandler(t)
+	for _, word := range []string{"ikke sant", "*heten"} {
+		id, err := h.Bot.Database.AddBannedWordPending(word, "", "reporter", "ola", "", "")
+		if err != nil {
+			t.Fatalf("AddBannedWordPending(%q): %v", word, err)
+		}
+		if err := h.Bot.Database.ApproveBannedWordCombined(int(id), []string{"kari"}, []string{"per"}); err != nil {
+			t.Fatalf("ApproveBannedWordCombined(%q): %v", word, err)
+		}
+		if word == "ikke sant" {
+			h.Bot.Database.UpdateBannedWordReplacements(int(id), []string{"ikkje sant", "ikkje sannt"})
+		}
+	}
+	h.Bot.BannedWords.Reload(h.Bot.Database)
+
+	h.MessageCreate(nil, messageCreate("msg", "reporter", "Sannheten er vel, ikke  sant?", nil))
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 {
+		t.Fatalf("sent = %+v, want one warning", sent)
+	}
+	if description := sent[0].Embeds[0].Description; !strings.Contains(description, "• **Sannheten (\\*heten)**\n• **ikke sant** → **ikkje sant** / **ikkje sannt**") {
+		t.Fatalf("warning = %q, want the matched text of the pattern and the phrase with its replacements", description)
+	}
+	if strings.Contains(sent[0].Embeds[0].Description, "<#>") {
+		t.Fatalf("warning links a missing thread: %q", sent[0].Embeds[0].Description)
+	}
+
+	// Words that are talked about rather than used are left alone
+	h.MessageCreate(nil, messageCreate("meta", "reporter", "Skriv «ikkje sant», ikkje \"ikke sant\" eller `Sannheten`", nil))
+	if sent := client.SentTo(testChannel); len(sent) != 1 {
+		t.Fatalf("warned about quoted words: %+v", sent[len(sent)-1].Embeds[0].Description)
+	}
+}
+
+func TestQuestionApprovalButtons(t *testing.T) {
+	h, client := newTestHandler(t)
+
+	h.MessageCreate(nil, messageCreate("ask", "reporter", "?spør Kva et du?", nil))
+	queued := client.SentTo(testQueue)
+	if len(queued) != 1 {
+		t.Fatalf("approval queue = %d messages, want 1", len(queued))
+	}
+	post := queued[0]
+	buttons := buttonIDs(post.Components)
+	if len(buttons) != 3 {
+		t.Fatalf("approval buttons = %v", buttons)
+	}
+	approve, edit := buttons[0], buttons[2]
+
+	// Rettskrivarar cannot handle questions
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testQueue, post.ID, approve))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "Berre opplysarar") {
+		t.Fatalf("response to rettskrivar = %q", got)
+	}
+
+	// Rediger opens a modal prefilled with the question, and submitting it updates the post
+	h.InteractionCreate(nil, buttonClick("opplysar", testQueue, post.ID, edit))
+	modal := client.InteractionResponses[len(client.InteractionResponses)-1].Response
+	if modal.Type != discordgo.InteractionResponseModal || modal.Data.CustomID != edit {
+		t.Fatalf("edit response = %+v, want a modal", modal)
+	}
+	h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "submit",
+		Type:      discordgo.InteractionModalSubmit,
+		GuildID:   testGuild,
+		ChannelID: testQueue,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "opplysar"}},
+		Data: discordgo.ModalSubmitInteractionData{CustomID: edit, Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{CustomID: "tekst", Value: "Kva et du til frukost?"},
+			}},
+		}},
+	}})
+	if got := lastEphemeral(t, client); !strings.Contains(got, "Kva et du til frukost?") {
+		t.Fatalf("response to edit = %q", got)
+	}
+	if title := client.Messages[post.ID].Embeds[0].Title; title != "Kva et du til frukost?" {
+		t.Fatalf("approval post title = %q after edit", title)
+	}
+
+	// Godkjenn approves, notifies the author and removes the buttons
+	h.InteractionCreate(nil, buttonClick("opplysar", testQueue, post.ID, approve))
+	question, err := h.Bot.Database.GetQuestionByApprovalMessageID(post.ID)
+	if err != nil || question.ApprovalStatus != "approved" || question.Question != "Kva et du til frukost?" {
+		t.Fatalf("question after approval = %+v, %v", question, err)
+	}
+	if dms := client.SentTo("dm-reporter"); len(dms) == 0 || !strings.Contains(dms[len(dms)-1].Embeds[0].Title, "Gratulerer") {
+		t.Fatalf("author was not notified: %+v", dms)
+	}
+	if components := client.Messages[post.ID].Components; len(components) != 0 {
+		t.Fatalf("approval post still has buttons: %+v", components)
+	}
+
+	// A second click is answered without changing anything
+	h.InteractionCreate(nil, buttonClick("opplysar", testQueue, post.ID, buttons[1]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "allereie handsama") {
+		t.Fatalf("response to late rejection = %q", got)
+	}
+}
+
+func TestIgnoresOwnEvents(t *testing.T) {
+	h, client := newTestHandler(t)
+
+	h.MessageCreate(nil, messageCreate("own", "bot", "?spør Kva no?", nil))
+	h.ReactionAdd(nil, reactionAdd("bot", testChannel, "own", "🔨"))
+
+	if len(client.Sent) != 0 {
+		t.Fatalf("bot reacted to its own events: %+v", client.Sent)
+	}
+}
+
+func TestActivityTracking(t *testing.T) {
+	h, _ := newTestHandler(t)
+	h.Bot.Activity = activity.NewTracker(testChannel, 2, time.Hour)
+	at := time.Date(2024, 5, 17, 10, 0, 0, 0, time.UTC)
+	send := func(userID string, bot bool, minutes int) {
+		m := messageCreate(fmt.Sprint("m", minutes), userID, "Hei", nil)
+		m.Author.Bot = bot
+		m.Timestamp = at.Add(time.Duration(minutes) * time.Minute)
+		h.MessageCreate(nil, m)
+	}
+
+	send("bot", true, 0)
+	send("anna-bot", true, 1)
+	send("reporter", false, 2)
+	if got := h.Bot.Activity.LastActivity(); !got.IsZero() {
+		t.Fatalf("activity from bots or a single author: %v", got)
+	}
+	send("opplysar", false, 3)
+	if got := h.Bot.Activity.LastActivity(); !got.Equal(at.Add(3 * time.Minute)) {
+		t.Errorf("last activity = %v, want the second author's message", got)
+	}
+}
+
+func TestAdminCommandsRequireOpplysar(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		wantReply bool
+	}{
+		{name: "regular member", userID: "reporter"},
+		{name: "rettskrivar", userID: "rettskrivar"},
+		{name: "opplysar", userID: "opplysar", wantReply: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, client := newTestHandler(t)
+
+			h.MessageCreate(nil, messageCreate("cmd", tt.userID, "?godkjenn neste", nil))
+
+			if got := len(client.SentTo(testChannel)) > 0; got != tt.wantReply {
+				t.Fatalf("replied = %v, want %v", got, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestAdminSlashCommandsRequireOpplysar(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		wantRefusal bool
+	}{
+		{name: "regular member", userID: "reporter", wantRefusal: true},
+		{name: "opplysar", userID: "opplysar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, client := newTestHandler(t)
+
+			h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+				ID:        "interaction",
+				Type:      discordgo.InteractionApplicationCommand,
+				ChannelID: testChannel,
+				GuildID:   testGuild,
+				Member:    &discordgo.Member{User: client.Users[tt.userID]},
+				Data: discordgo.ApplicationCommandInteractionData{
+					Name:    "godkjenn",
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: "spørsmål", Type: discordgo.ApplicationCommandOptionString, Value: "neste"}},
+				},
+			}})
+
+			if len(client.InteractionResponses) != 1 {
+				t.Fatalf("interaction responses = %d, want 1", len(client.InteractionResponses))
+			}
+			data := client.InteractionResponses[0].Response.Data
+			refused := len(data.Embeds) == 0 && data.Flags&discordgo.MessageFlagsEphemeral != 0
+			if refused != tt.wantRefusal {
+				t.Fatalf("refused = %v, want %v (response %+v)", refused, tt.wantRefusal, data)
+			}
+		})
+	}
+}
+
+func TestReadyRegistersSlashCommands(t *testing.T) {
+	h, client := newTestHandler(t)
+
+	h.Ready(nil, &discordgo.Ready{User: client.Bot, Application: &discordgo.Application{ID: "app"}})
+
+	if len(client.ApplicationCommands) == 0 {
+		t.Fatal("no application commands registered")
+	}
+}
+
+// approveWord adds a banned word that is fully approved and reloads the index
+func approveWord(t *testing.T, h *Handler, word string) {
+	t.Helper()
+	id, err := h.Bot.Database.AddBannedWordPending(word, "", "reporter", "ola", "", "")
+	if err != nil {
+		t.Fatalf("AddBannedWordPending(%q): %v", word, err)
+	}
+	if err := h.Bot.Database.ApproveBannedWordCombined(int(id), []string{"kari"}, []string{"per"}); err != nil {
+		t.Fatalf("ApproveBannedWordCombined(%q): %v", word, err)
+	}
+	h.Bot.BannedWords.Reload(h.Bot.Database)
+}
+
+func TestWarningThrottle(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+	client.AddUser("other", "nils")
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	h.warnings.now = func() time.Time { return now }
+
+	steps := []struct {
+		after    time.Duration // since the previous step
+		userID   string
+		channel  string
+		wantSent bool
+	}{
+		{0, "reporter", testChannel, true},
+		{10 * time.Second, "reporter", testChannel, false}, // user and channel cooldown
+		{20 * time.Second, "other", testChannel, false},    // channel cooldown
+		{30 * time.Second, "other", testRetting, true},     // another channel
+		{2 * time.Minute, "reporter", testChannel, false},  // user cooldown
+		{2 * time.Minute, "other", testChannel, false},     // user cooldown from the other channel
+		{10 * time.Minute, "reporter", testChannel, true},  // both expired
+		{time.Second, "reporter", testQueue, false},        // user cooldown in another channel
+	}
+	for i, step := range steps {
+		now = now.Add(step.after)
+		before := len(client.SentTo(step.channel))
+		m := messageCreate(fmt.Sprintf("m%d", i), step.userID, "Eg veit ikke", nil)
+		m.ChannelID = step.channel
+		h.MessageCreate(nil, m)
+		if sent := len(client.SentTo(step.channel)) > before; sent != step.wantSent {
+			t.Errorf("step %d: warned = %v, want %v", i, sent, step.wantSent)
+		}
+	}
+
+	// Users and channels whose cooldown has run out are forgotten
+	now = now.Add(time.Hour)
+	h.MessageCreate(nil, messageCreate("later", "other", "Eg veit ikke", nil))
+	if len(h.warnings.lastUser) != 1 || len(h.warnings.lastChannel) != 1 {
+		t.Errorf("throttle remembers %d users and %d channels, want only the last warning", len(h.warnings.lastUser), len(h.warnings.lastChannel))
+	}
+}
+
+func TestWarningModes(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+
+	h.Bot.Database.SetWarningMode("reporter", database.WarningModeDM)
+	h.MessageCreate(nil, messageCreate("m1", "reporter", "Eg veit ikke", nil))
+	if sent := client.SentTo(testChannel); len(sent) != 0 {
+		t.Fatalf("warned in the channel: %+v", sent)
+	}
+	dms := client.SentTo("dm-reporter")
+	if len(dms) != 1 || !strings.Contains(dms[0].Embeds[0].Description, "/"+testChannel+"/m1") {
+		t.Fatalf("DMs = %+v, want one linking the message", dms)
+	}
+
+	h.warnings = newWarningThrottle()
+	h.Bot.Database.SetWarningMode("reporter", database.WarningModeOff)
+	h.MessageCreate(nil, messageCreate("m2", "reporter", "Eg veit ikke", nil))
+	if len(client.SentTo(testChannel)) != 0 || len(client.SentTo("dm-reporter")) != 1 {
+		t.Fatalf("warned a user who opted out")
+	}
+}
+
+func TestWarningChannels(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowed, ignored []string
+		channel          string
+		want             bool
+	}{
+		{name: "everywhere by default", channel: testChannel, want: true},
+		{name: "ignored", ignored: []string{testChannel}, channel: testChannel},
+		{name: "not allowed", allowed: []string{testQueue}, channel: testChannel},
+		{name: "allowed", allowed: []string{testChannel}, channel: testChannel, want: true},
+		{name: "thread of an allowed channel", allowed: []string{testChannel}, channel: "thread", want: true},
+		{name: "thread of an ignored channel", ignored: []string{testChannel}, channel: "thread"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, client := newTestHandler(t)
+			approveWord(t, h, "ikke")
+			client.AddChannel(testGuild, "thread", "tråd").ParentID = testChannel
+			h.Bot.Config.BannedWords.Channels = tt.allowed
+			h.Bot.Config.BannedWords.IgnoredChannels = tt.ignored
+
+			m := messageCreate("m1", "reporter", "Eg veit ikke", nil)
+			m.ChannelID = tt.channel
+			h.MessageCreate(nil, m)
+			if warned := len(client.SentTo(tt.channel)) == 1; warned != tt.want {
+				t.Errorf("warned = %v, want %v", warned, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditedMessages(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+	approveWord(t, h, "hvordan")
+	h.Bot.Config.BannedWords.ReactOnCorrection = true
+	edit := func(id, content string) {
+		h.MessageUpdate(nil, &discordgo.MessageUpdate{Message: messageCreate(id, "reporter", content, nil).Message})
+	}
+
+	h.MessageCreate(nil, messageCreate("m1", "reporter", "Eg veit ikke hvordan", nil))
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 {
+		t.Fatalf("warnings = %d, want 1", len(sent))
+	}
+	warningID := sent[0].ID
+
+	// Fixing one word updates the warning to list the other
+	edit("m1", "Eg veit ikkje hvordan")
+	if len(client.Edits) != 1 || client.Edits[0].MessageID != warningID {
+		t.Fatalf("edits = %+v, want the warning updated", client.Edits)
+	}
+	if desc := client.Edits[0].Embeds[0].Description; strings.Contains(desc, "ikke") || !strings.Contains(desc, "hvordan") {
+		t.Fatalf("updated warning = %q", desc)
+	}
+
+	// Fixing the last one removes the warning and acknowledges the correction
+	edit("m1", "Eg veit ikkje korleis")
+	if len(client.Deletions) != 1 || client.Deletions[0].MessageID != warningID {
+		t.Fatalf("deletions = %+v, want the warning deleted", client.Deletions)
+	}
+	if len(client.Reactions) != 1 || client.Reactions[0].MessageID != "m1" || client.Reactions[0].Emoji != correctedEmoji {
+		t.Fatalf("reactions = %+v", client.Reactions)
+	}
+	if _, err := h.Bot.Database.GetWarning("m1"); err != sql.ErrNoRows {
+		t.Fatalf("warning still tracked: %v", err)
+	}
+
+	// Editing a banned word into a clean message is caught like a new message
+	h.warnings = newWarningThrottle()
+	h.MessageCreate(nil, messageCreate("m2", "reporter", "Heilt fint", nil))
+	edit("m2", "Heilt ikke fint")
+	sent = client.SentTo(testChannel)
+	if len(sent) != 2 {
+		t.Fatalf("warnings = %d, want one for the edited message", len(sent))
+	}
+
+	// Deleting the message deletes its warning
+	h.MessageDelete(nil, &discordgo.MessageDelete{Message: &discordgo.Message{ID: "m2", ChannelID: testChannel}})
+	if len(client.Deletions) != 2 || client.Deletions[1].MessageID != sent[1].ID {
+		t.Fatalf("deletions = %+v, want the second warning deleted", client.Deletions)
+	}
+}
+
+func TestEditedMessageWarnedByDM(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+	h.Bot.Database.SetWarningMode("reporter", database.WarningModeDM)
+
+	h.MessageCreate(nil, messageCreate("m1", "reporter", "Eg veit ikke", nil))
+	h.MessageUpdate(nil, &discordgo.MessageUpdate{Message: messageCreate("m1", "reporter", "Nei, ikke", nil).Message})
+	if len(client.Edits) != 1 || client.Edits[0].ChannelID != "dm-reporter" || !strings.Contains(client.Edits[0].Embeds[0].Description, "/m1") {
+		t.Fatalf("edits = %+v, want the DM updated and still linking the message", client.Edits)
+	}
+	if len(client.Reactions) != 0 {
+		t.Fatalf("reacted without reactOnCorrection: %+v", client.Reactions)
+	}
+}
+
+// reasonSubmit submits the reject modal opened by an approval button
+func reasonSubmit(userID, customID, reason string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "reason-" + userID + "-" + customID,
+		Type:      discordgo.InteractionModalSubmit,
+		GuildID:   testGuild,
+		ChannelID: testRetting,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Data: discordgo.ModalSubmitInteractionData{CustomID: customID, Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{&discordgo.TextInput{CustomID: "grunn", Value: reason}}},
+		}},
+	}}
+}
+
+func TestRejectBannedWordWithReason(t *testing.T) {
+	h, client := newTestHandler(t)
+	id, _ := h.Bot.Database.AddBannedWordPending("noko", "", "reporter", "ola", "", "")
+	h.Services.Approval.PostPendingBannedWordToRettingChannel(id)
+	approvalMessage := client.SentTo(testRetting)[0]
+	reject := buttonIDs(approvalMessage.Components)[1]
+
+	// The Avvis button asks for a reason before anything is stored
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, approvalMessage.ID, reject))
+	modal := client.InteractionResponses[len(client.InteractionResponses)-1].Response
+	if modal.Type != discordgo.InteractionResponseModal || modal.Data.CustomID != reject {
+		t.Fatalf("response to Avvis = %+v, want the reason modal", modal)
+	}
+	if bw, _ := h.Bot.Database.GetBannedWordByID(int(id)); bw.ApprovalStatus != "pending" {
+		t.Fatalf("word rejected before a reason was given")
+	}
+
+	h.InteractionCreate(nil, reasonSubmit("opplysar", reject, "Noko er nynorsk"))
+	bw, _ := h.Bot.Database.GetBannedWordByID(int(id))
+	if bw.ApprovalStatus != "rejected" || bw.RejectionReason != "Noko er nynorsk" {
+		t.Fatalf("word after rejection = %+v", bw)
+	}
+	dms := client.SentTo("dm-reporter")
+	if len(dms) != 1 || !strings.Contains(dms[0].Embeds[0].Description, "Noko er nynorsk") || !strings.Contains(dms[0].Embeds[0].Description, "kari") {
+		t.Fatalf("reporter DMs = %+v, want the reason and who rejected", dms)
+	}
+	edit := client.Edits[len(client.Edits)-1]
+	if edit.MessageID != approvalMessage.ID || len(edit.Components) != 0 || !strings.Contains(edit.Embeds[0].Description, "**Grunn:** Noko er nynorsk") {
+		t.Fatalf("approval post after rejection = %+v", edit)
+	}
+
+	// A second rejection finds the word already handled
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, approvalMessage.ID, reject))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "allereie handsama") {
+		t.Fatalf("second rejection = %q", got)
+	}
+}
+
+func TestEditedBannedWordNeedsFreshApprovals(t *testing.T) {
+	h, client := newTestHandler(t)
+	id, _ := h.Bot.Database.AddBannedWordPending("noko", "", "reporter", "ola", "", "")
+	h.Services.Approval.PostPendingBannedWordToRettingChannel(id)
+	approvalMessage := client.SentTo(testRetting)[0]
+	buttons := buttonIDs(approvalMessage.Components)
+	approve, edit := buttons[0], buttons[2]
+
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, approvalMessage.ID, approve))
+	h.InteractionCreate(nil, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "submit",
+		Type:      discordgo.InteractionModalSubmit,
+		GuildID:   testGuild,
+		ChannelID: testRetting,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: "rettskrivar"}},
+		Data: discordgo.ModalSubmitInteractionData{CustomID: edit, Components: []discordgo.MessageComponent{
+			&discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				&discordgo.TextInput{CustomID: "tekst", Value: "noe"},
+			}},
+		}},
+	}})
+	if got := lastEphemeral(t, client); !strings.Contains(got, "«noe»") {
+		t.Fatalf("response to edit = %q", got)
+	}
+
+	// The opplysar approved "noko", not "noe", so a rettskrivar alone cannot approve it now
+	if approvals, _ := h.Bot.Database.GetApprovals(database.ApprovalEntityBannedWord, int(id)); len(approvals) != 0 {
+		t.Fatalf("approvals after edit = %+v, want none", approvals)
+	}
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, approvalMessage.ID, approve))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "treng òg godkjenning frå ein opplysar") {
+		t.Fatalf("response to rettskrivar after edit = %q", got)
+	}
+	if bw, _ := h.Bot.Database.GetBannedWordByID(int(id)); bw.ApprovalStatus != "pending" || bw.Word != "noe" {
+		t.Fatalf("word after edit and one approval = %+v", bw)
+	}
+}
+
+func TestBannedWordRemovalFlow(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+	_, bw, _ := h.Bot.Database.IsBannedWord("ikke")
+	client.AddChannel(testGuild, "thread-ikke", "ikke").ParentID = testForum
+	h.Bot.Database.UpdateBannedWordForumThreadID(bw.ID, "thread-ikke")
+
+	// Only opplysarar and rettskrivarar can ask, and only once at a time
+	h.MessageCreate(nil, messageCreate("m1", "reporter", "?avbann ikke Det er greitt", nil))
+	h.MessageCreate(nil, messageCreate("m2", "opplysar", "?avbann ikke Det er lov på nynorsk", nil))
+	h.MessageCreate(nil, messageCreate("m3", "rettskrivar", "?avbann ikke Igjen", nil))
+	var titles []string
+	for _, msg := range client.SentTo(testChannel) {
+		titles = append(titles, msg.Embeds[0].Title)
+	}
+	if fmt.Sprint(titles) != "[⛔ Ingen tilgang 🗑️ Fjerning førespurd ⏳ Allereie førespurt]" {
+		t.Fatalf("replies = %v", titles)
+	}
+
+	queued := client.SentTo(testRetting)
+	if len(queued) != 1 || !strings.Contains(queued[0].Embeds[0].Description, "Det er lov på nynorsk") {
+		t.Fatalf("retting channel = %+v, want the removal request", queued)
+	}
+	buttons := buttonIDs(queued[0].Components)
+	if len(buttons) != 2 || buttons[0] != "godkjenning:godkjenn:banned_word_removal:1" {
+		t.Fatalf("removal buttons = %v", buttons)
+	}
+
+	// One role is not enough
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, queued[0].ID, buttons[0]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "rettskrivar") {
+		t.Fatalf("response to opplysar = %q", got)
+	}
+	if _, ok := h.Bot.BannedWords.Lookup("ikke"); !ok {
+		t.Fatalf("word removed with a single approval")
+	}
+
+	// The second role removes the word and closes its discussion thread
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, queued[0].ID, buttons[0]))
+	if banned, _, _ := h.Bot.Database.IsBannedWord("ikke"); banned {
+		t.Fatalf("word still in the database")
+	}
+	if _, ok := h.Bot.BannedWords.Lookup("ikke"); ok {
+		t.Fatalf("word still in the index")
+	}
+	removal, _ := h.Bot.Database.GetBannedWordRemoval(1)
+	if removal.Status != database.RemovalStatusApproved || removal.RequestedBy != "opplysar" ||
+		*removal.OpplysarApprovedBy != "opplysar" || *removal.RettskrivarApprovedBy != "rettskrivar" {
+		t.Fatalf("removal record = %+v", removal)
+	}
+	if posts := client.SentTo("thread-ikke"); len(posts) != 1 || !strings.Contains(posts[0].Embeds[0].Description, "kari") {
+		t.Fatalf("thread posts = %+v, want who removed the word", posts)
+	}
+	if meta := client.Channels["thread-ikke"].ThreadMetadata; meta == nil || !meta.Archived || !meta.Locked {
+		t.Fatalf("thread metadata = %+v, want archived and locked", meta)
+	}
+	if edit := client.Edits[len(client.Edits)-1]; len(edit.Components) != 0 || !strings.Contains(edit.Embeds[0].Title, "FJERNA") {
+		t.Fatalf("approval post after removal = %+v", edit)
+	}
+}
+
+func TestRejectBannedWordRemoval(t *testing.T) {
+	h, client := newTestHandler(t)
+	approveWord(t, h, "ikke")
+	h.MessageCreate(nil, messageCreate("m1", "rettskrivar", "?avbann ikke Det er lov", nil))
+	request := client.SentTo(testRetting)[0]
+	reject := buttonIDs(request.Components)[1]
+
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, request.ID, reject))
+	h.InteractionCreate(nil, reasonSubmit("opplysar", reject, "Framleis bokmål"))
+
+	removal, _ := h.Bot.Database.GetBannedWordRemoval(1)
+	if removal.Status != database.RemovalStatusRejected || removal.RejectionReason != "Framleis bokmål" {
+		t.Fatalf("removal after rejection = %+v", removal)
+	}
+	if _, ok := h.Bot.BannedWords.Lookup("ikke"); !ok {
+		t.Fatalf("word removed although the request was rejected")
+	}
+
+	// The word can be asked about again once the request is closed
+	h.MessageCreate(nil, messageCreate("m2", "rettskrivar", "?avbann ikke Nye kjelder", nil))
+	requests := 0
+	for _, msg := range client.SentTo(testRetting) {
+		if len(msg.Embeds) > 0 && strings.HasPrefix(msg.Embeds[0].Title, "🗑️ Fjerne") {
+			requests++
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("removal requests posted = %d, want a new one", requests)
+	}
+}
+
+// importWords imports words as a pending batch from the opplysar and returns the summary post
+func importWords(t *testing.T, h *Handler, client *fake.Client, words ...string) *discordgo.Message {
+	t.Helper()
+	list, err := bannedwords.ParseWordlist(strings.NewReader(strings.Join(words, "\n")))
+	if err != nil {
+		t.Fatalf("ParseWordlist: %v", err)
+	}
+	result, err := (&services.ApprovalService{Bot: h.Bot}).ImportBannedWords(client, list, "opplysar", "kari", false)
+	if err != nil || len(result.Added) != len(words) {
+		t.Fatalf("imported %v, %v; want %v", result, err, words)
+	}
+	sent := client.SentTo(testRetting)
+	return sent[len(sent)-1]
+}
+
+// failingChannel is a Discord client that cannot post to one channel
+type failingChannel struct {
+	*fake.Client
+	channelID string
+}
+
+func (c failingChannel) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if channelID == c.channelID {
+		return nil, errors.New("missing access")
+	}
+	return c.Client.ChannelMessageSendComplex(channelID, data, options...)
+}
+
+func TestImportNotPosted(t *testing.T) {
+	h, client := newTestHandler(t)
+	list, _ := bannedwords.ParseWordlist(strings.NewReader("noe\nikke"))
+	approval := &services.ApprovalService{Bot: h.Bot}
+
+	// Pending words that never reach the retting channel could never be approved
+	if _, err := approval.ImportBannedWords(failingChannel{client, testRetting}, list, "opplysar", "kari", false); err == nil {
+		t.Fatalf("import succeeded without posting the summary")
+	}
+	if words, _ := h.Bot.Database.GetBannedWords(); len(words) != 0 {
+		t.Fatalf("words left after the failed import: %+v", words)
+	}
+
+	h.Bot.Config.BannedWords.ApprovalChannelID = ""
+	if _, err := approval.ImportBannedWords(client, list, "opplysar", "kari", false); !errors.Is(err, services.ErrNoRettingChannel) {
+		t.Fatalf("import without a retting channel = %v", err)
+	}
+	if words, _ := h.Bot.Database.GetBannedWords(); len(words) != 0 {
+		t.Fatalf("words added without a retting channel: %+v", words)
+	}
+
+	// Approved words do not depend on the summary
+	result, err := approval.ImportBannedWords(client, list, "opplysar", "kari", true)
+	if err != nil || len(result.Added) != 2 {
+		t.Fatalf("approved import = %+v, %v", result, err)
+	}
+}
+
+func TestImportBatchApproval(t *testing.T) {
+	h, client := newTestHandler(t)
+	summary := importWords(t, h, client, "noe", "ikke", "hvordan")
+	if len(client.SentTo(testRetting)) != 1 || !strings.Contains(summary.Embeds[0].Description, "**noe**, **ikke**, **hvordan**") {
+		t.Fatalf("retting channel = %+v, want one summary listing every word", client.SentTo(testRetting))
+	}
+	buttons := buttonIDs(summary.Components)
+	if len(buttons) != 2 || !strings.HasPrefix(buttons[0], "godkjenning:godkjenn:banned_word_import:") {
+		t.Fatalf("summary buttons = %v", buttons)
+	}
+
+	// Editing one word does not redraw the summary as a single report
+	_, bw, _ := h.Bot.Database.IsBannedWord("ikke")
+	h.Services.Approval.RefreshBannedWordApproval(client, bw.ID)
+	if len(client.Edits) != 0 {
+		t.Fatalf("refreshing an imported word edited %+v", client.Edits)
+	}
+
+	h.InteractionCreate(nil, buttonClick("opplysar", testRetting, summary.ID, buttons[0]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "rettskrivar") {
+		t.Fatalf("response to opplysar = %q", got)
+	}
+	if n, _ := h.Bot.Database.CountBannedWords("pending"); n != 3 {
+		t.Fatalf("pending words = %d after one role approved", n)
+	}
+
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, summary.ID, buttons[0]))
+	if n, _ := h.Bot.Database.CountBannedWords("fully_approved"); n != 3 {
+		t.Fatalf("approved words = %d, want the whole batch", n)
+	}
+	for _, word := range []string{"noe", "ikke", "hvordan"} {
+		if _, ok := h.Bot.BannedWords.Lookup(word); !ok {
+			t.Errorf("%s not in the index", word)
+		}
+	}
+	if edit := client.Edits[len(client.Edits)-1]; edit.MessageID != summary.ID || len(edit.Components) != 0 || !strings.Contains(edit.Embeds[0].Title, "GODKJENT") {
+		t.Fatalf("summary after approval = %+v", edit)
+	}
+
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, summary.ID, buttons[0]))
+	if got := lastEphemeral(t, client); !strings.Contains(got, "allereie") {
+		t.Errorf("second approval = %q", got)
+	}
+}
+
+func TestRejectImport(t *testing.T) {
+	h, client := newTestHandler(t)
+	summary := importWords(t, h, client, "noe", "ikke")
+	reject := buttonIDs(summary.Components)[1]
+
+	h.InteractionCreate(nil, buttonClick("rettskrivar", testRetting, summary.ID, reject))
+	modal := client.InteractionResponses[len(client.InteractionResponses)-1].Response
+	if modal.Type != discordgo.InteractionResponseModal || modal.Data.CustomID != reject {
+		t.Fatalf("response = %+v, want the reason modal", modal)
+	}
+	h.InteractionCreate(nil, reasonSubmit("rettskrivar", reject, "Begge er lov"))
+
+	for _, word := range []string{"noe", "ikke"} {
+		_, bw, _ := h.Bot.Database.IsBannedWord(word)
+		if bw.ApprovalStatus != "rejected" || bw.RejectionReason != "Begge er lov" {
+			t.Errorf("%s = %s %q, want rejected with the reason", word, bw.ApprovalStatus, bw.RejectionReason)
+		}
+	}
+	if edit := client.Edits[len(client.Edits)-1]; edit.MessageID != summary.ID || !strings.Contains(edit.Embeds[0].Description, "Begge er lov") {
+		t.Fatalf("summary after rejection = %+v", edit)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// correctedEmoji is added to a message when its author fixes every flagged word
+const correctedEmoji = "✅"
+
+// Default grammar warning cooldowns, used when the config leaves them unset
+const (
+	defaultUserWarningCooldown    = 10 * time.Minute
+	defaultChannelWarningCooldown = time.Minute
+)
+
+// warningThrottle remembers when each user and channel was last warned
+type warningThrottle struct {
+	mu          sync.Mutex
+	lastUser    map[string]time.Time
+	lastChannel map[string]time.Time
+	now         func() time.Time
+}
+
+func newWarningThrottle() *warningThrottle {
+	return &warningThrottle{
+		lastUser:    make(map[string]time.Time),
+		lastChannel: make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// allow records a warning to userID in channelID and returns true, or returns false without
+// recording anything if either was warned within its cooldown. An empty channelID, for a
+// warning sent by DM, only checks the user.
+func (t *warningThrottle) allow(userID, channelID string, userCooldown, channelCooldown time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Expired entries say nothing, so only those within their cooldown are kept
+	now := t.now()
+	expire(t.lastUser, now, userCooldown)
+	expire(t.lastChannel, now, channelCooldown)
+	if _, exists := t.lastUser[userID]; exists {
+		return false
+	}
+	if _, exists := t.lastChannel[channelID]; channelID != "" && exists {
+		return false
+	}
+	t.lastUser[userID] = now
+	if channelID != "" {
+		t.lastChannel[channelID] = now
+	}
+	return true
+}
+
+// expire deletes the times in last that are at least cooldown before now
+func expire(last map[string]time.Time, now time.Time, cooldown time.Duration) {
+	for key, at := range last {
+		if now.Sub(at) >= cooldown {
+			delete(last, key)
+		}
+	}
+}
+
+// warningCooldowns returns the configured cooldowns, falling back to the defaults
+func (h *Handler) warningCooldowns() (user, channel time.Duration) {
+	user, channel = h.Bot.Config.BannedWords.UserCooldown, h.Bot.Config.BannedWords.ChannelCooldown
+	if user <= 0 {
+		user = defaultUserWarningCooldown
+	}
+	if channel <= 0 {
+		channel = defaultChannelWarningCooldown
+	}
+	return user, channel
+}
+
+// detectsIn reports whether banned words are checked in a channel. A thread, or a
+// channel in a category, is also allowed or ignored through its parent.
+func (h *Handler) detectsIn(s discord.Client, channelID string) bool {
+	allowed, ignored := h.Bot.Config.BannedWords.Channels, h.Bot.Config.BannedWords.IgnoredChannels
+	if len(allowed) == 0 && len(ignored) == 0 {
+		return true
+	}
+
+	ids := []string{channelID}
+	if channel, err := s.Channel(channelID); err == nil && channel.ParentID != "" {
+		ids = append(ids, channel.ParentID)
+	}
+	for _, id := range ids {
+		if slices.Contains(ignored, id) {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, id := range ids {
+		if slices.Contains(allowed, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// recheckBannedWords brings the warning about an edited message up to date: it is
+// removed once the author has fixed every banned word and lists the ones left otherwise.
+// A message that was not warned about is checked as if it were new.
+func (h *Handler) recheckBannedWords(s discord.Client, m *discordgo.Message) {
+	warning, err := h.Bot.Database.GetWarning(m.ID)
+	if err == sql.ErrNoRows {
+		h.checkForBannedWords(s, m)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to look up warning for edited message %s: %v", m.ID, err)
+		return
+	}
+
+	hits, forumThreads := h.findBannedWords(m)
+	if len(hits) == 0 {
+		log.Printf("User %s corrected message %s", m.Author.ID, m.ID)
+		h.removeWarning(s, warning)
+		if h.Bot.Config.BannedWords.ReactOnCorrection {
+			if err := s.MessageReactionAdd(m.ChannelID, m.ID, correctedEmoji); err != nil {
+				log.Printf("Failed to react to corrected message: %v", err)
+			}
+		}
+		return
+	}
+
+	dm := warning.WarningChannelID != warning.ChannelID
+	embed := bannedWordWarningEmbed(m, hits, forumThreads, dm)
+	if _, err := s.ChannelMessageEditEmbed(warning.WarningChannelID, warning.WarningMessageID, embed); err != nil {
+		// Most likely someone deleted the warning, so stop tracking it
+		log.Printf("Failed to update warning %s, forgetting it: %v", warning.WarningMessageID, err)
+		h.Bot.Database.RemoveWarning(m.ID)
+	}
+}
+
+// removeWarning deletes a warning message and stops tracking it
+func (h *Handler) removeWarning(s discord.Client, warning *database.Warning) {
+	if err := s.ChannelMessageDelete(warning.WarningChannelID, warning.WarningMessageID); err != nil {
+		log.Printf("Failed to delete warning %s: %v", warning.WarningMessageID, err)
+	}
+	h.Bot.Database.RemoveWarning(warning.OriginalMessageID)
+}
@@ -7,6 +7,8 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -24,30 +26,27 @@ func (s *ApprovalService) PostNewQuestionToApprovalQueue(questionID int64) {
 		return
 	}
 
-	s.postToApprovalQueue(s.Bot.Session, question)
+	s.postToApprovalQueue(s.Bot.Discord, question)
 }
 
 // postToApprovalQueue posts a question to the approval queue channel.
-func (s *ApprovalService) postToApprovalQueue(session *discordgo.Session, question *database.Question) {
+func (s *ApprovalService) postToApprovalQueue(session discord.Client, question *database.Question) {
 	if s.Bot.Config.Approval.QueueChannelID == "" {
 		log.Println("Approval queue channel not configured")
 		return
 	}
 
-	// Get the author's user info
-	author, err := session.User(question.AuthorID)
+	approvalEmbed := s.questionApprovalEmbed(session, question, "⏳ Opplysar-godkjenning: ventar")
 
-	approvalEmbed := CreateApprovalEmbed(question.Question, "⏳ Opplysar-godkjenning: ventar", author)
-
-	approvalMessage, err := session.ChannelMessageSendEmbed(s.Bot.Config.Approval.QueueChannelID, approvalEmbed)
+	approvalMessage, err := session.ChannelMessageSendComplex(s.Bot.Config.Approval.QueueChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{approvalEmbed},
+		Components: ApprovalButtons(database.ApprovalEntityQuestion, question.ID),
+	})
 	if err != nil {
 		log.Printf("Failed to post to approval queue: %v", err)
 		return
 	}
 
-	// Add thumbs up reaction
-	session.MessageReactionAdd(s.Bot.Config.Approval.QueueChannelID, approvalMessage.ID, "👍")
-
 	// Update the database with the approval message ID
 	err = s.Bot.Database.UpdateApprovalMessageID(question.ID, approvalMessage.ID)
 	if err != nil {
@@ -56,7 +55,7 @@ func (s *ApprovalService) postToApprovalQueue(session *discordgo.Session, questi
 }
 
 // UserHasOpplysarRole checks if a user has the opplysar role.
-func (s *ApprovalService) UserHasOpplysarRole(session *discordgo.Session, guildID, userID string) bool {
+func (s *ApprovalService) UserHasOpplysarRole(session discord.Client, guildID, userID string) bool {
 	if s.Bot.Config.Approval.OpplysarRoleID == "" {
 		return false
 	}
@@ -77,7 +76,7 @@ func (s *ApprovalService) UserHasOpplysarRole(session *discordgo.Session, guildI
 }
 
 // UserHasRettskrivarRole checks if a user has the rettskrivar role.
-func (s *ApprovalService) UserHasRettskrivarRole(session *discordgo.Session, guildID, userID string) bool {
+func (s *ApprovalService) UserHasRettskrivarRole(session discord.Client, guildID, userID string) bool {
 	if s.Bot.Config.BannedWords.RettskrivarRoleID == "" {
 		return false
 	}
@@ -98,7 +97,7 @@ func (s *ApprovalService) UserHasRettskrivarRole(session *discordgo.Session, gui
 }
 
 // NotifyUserApproval notifies the user that their question was approved.
-func (s *ApprovalService) NotifyUserApproval(session *discordgo.Session, question *database.Question, approverID string) {
+func (s *ApprovalService) NotifyUserApproval(session discord.Client, question *database.Question, approverID string) {
 	privateChannel, err := session.UserChannelCreate(question.AuthorID)
 	if err != nil {
 		log.Printf("Failed to create private channel for approval notification: %v", err)
@@ -132,20 +131,17 @@ func (s *ApprovalService) PostPendingBannedWordToRettingChannel(bannedWordID int
 		return
 	}
 
-	// Get the hammer user info
-	hammerUser, err := s.Bot.Session.User(bannedWord.AuthorID)
-
-	approvalEmbed := CreateApprovalEmbed(bannedWord.Word, "⏳ Opplysar-godkjenning: ventar\n⏳ Rettskrivar-godkjenning: ventar", hammerUser)
+	approvalEmbed := s.bannedWordApprovalEmbed(s.Bot.Discord, bannedWord, permissions.NewApprovalState(nil))
 
-	message, err := s.Bot.Session.ChannelMessageSendEmbed(channelID, approvalEmbed)
+	message, err := s.Bot.Discord.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{approvalEmbed},
+		Components: ApprovalButtons(database.ApprovalEntityBannedWord, bannedWord.ID),
+	})
 	if err != nil {
 		log.Printf("Failed to post to retting channel: %v", err)
 		return
 	}
 
-	// Add reaction emoji
-	s.Bot.Session.MessageReactionAdd(channelID, message.ID, "👍")
-
 	// Update the database with the approval message ID
 	err = s.Bot.Database.UpdateBannedWordApprovalMessageID(int(bannedWord.ID), message.ID)
 	if err != nil {
@@ -153,9 +149,41 @@ func (s *ApprovalService) PostPendingBannedWordToRettingChannel(bannedWordID int
 	}
 }
 
+// ReportBannedWords stores reported words as pending, with any suggested replacements keyed
+// by word, and posts each new one for approval.
+// Words that are already registered, in any state, are returned separately.
+func (s *ApprovalService) ReportBannedWords(words []string, replacements map[string][]string, reason, reporterID, reporterName, originalChannelID, originalMessageID string) (newWords, existingWords []string) {
+	for _, word := range words {
+		isBanned, _, err := s.Bot.Database.IsBannedWord(word)
+		if err != nil {
+			log.Printf("Error checking if word '%s' exists: %v", word, err)
+			continue
+		}
+		if isBanned {
+			existingWords = append(existingWords, word)
+			continue
+		}
+
+		wordID, err := s.Bot.Database.AddBannedWordPending(word, reason, reporterID, reporterName, "", fmt.Sprintf("%s|%s", originalChannelID, originalMessageID))
+		if err != nil {
+			log.Printf("Error adding pending banned word '%s': %v", word, err)
+			continue
+		}
+		log.Printf("Added pending banned word: %s with ID %d", word, wordID)
+		if len(replacements[word]) > 0 {
+			if err := s.Bot.Database.UpdateBannedWordReplacements(int(wordID), replacements[word]); err != nil {
+				log.Printf("Error saving replacements for '%s': %v", word, err)
+			}
+		}
+		newWords = append(newWords, word)
+		s.PostPendingBannedWordToRettingChannel(wordID)
+	}
+	return newWords, existingWords
+}
+
 // PostBannedWordReport creates a forum post in the grammar channel for banned word discussion
 // Returns the forum thread if a new one was created, or nil if referencing existing threads
-func (s *ApprovalService) PostBannedWordReport(session *discordgo.Session, words []string, reporterID string, guildID string, originalChannelID string, originalMessageID string) *discordgo.Channel {
+func (s *ApprovalService) PostBannedWordReport(session discord.Client, words []string, reporterID string, guildID string, originalChannelID string, originalMessageID string) *discordgo.Channel {
 	if s.Bot.Config.Grammar.ChannelID == "" {
 		log.Println("Grammar channel not configured")
 		return nil
@@ -173,12 +201,7 @@ func (s *ApprovalService) PostBannedWordReport(session *discordgo.Session, words
 	var existingThreads []string
 
 	for _, word := range words {
-		isBanned, bannedWord, err := s.Bot.Database.IsBannedWord(word)
-		if err != nil {
-			log.Printf("Error checking if word '%s' is banned: %v", word, err)
-			continue
-		}
-
+		bannedWord, isBanned := s.Bot.BannedWords.Lookup(word)
 		if isBanned && bannedWord.ForumThreadID != nil && *bannedWord.ForumThreadID != "" {
 			// Word already exists with a forum thread
 			existingThreads = append(existingThreads, *bannedWord.ForumThreadID)
@@ -251,7 +274,7 @@ func (s *ApprovalService) PostBannedWordReport(session *discordgo.Session, words
 }
 
 // NotifyUserRejection notifies the user that their question was rejected.
-func (s *ApprovalService) NotifyUserRejection(session *discordgo.Session, question *database.Question, rejectorID string) {
+func (s *ApprovalService) NotifyUserRejection(session discord.Client, question *database.Question, rejectorID string) {
 	privateChannel, err := session.UserChannelCreate(question.AuthorID)
 	if err != nil {
 		log.Printf("Failed to create private channel for rejection notification: %v", err)
@@ -272,3 +295,22 @@ func (s *ApprovalService) NotifyUserRejection(session *discordgo.Session, questi
 		log.Printf("Failed to send rejection notification to user: %v", err)
 	}
 }
+
+// NotifyBannedWordRejection tells the reporter of a banned word why it was rejected.
+func (s *ApprovalService) NotifyBannedWordRejection(session discord.Client, bannedWord *database.BannedWord, rejectorID, reason string) {
+	privateChannel, err := session.UserChannelCreate(bannedWord.AuthorID)
+	if err != nil {
+		log.Printf("Failed to create private channel for rejection notification: %v", err)
+		return
+	}
+
+	rejectorName := "ein opplysar"
+	if rejector, err := session.User(rejectorID); err == nil {
+		rejectorName = rejector.Username
+	}
+
+	embed := CreateBotEmbed(session, "❌ Ord avvist", fmt.Sprintf("Ordet du rapporterte, **«%s»**, er avvist av %s.\n\n**Grunn:** %s\n\nTakk for at du melde det likevel!", bannedWord.Word, rejectorName, reason), EmbedTypeError)
+	if _, err := session.ChannelMessageSendEmbed(privateChannel.ID, embed); err != nil {
+		log.Printf("Failed to send rejection notification to user: %v", err)
+	}
+}
@@ -0,0 +1,571 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ApprovalButtonPrefix starts the custom ID of the approval queue buttons and the
+// edit modal: "godkjenning:<action>:<entity type>:<id>"
+const ApprovalButtonPrefix = "godkjenning:"
+
+// Actions encoded in approval custom IDs
+const (
+	ApprovalActionApprove = "godkjenn"
+	ApprovalActionReject  = "avvis"
+	ApprovalActionEdit    = "rediger"
+)
+
+// Custom IDs of the text inputs in the edit and reject modals
+const (
+	approvalEditField   = "tekst"
+	approvalFormsField  = "former" // banned words only
+	approvalReasonField = "grunn"
+)
+
+// approvalCustomID builds the custom ID for an approval button or modal
+func approvalCustomID(action, entityType string, id int) string {
+	return fmt.Sprintf("%s%s:%s:%d", ApprovalButtonPrefix, action, entityType, id)
+}
+
+// parseApprovalCustomID splits an approval custom ID into its action, entity type and ID
+func parseApprovalCustomID(customID string) (action, entityType string, id int, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(customID, ApprovalButtonPrefix), ":")
+	if len(parts) != 3 {
+		return "", "", 0, false
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", "", 0, false
+	}
+	return parts[0], parts[1], id, true
+}
+
+// ApprovalButtons returns the Godkjenn / Avvis / Rediger buttons for an approval post.
+// Removal requests and imports have nothing to edit and only get Godkjenn / Avvis.
+func ApprovalButtons(entityType string, id int) []discordgo.MessageComponent {
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Godkjenn",
+			Style:    discordgo.SuccessButton,
+			CustomID: approvalCustomID(ApprovalActionApprove, entityType, id),
+		},
+		discordgo.Button{
+			Label:    "Avvis",
+			Style:    discordgo.DangerButton,
+			CustomID: approvalCustomID(ApprovalActionReject, entityType, id),
+		},
+	}
+	if entityType != database.ApprovalEntityBannedWordRemoval && entityType != database.ApprovalEntityBannedWordImport {
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Rediger",
+			Style:    discordgo.SecondaryButton,
+			CustomID: approvalCustomID(ApprovalActionEdit, entityType, id),
+		})
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// HandleApprovalInteraction handles the approval queue buttons and the edit and reject modals.
+// Questions are handled by opplysarar; banned words and requests to remove them need
+// approval from both an opplysar and a rettskrivar, which is recorded per role until
+// both have approved. Rejecting a banned word or removal asks for a reason first.
+func (s *ApprovalService) HandleApprovalInteraction(session discord.Client, i *discordgo.InteractionCreate) {
+	var customID string
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		customID = i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		customID = i.ModalSubmitData().CustomID
+	default:
+		return
+	}
+
+	action, entityType, id, ok := parseApprovalCustomID(customID)
+	if !ok {
+		log.Printf("[APPROVAL] Malformed approval ID: %s", customID)
+		return
+	}
+
+	if i.Member == nil || i.Member.User == nil {
+		respondApproval(session, i, "Godkjenning kan berre gjerast på serveren.")
+		return
+	}
+	userID := i.Member.User.ID
+
+	role := permissions.NewPermissionManager(s.Bot.Config).GetUserRole(session, i.GuildID, userID)
+	if !role.CanHandle(entityType) {
+		if entityType == database.ApprovalEntityQuestion {
+			respondApproval(session, i, "Berre opplysarar kan handsame spørsmål.")
+		} else {
+			respondApproval(session, i, "Berre opplysarar og rettskrivarar kan handsame ord.")
+		}
+		return
+	}
+
+	switch {
+	case action == ApprovalActionEdit && i.Type == discordgo.InteractionModalSubmit:
+		s.saveEdit(session, i, entityType, id, ModalValue(i.ModalSubmitData(), approvalEditField))
+	case action == ApprovalActionEdit:
+		s.openEditModal(session, i, entityType, id)
+	case entityType == database.ApprovalEntityQuestion && action == ApprovalActionApprove:
+		s.approveQuestion(session, i, id, userID)
+	case entityType == database.ApprovalEntityQuestion && action == ApprovalActionReject:
+		s.rejectQuestion(session, i, id, userID)
+	case entityType == database.ApprovalEntityBannedWord && action == ApprovalActionApprove:
+		s.approveBannedWord(session, i, id, userID, role)
+	case entityType == database.ApprovalEntityBannedWord && action == ApprovalActionReject && i.Type == discordgo.InteractionModalSubmit:
+		s.rejectBannedWord(session, i, id, userID, ModalValue(i.ModalSubmitData(), approvalReasonField))
+	case entityType == database.ApprovalEntityBannedWordRemoval && action == ApprovalActionApprove:
+		s.approveRemoval(session, i, id, userID, role)
+	case entityType == database.ApprovalEntityBannedWordRemoval && action == ApprovalActionReject && i.Type == discordgo.InteractionModalSubmit:
+		s.rejectRemoval(session, i, id, userID, ModalValue(i.ModalSubmitData(), approvalReasonField))
+	case entityType == database.ApprovalEntityBannedWordImport && action == ApprovalActionApprove:
+		s.approveImport(session, i, id, userID, role)
+	case entityType == database.ApprovalEntityBannedWordImport && action == ApprovalActionReject && i.Type == discordgo.InteractionModalSubmit:
+		s.rejectImport(session, i, id, userID, ModalValue(i.ModalSubmitData(), approvalReasonField))
+	case action == ApprovalActionReject && entityType != database.ApprovalEntityQuestion:
+		s.openRejectModal(session, i, entityType, id)
+	default:
+		log.Printf("[APPROVAL] Unknown approval action %s for %s", action, entityType)
+	}
+}
+
+func (s *ApprovalService) approveQuestion(session discord.Client, i *discordgo.InteractionCreate, id int, userID string) {
+	question, err := s.Bot.Database.GetPendingQuestionByID(id)
+	if err != nil {
+		respondApproval(session, i, "Spørsmålet er allereie handsama.")
+		return
+	}
+	if err := s.Bot.Database.ApproveQuestion(question.ID, userID); err != nil {
+		log.Printf("Failed to approve question: %v", err)
+		respondApproval(session, i, "Kunne ikkje godkjenne spørsmålet.")
+		return
+	}
+	log.Printf("Question approved by opplysar %s: %s", userID, question.Question)
+	respondApproval(session, i, "✅ Spørsmålet er godkjent.")
+
+	s.NotifyUserApproval(session, question, userID)
+
+	approverName := "Ukjend"
+	if approver, err := session.User(userID); err == nil {
+		approverName = approver.Username
+	}
+	embed := s.questionApprovalEmbed(session, question, fmt.Sprintf("🧘‍♀️ Opplysar-godkjenning: %s", approverName))
+	embed.Color = ColorSuccess
+	s.editApprovalMessage(session, s.Bot.Config.Approval.QueueChannelID, question.ApprovalMessageID, embed, nil)
+}
+
+func (s *ApprovalService) rejectQuestion(session discord.Client, i *discordgo.InteractionCreate, id int, userID string) {
+	question, err := s.Bot.Database.GetPendingQuestionByID(id)
+	if err != nil {
+		respondApproval(session, i, "Spørsmålet er allereie handsama.")
+		return
+	}
+	if err := s.Bot.Database.RejectQuestion(question.ID, userID); err != nil {
+		log.Printf("Failed to reject question: %v", err)
+		respondApproval(session, i, "Kunne ikkje avvise spørsmålet.")
+		return
+	}
+	log.Printf("Question rejected by opplysar %s: %s", userID, question.Question)
+	respondApproval(session, i, "❌ Spørsmålet er avvist.")
+
+	s.NotifyUserRejection(session, question, userID)
+
+	embed := CreateBotEmbed(session, "❌ AVVIST", fmt.Sprintf("**Spørsmål:** %s\n**Frå:** %s\n**Avvist av:** <@%s>", question.Question, question.AuthorName, userID), EmbedTypeError)
+	s.editApprovalMessage(session, s.Bot.Config.Approval.QueueChannelID, question.ApprovalMessageID, embed, nil)
+}
+
+func (s *ApprovalService) approveBannedWord(session discord.Client, i *discordgo.InteractionCreate, id int, userID string, role permissions.UserRole) {
+	bannedWord := s.pendingBannedWord(id)
+	if bannedWord == nil {
+		respondApproval(session, i, "Ordet er allereie handsama.")
+		return
+	}
+
+	for _, approvalRole := range role.ApprovalRoles() {
+		if err := s.Bot.Database.AddApproval(database.ApprovalEntityBannedWord, id, userID, approvalRole); err != nil {
+			log.Printf("Failed to record approval: %v", err)
+			respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+			return
+		}
+	}
+	state, err := s.bannedWordApprovalState(id)
+	if err != nil {
+		respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+		return
+	}
+
+	if !state.IsFullyApproved() {
+		missing := "rettskrivar"
+		if !state.HasOpplysarApproval {
+			missing = "opplysar"
+		}
+		log.Printf("Banned word %s partially approved - waiting for additional roles", bannedWord.Word)
+		respondApproval(session, i, fmt.Sprintf("👍 Godkjenninga di er registrert. Ordet treng òg godkjenning frå ein %s.", missing))
+		s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, bannedWord.ApprovalMessageID,
+			s.bannedWordApprovalEmbed(session, bannedWord, state), ApprovalButtons(database.ApprovalEntityBannedWord, id))
+		return
+	}
+
+	if err := s.Bot.Database.ApproveBannedWordCombined(id, state.OpplysarApprovers, state.RettskrivarApprovers); err != nil {
+		log.Printf("Failed to approve banned word: %v", err)
+		respondApproval(session, i, "Ordet er allereie handsama.")
+		return
+	}
+	log.Printf("Banned word %s fully approved by combined roles", bannedWord.Word)
+	respondApproval(session, i, fmt.Sprintf("✅ «%s» er godkjent og lagt til i lista over feil ord.", bannedWord.Word))
+
+	// Open a forum thread for discussion
+	originalChannelID, originalMessageID := "", ""
+	if bannedWord.OriginalMessageID != nil {
+		// Stored as channel|message; older rows only have the message ID
+		if channelID, messageID, found := strings.Cut(*bannedWord.OriginalMessageID, "|"); found {
+			originalChannelID, originalMessageID = channelID, messageID
+		} else {
+			originalMessageID = *bannedWord.OriginalMessageID
+		}
+	}
+	thread := s.PostBannedWordReport(session, []string{bannedWord.Word}, bannedWord.AuthorID, i.GuildID, originalChannelID, originalMessageID)
+	if thread != nil {
+		log.Printf("Created forum thread %s for banned word %s", thread.ID, bannedWord.Word)
+		s.Bot.Database.UpdateBannedWordForumThreadID(id, thread.ID)
+	}
+	s.Bot.BannedWords.Reload(s.Bot.Database)
+
+	embed := s.bannedWordApprovalEmbed(session, bannedWord, state)
+	embed.Color = ColorSuccess
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, bannedWord.ApprovalMessageID, embed, nil)
+}
+
+func (s *ApprovalService) rejectBannedWord(session discord.Client, i *discordgo.InteractionCreate, id int, userID, reason string) {
+	reason = strings.TrimSpace(reason)
+	bannedWord := s.pendingBannedWord(id)
+	if bannedWord == nil {
+		respondApproval(session, i, "Ordet er allereie handsama.")
+		return
+	}
+	if err := s.Bot.Database.RejectBannedWord(id, userID, reason); err != nil {
+		log.Printf("Failed to reject banned word: %v", err)
+		respondApproval(session, i, "Ordet er allereie handsama.")
+		return
+	}
+	log.Printf("Banned word %s rejected by %s: %s", bannedWord.Word, userID, reason)
+	s.Bot.BannedWords.Reload(s.Bot.Database)
+	respondApproval(session, i, fmt.Sprintf("❌ «%s» er avvist.", bannedWord.Word))
+
+	s.NotifyBannedWordRejection(session, bannedWord, userID, reason)
+
+	embed := CreateBotEmbed(session, "❌ AVVIST", fmt.Sprintf("**Ord:** %s\n**Frå:** %s\n**Avvist av:** <@%s>\n**Grunn:** %s", bannedWord.Word, bannedWord.AuthorName, userID, reason), EmbedTypeError)
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, bannedWord.ApprovalMessageID, embed, nil)
+}
+
+// openRejectModal asks why a banned word or removal request is rejected
+func (s *ApprovalService) openRejectModal(session discord.Client, i *discordgo.InteractionCreate, entityType string, id int) {
+	var title, placeholder string
+	switch entityType {
+	case database.ApprovalEntityBannedWordRemoval:
+		if s.pendingRemoval(id) == nil {
+			respondApproval(session, i, "Førespurnaden er allereie handsama.")
+			return
+		}
+		title = "Behald ordet"
+		placeholder = "Kvifor skal ordet framleis vere på lista?"
+	case database.ApprovalEntityBannedWordImport:
+		if len(s.pendingImport(id)) == 0 {
+			respondApproval(session, i, "Importen er allereie handsama.")
+			return
+		}
+		title = "Avvis importen"
+		placeholder = "Kvifor skal ingen av orda på lista?"
+	default:
+		if s.pendingBannedWord(id) == nil {
+			respondApproval(session, i, "Ordet er allereie handsama.")
+			return
+		}
+		title = "Avvis ord"
+		placeholder = "Kvifor er ordet ikkje feil? Den som rapporterte det, får grunnen på DM."
+	}
+
+	err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: approvalCustomID(ApprovalActionReject, entityType, id),
+			Title:    title,
+			Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.TextInput{
+					CustomID:    approvalReasonField,
+					Label:       "Grunn",
+					Style:       discordgo.TextInputParagraph,
+					Placeholder: placeholder,
+					Required:    true,
+					MaxLength:   1000,
+				},
+			}}},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to open reject modal: %v", err)
+	}
+}
+
+// openEditModal asks for a new text for a pending question or banned word
+func (s *ApprovalService) openEditModal(session discord.Client, i *discordgo.InteractionCreate, entityType string, id int) {
+	input := discordgo.TextInput{
+		CustomID: approvalEditField,
+		Required: true,
+	}
+	var title string
+	var formsInput *discordgo.TextInput
+	if entityType == database.ApprovalEntityQuestion {
+		question, err := s.Bot.Database.GetPendingQuestionByID(id)
+		if err != nil {
+			respondApproval(session, i, "Spørsmålet er allereie handsama.")
+			return
+		}
+		title = "Rediger spørsmål"
+		input.Label = "Spørsmål"
+		input.Style = discordgo.TextInputParagraph
+		input.Value = question.Question
+		input.MaxLength = 1000
+	} else {
+		bannedWord := s.pendingBannedWord(id)
+		if bannedWord == nil {
+			respondApproval(session, i, "Ordet er allereie handsama.")
+			return
+		}
+		title = "Rediger ord"
+		input.Label = "Ord"
+		input.Style = discordgo.TextInputShort
+		input.Value = bannedWord.Word
+		input.MaxLength = 255
+		formsInput = &discordgo.TextInput{
+			CustomID:    approvalFormsField,
+			Label:       "Bøyingsformer (valfritt)",
+			Style:       discordgo.TextInputParagraph,
+			Placeholder: "Skil med komma. Former frå ordlista kjem i tillegg.",
+			Value:       strings.Join(bannedWord.Forms, ", "),
+			MaxLength:   1000,
+		}
+	}
+
+	rows := []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}}}
+	if formsInput != nil {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{*formsInput}})
+	}
+
+	err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   approvalCustomID(ApprovalActionEdit, entityType, id),
+			Title:      title,
+			Components: rows,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to open edit modal: %v", err)
+	}
+}
+
+// saveEdit stores the text from the edit modal and updates the approval post
+func (s *ApprovalService) saveEdit(session discord.Client, i *discordgo.InteractionCreate, entityType string, id int, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		respondApproval(session, i, "Teksten kan ikkje vere tom.")
+		return
+	}
+
+	if entityType == database.ApprovalEntityQuestion {
+		if err := s.Bot.Database.UpdateQuestionText(id, text); err != nil {
+			respondApproval(session, i, "Spørsmålet er allereie handsama.")
+			return
+		}
+		// The edit is saved, so it is confirmed even if the post cannot be redrawn
+		respondApproval(session, i, fmt.Sprintf("✏️ Spørsmålet er endra til «%s».", text))
+		question, err := s.Bot.Database.GetPendingQuestionByID(id)
+		if err != nil {
+			log.Printf("Failed to reload edited question %d, approval post not redrawn: %v", id, err)
+			return
+		}
+		s.editApprovalMessage(session, s.Bot.Config.Approval.QueueChannelID, question.ApprovalMessageID,
+			s.questionApprovalEmbed(session, question, "⏳ Opplysar-godkjenning: ventar"), ApprovalButtons(entityType, id))
+		return
+	}
+
+	entry, err := bannedwords.ParseEntry(text)
+	if err != nil {
+		respondApproval(session, i, fmt.Sprintf("Kunne ikkje tolke «%s»: %v", text, err))
+		return
+	}
+	text = entry.String()
+	bannedWord := s.pendingBannedWord(id)
+	if bannedWord == nil {
+		respondApproval(session, i, "Ordet er allereie handsama.")
+		return
+	}
+	if text != bannedWord.Word {
+		if err := s.Bot.Database.UpdateBannedWordText(id, text); err != nil {
+			respondApproval(session, i, fmt.Sprintf("Kunne ikkje endre ordet til «%s». Anten er det allereie handsama, eller så finst ordet frå før.", text))
+			return
+		}
+	}
+	var forms []string
+	for _, form := range strings.FieldsFunc(ModalValue(i.ModalSubmitData(), approvalFormsField), func(r rune) bool { return r == ',' || r == '\n' }) {
+		if form = strings.ToLower(strings.Join(strings.Fields(form), " ")); form != "" {
+			forms = append(forms, form)
+		}
+	}
+	if err := s.Bot.Database.UpdateBannedWordForms(id, forms); err != nil {
+		respondApproval(session, i, "Kunne ikkje lagre bøyingsformene.")
+		return
+	}
+
+	respondApproval(session, i, fmt.Sprintf("✏️ Ordet er endra til «%s».", text))
+	bannedWord = s.pendingBannedWord(id)
+	state, err := s.bannedWordApprovalState(id)
+	if bannedWord == nil || err != nil {
+		log.Printf("Failed to reload edited banned word %d, approval post not redrawn: %v", id, err)
+		return
+	}
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, bannedWord.ApprovalMessageID,
+		s.bannedWordApprovalEmbed(session, bannedWord, state), ApprovalButtons(entityType, id))
+}
+
+// RefreshBannedWordApproval redraws the approval post of a pending banned word after it changed
+func (s *ApprovalService) RefreshBannedWordApproval(session discord.Client, id int) {
+	bannedWord := s.pendingBannedWord(id)
+	if bannedWord == nil {
+		return
+	}
+	state, err := s.bannedWordApprovalState(id)
+	if err != nil {
+		return
+	}
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, bannedWord.ApprovalMessageID,
+		s.bannedWordApprovalEmbed(session, bannedWord, state), ApprovalButtons(database.ApprovalEntityBannedWord, id))
+}
+
+// pendingBannedWord returns the banned word if it is still awaiting approval, or nil
+func (s *ApprovalService) pendingBannedWord(id int) *database.BannedWord {
+	bannedWord, err := s.Bot.Database.GetBannedWordByID(id)
+	if err != nil || bannedWord == nil || bannedWord.ApprovalStatus != "pending" {
+		return nil
+	}
+	return bannedWord
+}
+
+// bannedWordApprovalState returns the recorded approvals of a banned word
+func (s *ApprovalService) bannedWordApprovalState(id int) (*permissions.ApprovalState, error) {
+	approvals, err := s.Bot.Database.GetApprovals(database.ApprovalEntityBannedWord, id)
+	if err != nil {
+		log.Printf("Failed to get approvals for banned word %d: %v", id, err)
+		return nil, err
+	}
+	return permissions.NewApprovalState(approvals), nil
+}
+
+// questionApprovalEmbed builds the approval post for a question with the author as embed author
+func (s *ApprovalService) questionApprovalEmbed(session discord.Client, question *database.Question, description string) *discordgo.MessageEmbed {
+	author, err := session.User(question.AuthorID)
+	if err != nil {
+		author = &discordgo.User{ID: question.AuthorID, Username: question.AuthorName}
+	}
+	return CreateApprovalEmbed(question.Question, description, author)
+}
+
+// bannedWordApprovalEmbed builds the approval post for a banned word: red while nobody
+// has approved it, yellow while it waits for the other role
+func (s *ApprovalService) bannedWordApprovalEmbed(session discord.Client, bannedWord *database.BannedWord, state *permissions.ApprovalState) *discordgo.MessageEmbed {
+	hammerUser, err := session.User(bannedWord.AuthorID)
+	if err != nil {
+		hammerUser = &discordgo.User{ID: bannedWord.AuthorID, Username: bannedWord.AuthorName}
+	}
+	embed := CreateApprovalEmbed(bannedWord.Word, state.GetApprovalSummary(session), hammerUser)
+	if len(bannedWord.Replacements) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Rett form", Value: strings.Join(bannedWord.Replacements, " / ")})
+	}
+	if forms := s.Bot.BannedWords.Forms(bannedWord); len(forms) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Bøyingsformer", Value: joinForms(forms)})
+	}
+	if state.HasOpplysarApproval || state.HasRettskrivarApproval {
+		embed.Color = ColorWarning
+	}
+	return embed
+}
+
+// editApprovalMessage replaces the embed and buttons of an approval post; nil buttons removes them
+func (s *ApprovalService) editApprovalMessage(session discord.Client, channelID string, messageID *string, embed *discordgo.MessageEmbed, buttons []discordgo.MessageComponent) {
+	if messageID == nil || *messageID == "" {
+		return
+	}
+	if buttons == nil {
+		buttons = []discordgo.MessageComponent{}
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
+	_, err := session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    channelID,
+		ID:         *messageID,
+		Embeds:     &embeds,
+		Components: &buttons,
+	})
+	if err != nil {
+		log.Printf("Failed to update approval message %s: %v", *messageID, err)
+	}
+}
+
+// ModalValue returns the value of a text input in a submitted modal
+func ModalValue(data discordgo.ModalSubmitInteractionData, customID string) string {
+	for _, row := range data.Components {
+		actions, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, component := range actions.Components {
+			if input, ok := component.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
+
+// respondApproval answers an approval interaction with a message only the clicking user sees
+func respondApproval(session discord.Client, i *discordgo.InteractionCreate, content string) {
+	err := session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to send interaction response: %v", err)
+	}
+}
+
+// joinForms lists forms for an embed field, cut after the last whole form that fits in
+// 1024 bytes, or mid-form if not even the first one fits. Counting bytes keeps the value
+// within Discord's 1024 character limit even when the forms are not ASCII.
+func joinForms(forms []string) string {
+	value := strings.Join(forms, ", ")
+	if len(value) <= 1024 {
+		return value
+	}
+	cut := value[:1020]
+	if end := strings.LastIndex(cut, ", "); end >= 0 {
+		return cut[:end] + ", …"
+	}
+	for !utf8.ValidString(cut) {
+		cut = cut[:len(cut)-1]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestJoinForms(t *testing.T) {
+	if got := joinForms([]string{"hest", "hesten"}); got != "hest, hesten" {
+		t.Errorf("short forms = %q", got)
+	}
+
+	many := make([]string, 200)
+	for i := range many {
+		many[i] = "bøyingsform"
+	}
+	got := joinForms(many)
+	if len(got) > 1024 || !strings.HasSuffix(got, "bøyingsform, …") {
+		t.Errorf("many forms cut to %d bytes ending %q", len(got), got[len(got)-20:])
+	}
+
+	// One form longer than the limit, with multi-byte runes across the cut
+	got = joinForms([]string{strings.Repeat("å", 600)})
+	if len(got) > 1024 || !utf8.ValidString(got) || !strings.HasSuffix(got, "å…") {
+		t.Errorf("long form cut to %d bytes, valid %v", len(got), utf8.ValidString(got))
+	}
+}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"askeladden/internal/database"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -99,8 +101,8 @@ func (eb *EmbedBuilder) SetAuthorFromUser(user *discordgo.User) *EmbedBuilder {
 }
 
 // SetAuthorFromBot sets the author from the bot user
-func (eb *EmbedBuilder) SetAuthorFromBot(session *discordgo.Session) *EmbedBuilder {
-	if botUser, err := session.User("@me"); err == nil {
+func (eb *EmbedBuilder) SetAuthorFromBot(session discord.Client) *EmbedBuilder {
+	if botUser := session.BotUser(); botUser != nil {
 		eb.embed.Author = &discordgo.MessageEmbedAuthor{
 			Name:    botUser.Username,
 			IconURL: botUser.AvatarURL(""),
@@ -179,7 +181,7 @@ func CreateInfoEmbed(title, description string) *discordgo.MessageEmbed {
 }
 
 // CreateBotEmbed creates an embed with the bot as author
-func CreateBotEmbed(session *discordgo.Session, title, description string, embedType EmbedType) *discordgo.MessageEmbed {
+func CreateBotEmbed(session discord.Client, title, description string, embedType EmbedType) *discordgo.MessageEmbed {
 	return NewEmbedBuilder().
 		SetTitle(title).
 		SetDescription(description).
@@ -189,7 +191,7 @@ func CreateBotEmbed(session *discordgo.Session, title, description string, embed
 }
 
 // Legacy function maintained for compatibility
-func CreateBotEmbedLegacy(session *discordgo.Session, title, description string, color int) *discordgo.MessageEmbed {
+func CreateBotEmbedLegacy(session discord.Client, title, description string, color int) *discordgo.MessageEmbed {
 	return NewEmbedBuilder().
 		SetTitle(title).
 		SetDescription(description).
@@ -228,14 +230,42 @@ func CreateApprovalEmbed(title, description string, author *discordgo.User) *dis
 	return builder.Build()
 }
 
+// BannedWordHit is one banned word found in a message, with its suggested replacements
+type BannedWordHit struct {
+	Word         string
+	Replacements []string
+}
+
+// suggestion formats a hit as "**ikke** → **ikkje**", or just the word without replacements
+func (h BannedWordHit) suggestion() string {
+	if len(h.Replacements) == 0 {
+		return fmt.Sprintf("**%s**", escapeAsterisks(h.Word))
+	}
+	replacements := make([]string, len(h.Replacements))
+	for i, replacement := range h.Replacements {
+		replacements[i] = escapeAsterisks(replacement)
+	}
+	return fmt.Sprintf("**%s** → **%s**", escapeAsterisks(h.Word), strings.Join(replacements, "** / **"))
+}
+
+// escapeAsterisks keeps patterns like *heten from breaking the surrounding bold text
+func escapeAsterisks(s string) string {
+	return strings.ReplaceAll(s, "*", "\\*")
+}
+
 // CreateBannedWordWarningEmbed creates standardized banned word warning embeds
-func CreateBannedWordWarningEmbed(bannedWords []string, forumThreads []string) *discordgo.MessageEmbed {
+func CreateBannedWordWarningEmbed(hits []BannedWordHit, forumThreads []string) *discordgo.MessageEmbed {
 	var warningText string
-	if len(bannedWords) == 1 {
-		warningText = fmt.Sprintf("⚠️ **Grammatisk merknad**\n\nOrdet **\"%s\"** er markert som feilaktig i norsk.", bannedWords[0])
+	if len(hits) == 1 {
+		warningText = fmt.Sprintf("⚠️ **Grammatisk merknad**\n\nOrdet **\"%s\"** er markert som feilaktig i norsk.", escapeAsterisks(hits[0].Word))
+		if len(hits[0].Replacements) > 0 {
+			warningText += "\n\n" + hits[0].suggestion()
+		}
 	} else {
-		warningText = fmt.Sprintf("⚠️ **Grammatisk merknad**\n\nDesse orda er markerte som feilaktige i norsk: **%s**",
-			fmt.Sprintf("%v", bannedWords))
+		warningText = "⚠️ **Grammatisk merknad**\n\nDesse orda er markerte som feilaktige i norsk:"
+		for _, hit := range hits {
+			warningText += "\n• " + hit.suggestion()
+		}
 	}
 
 	// Add forum thread references if available
@@ -257,7 +287,7 @@ func CreateBannedWordWarningEmbed(bannedWords []string, forumThreads []string) *
 			for i, threadID := range uniqueThreadList {
 				threadLinks[i] = fmt.Sprintf("<#%s>", threadID)
 			}
-			warningText += fmt.Sprintf("\n\nSjå diskusjonar: %s", fmt.Sprintf("%v", threadLinks))
+			warningText += fmt.Sprintf("\n\nSjå diskusjonar: %s", strings.Join(threadLinks, ", "))
 		}
 	} else {
 		warningText += "\n\nSjå grammatikkforumet for meir informasjon."
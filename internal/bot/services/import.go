@@ -0,0 +1,268 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxImportListLength keeps the word list of an import summary inside an embed description
+const maxImportListLength = 3500
+
+// ErrNoRettingChannel refuses an import that would leave words pending with nowhere to approve them
+var ErrNoRettingChannel = errors.New("retting-kanalen er ikkje sett opp, så importerte ord kan ikkje godkjennast der")
+
+// ImportResult is what a wordlist import did
+type ImportResult struct {
+	Added      []string // new words, pending or approved
+	Duplicates []string // already registered in any state, or repeated in the file
+	Invalid    []string // rows that could not be read, with line numbers
+	Approved   bool     // the new words were approved straight away
+}
+
+// ImportBannedWords adds the words of a wordlist that are not registered yet. With
+// approve, which callers only pass for someone holding both roles, they are approved
+// at once; otherwise they are pending and posted together to the retting channel in
+// one summary, approved or rejected as a batch. Pending words are removed again if the
+// summary cannot be posted, since nothing else would let anyone approve them.
+func (s *ApprovalService) ImportBannedWords(session discord.Client, list *bannedwords.Wordlist, importerID, importerName string, approve bool) (*ImportResult, error) {
+	if !approve && s.Bot.Config.BannedWords.ApprovalChannelID == "" {
+		return nil, ErrNoRettingChannel
+	}
+
+	result := &ImportResult{Invalid: list.Invalid, Duplicates: list.Repeated, Approved: approve}
+	var ids []int
+	for _, row := range list.Rows {
+		isBanned, _, err := s.Bot.Database.IsBannedWord(row.Word)
+		if err != nil {
+			log.Printf("Error checking if word '%s' exists: %v", row.Word, err)
+			result.Invalid = append(result.Invalid, fmt.Sprintf("linje %d: kunne ikkje sjekke «%s»", row.Line, row.Word))
+			continue
+		}
+		if isBanned {
+			result.Duplicates = append(result.Duplicates, row.Word)
+			continue
+		}
+
+		wordID, err := s.Bot.Database.AddBannedWordPending(row.Word, row.Reason, importerID, importerName, "", "")
+		if err != nil {
+			log.Printf("Error adding imported banned word '%s': %v", row.Word, err)
+			result.Invalid = append(result.Invalid, fmt.Sprintf("linje %d: kunne ikkje lagre «%s»", row.Line, row.Word))
+			continue
+		}
+		if len(row.Replacements) > 0 {
+			if err := s.Bot.Database.UpdateBannedWordReplacements(int(wordID), row.Replacements); err != nil {
+				log.Printf("Error saving replacements for '%s': %v", row.Word, err)
+			}
+		}
+		if approve {
+			if err := s.Bot.Database.ApproveBannedWordCombined(int(wordID), []string{importerID}, []string{importerID}); err != nil {
+				log.Printf("Error approving imported banned word '%s': %v", row.Word, err)
+				s.removeImported([]string{row.Word})
+				result.Invalid = append(result.Invalid, fmt.Sprintf("linje %d: kunne ikkje godkjenne «%s»", row.Line, row.Word))
+				continue
+			}
+		}
+		ids = append(ids, int(wordID))
+		result.Added = append(result.Added, row.Word)
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+	if approve {
+		s.Bot.BannedWords.Reload(s.Bot.Database)
+	}
+	if err := s.postImportSummary(session, ids, result.Added, importerID, approve); err != nil {
+		if !approve {
+			log.Printf("[IMPORT] Removing %d pending words that could not be posted for approval", len(ids))
+			s.removeImported(result.Added)
+			return nil, fmt.Errorf("kunne ikkje leggje ut importen i retting-kanalen, så ingen ord er lagde til: %w", err)
+		}
+		log.Printf("Failed to post summary of approved import: %v", err)
+	}
+	log.Printf("[IMPORT] %s imported %d banned words (approved: %v), %d duplicates, %d invalid rows",
+		importerName, len(result.Added), approve, len(result.Duplicates), len(result.Invalid))
+	return result, nil
+}
+
+// removeImported deletes words an import added but could not finish
+func (s *ApprovalService) removeImported(words []string) {
+	for _, word := range words {
+		if err := s.Bot.Database.RemoveBannedWord(word); err != nil {
+			log.Printf("Failed to remove imported banned word %s: %v", word, err)
+		}
+	}
+}
+
+// postImportSummary posts the words of an import to the retting channel, with batch
+// approval buttons unless they are already approved
+func (s *ApprovalService) postImportSummary(session discord.Client, ids []int, words []string, importerID string, approved bool) error {
+	channelID := s.Bot.Config.BannedWords.ApprovalChannelID
+	if channelID == "" {
+		return ErrNoRettingChannel
+	}
+
+	var message *discordgo.MessageSend
+	if approved {
+		embed := CreateBotEmbed(session, fmt.Sprintf("📥 Importert og godkjent: %d ord", len(words)),
+			fmt.Sprintf("%s\n\n**Importert og godkjent av:** <@%s>", importWordList(words), importerID), EmbedTypeSuccess)
+		message = &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+	} else {
+		message = &discordgo.MessageSend{
+			Embeds:     []*discordgo.MessageEmbed{s.importSummaryEmbed(session, words, importerID, permissions.NewApprovalState(nil))},
+			Components: ApprovalButtons(database.ApprovalEntityBannedWordImport, ids[0]),
+		}
+	}
+	posted, err := session.ChannelMessageSendComplex(channelID, message)
+	if err != nil {
+		log.Printf("Failed to post import summary to retting channel: %v", err)
+		return err
+	}
+	for _, id := range ids {
+		if err := s.Bot.Database.UpdateBannedWordImportMessageID(id, posted.ID); err != nil {
+			log.Printf("Failed to record import message for banned word %d: %v", id, err)
+			if !approved {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ApprovalService) approveImport(session discord.Client, i *discordgo.InteractionCreate, id int, userID string, role permissions.UserRole) {
+	words := s.pendingImport(id)
+	if len(words) == 0 {
+		respondApproval(session, i, "Importen er allereie handsama.")
+		return
+	}
+
+	for _, approvalRole := range role.ApprovalRoles() {
+		if err := s.Bot.Database.AddApproval(database.ApprovalEntityBannedWordImport, id, userID, approvalRole); err != nil {
+			log.Printf("Failed to record approval: %v", err)
+			respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+			return
+		}
+	}
+	approvals, err := s.Bot.Database.GetApprovals(database.ApprovalEntityBannedWordImport, id)
+	if err != nil {
+		respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+		return
+	}
+	state := permissions.NewApprovalState(approvals)
+	names := wordNames(words)
+
+	if !state.IsFullyApproved() {
+		missing := "rettskrivar"
+		if !state.HasOpplysarApproval {
+			missing = "opplysar"
+		}
+		respondApproval(session, i, fmt.Sprintf("👍 Godkjenninga di er registrert. Importen treng òg godkjenning frå ein %s.", missing))
+		s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, words[0].ImportMessageID,
+			s.importSummaryEmbed(session, names, words[0].AuthorID, state), ApprovalButtons(database.ApprovalEntityBannedWordImport, id))
+		return
+	}
+
+	approved := 0
+	for _, bw := range words {
+		if err := s.Bot.Database.ApproveBannedWordCombined(bw.ID, state.OpplysarApprovers, state.RettskrivarApprovers); err != nil {
+			log.Printf("Failed to approve imported banned word %s: %v", bw.Word, err)
+			continue
+		}
+		approved++
+	}
+	log.Printf("Import of %d banned words fully approved by combined roles", approved)
+	s.Bot.BannedWords.Reload(s.Bot.Database)
+	respondApproval(session, i, fmt.Sprintf("✅ %d ord er godkjende og lagde til i lista over feil ord.", approved))
+
+	embed := s.importSummaryEmbed(session, names, words[0].AuthorID, state)
+	embed.Title = fmt.Sprintf("📥 GODKJENT: %d importerte ord", approved)
+	embed.Color = ColorSuccess
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, words[0].ImportMessageID, embed, nil)
+}
+
+func (s *ApprovalService) rejectImport(session discord.Client, i *discordgo.InteractionCreate, id int, userID, reason string) {
+	reason = strings.TrimSpace(reason)
+	words := s.pendingImport(id)
+	if len(words) == 0 {
+		respondApproval(session, i, "Importen er allereie handsama.")
+		return
+	}
+	for _, bw := range words {
+		if err := s.Bot.Database.RejectBannedWord(bw.ID, userID, reason); err != nil {
+			log.Printf("Failed to reject imported banned word %s: %v", bw.Word, err)
+		}
+	}
+	log.Printf("Import of %d banned words rejected by %s: %s", len(words), userID, reason)
+	respondApproval(session, i, fmt.Sprintf("❌ Importen av %d ord er avvist.", len(words)))
+
+	embed := CreateBotEmbed(session, fmt.Sprintf("❌ AVVIST: %d importerte ord", len(words)),
+		fmt.Sprintf("%s\n\n**Importert av:** <@%s>\n**Avvist av:** <@%s>\n**Grunn:** %s", importWordList(wordNames(words)), words[0].AuthorID, userID, reason), EmbedTypeError)
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, words[0].ImportMessageID, embed, nil)
+}
+
+// pendingImport returns the words of the import that added word id that still await
+// approval, or nil if the import has been handled
+func (s *ApprovalService) pendingImport(id int) []*database.BannedWord {
+	bannedWord, err := s.Bot.Database.GetBannedWordByID(id)
+	if err != nil || bannedWord == nil || bannedWord.ImportMessageID == nil {
+		return nil
+	}
+	batch, err := s.Bot.Database.GetBannedWordsByImportMessageID(*bannedWord.ImportMessageID)
+	if err != nil {
+		log.Printf("Failed to get imported banned words: %v", err)
+		return nil
+	}
+	var pending []*database.BannedWord
+	for _, bw := range batch {
+		if bw.ApprovalStatus == "pending" {
+			pending = append(pending, bw)
+		}
+	}
+	return pending
+}
+
+// importSummaryEmbed builds the approval post for an import, coloured like a report
+func (s *ApprovalService) importSummaryEmbed(session discord.Client, words []string, importerID string, state *permissions.ApprovalState) *discordgo.MessageEmbed {
+	importer, err := session.User(importerID)
+	if err != nil {
+		importer = &discordgo.User{ID: importerID, Username: "Ukjend"}
+	}
+	embed := CreateApprovalEmbed(fmt.Sprintf("📥 Importerte ord: %d", len(words)),
+		fmt.Sprintf("%s\n\n**Importert av:** <@%s>\n\n%s", importWordList(words), importerID, state.GetApprovalSummary(session)), importer)
+	if state.HasOpplysarApproval || state.HasRettskrivarApproval {
+		embed.Color = ColorWarning
+	}
+	return embed
+}
+
+// importWordList lists imported words, cut short to fit an embed
+func importWordList(words []string) string {
+	list := ""
+	for n, word := range words {
+		next := "**" + word + "**"
+		if n > 0 {
+			next = ", " + next
+		}
+		if len(list)+len(next) > maxImportListLength {
+			return fmt.Sprintf("%s … og %d til", list, len(words)-n)
+		}
+		list += next
+	}
+	return list
+}
+
+func wordNames(words []*database.BannedWord) []string {
+	names := make([]string, len(words))
+	for i, bw := range words {
+		names[i] = bw.Word
+	}
+	return names
+}
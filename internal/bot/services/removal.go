@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+// RequestBannedWordRemoval stores a request to take an approved word off the list and
+// posts it to the retting channel, where it needs an opplysar and a rettskrivar like a report.
+func (s *ApprovalService) RequestBannedWordRemoval(session discord.Client, bannedWord *database.BannedWord, reason, requesterID string) error {
+	id, err := s.Bot.Database.AddBannedWordRemoval(bannedWord.ID, bannedWord.Word, reason, requesterID)
+	if err != nil {
+		return err
+	}
+	log.Printf("Removal of banned word %s requested by %s", bannedWord.Word, requesterID)
+
+	channelID := s.Bot.Config.BannedWords.ApprovalChannelID
+	if channelID == "" {
+		log.Println("Retting channel is not configured")
+		return nil
+	}
+	removal, err := s.Bot.Database.GetBannedWordRemoval(int(id))
+	if err != nil {
+		return err
+	}
+	message, err := session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{s.removalApprovalEmbed(session, removal, permissions.NewApprovalState(nil))},
+		Components: ApprovalButtons(database.ApprovalEntityBannedWordRemoval, removal.ID),
+	})
+	if err != nil {
+		log.Printf("Failed to post removal request to retting channel: %v", err)
+		return nil
+	}
+	return s.Bot.Database.UpdateBannedWordRemovalMessageID(removal.ID, message.ID)
+}
+
+func (s *ApprovalService) approveRemoval(session discord.Client, i *discordgo.InteractionCreate, id int, userID string, role permissions.UserRole) {
+	removal := s.pendingRemoval(id)
+	if removal == nil {
+		respondApproval(session, i, "Førespurnaden er allereie handsama.")
+		return
+	}
+
+	for _, approvalRole := range role.ApprovalRoles() {
+		if err := s.Bot.Database.AddApproval(database.ApprovalEntityBannedWordRemoval, id, userID, approvalRole); err != nil {
+			log.Printf("Failed to record approval: %v", err)
+			respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+			return
+		}
+	}
+	approvals, err := s.Bot.Database.GetApprovals(database.ApprovalEntityBannedWordRemoval, id)
+	if err != nil {
+		respondApproval(session, i, "Kunne ikkje lagre godkjenninga.")
+		return
+	}
+	state := permissions.NewApprovalState(approvals)
+
+	if !state.IsFullyApproved() {
+		missing := "rettskrivar"
+		if !state.HasOpplysarApproval {
+			missing = "opplysar"
+		}
+		respondApproval(session, i, fmt.Sprintf("👍 Godkjenninga di er registrert. Fjerninga treng òg godkjenning frå ein %s.", missing))
+		s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, removal.ApprovalMessageID,
+			s.removalApprovalEmbed(session, removal, state), ApprovalButtons(database.ApprovalEntityBannedWordRemoval, id))
+		return
+	}
+
+	bannedWord, err := s.Bot.Database.GetBannedWordByID(removal.WordID)
+	if err != nil {
+		log.Printf("Failed to get banned word %d for removal: %v", removal.WordID, err)
+	}
+	if err := s.Bot.Database.ApproveBannedWordRemoval(id, state.OpplysarApprovers, state.RettskrivarApprovers); err != nil {
+		log.Printf("Failed to approve removal: %v", err)
+		if s.pendingRemoval(id) != nil {
+			respondApproval(session, i, "Kunne ikkje fjerne ordet. Prøv igjen.")
+		} else {
+			respondApproval(session, i, "Førespurnaden er allereie handsama.")
+		}
+		return
+	}
+	log.Printf("Banned word %s removed, approved by %v and %v", removal.Word, state.OpplysarApprovers, state.RettskrivarApprovers)
+	s.Bot.BannedWords.Reload(s.Bot.Database)
+	respondApproval(session, i, fmt.Sprintf("🗑️ «%s» er fjerna frå lista over feil ord.", removal.Word))
+
+	if bannedWord != nil && bannedWord.ForumThreadID != nil && *bannedWord.ForumThreadID != "" {
+		s.closeForumThread(session, *bannedWord.ForumThreadID, removal, state)
+	}
+
+	embed := s.removalApprovalEmbed(session, removal, state)
+	embed.Title = fmt.Sprintf("🗑️ FJERNA: %s", removal.Word)
+	embed.Color = ColorSuccess
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, removal.ApprovalMessageID, embed, nil)
+}
+
+func (s *ApprovalService) rejectRemoval(session discord.Client, i *discordgo.InteractionCreate, id int, userID, reason string) {
+	reason = strings.TrimSpace(reason)
+	removal := s.pendingRemoval(id)
+	if removal == nil {
+		respondApproval(session, i, "Førespurnaden er allereie handsama.")
+		return
+	}
+	if err := s.Bot.Database.RejectBannedWordRemoval(id, userID, reason); err != nil {
+		log.Printf("Failed to reject removal: %v", err)
+		respondApproval(session, i, "Førespurnaden er allereie handsama.")
+		return
+	}
+	log.Printf("Removal of banned word %s rejected by %s: %s", removal.Word, userID, reason)
+	respondApproval(session, i, fmt.Sprintf("❌ «%s» blir verande på lista.", removal.Word))
+
+	embed := CreateBotEmbed(session, "❌ BEHALDE", fmt.Sprintf("**Ord:** %s\n**Fjerning bede om av:** <@%s>\n**Avvist av:** <@%s>\n**Grunn:** %s", removal.Word, removal.RequestedBy, userID, reason), EmbedTypeError)
+	s.editApprovalMessage(session, s.Bot.Config.BannedWords.ApprovalChannelID, removal.ApprovalMessageID, embed, nil)
+}
+
+// closeForumThread says in a removed word's discussion thread who removed it and why,
+// then archives and locks the thread
+func (s *ApprovalService) closeForumThread(session discord.Client, threadID string, removal *database.BannedWordRemoval, state *permissions.ApprovalState) {
+	embed := CreateBotEmbed(session, "🗑️ Ordet er fjerna frå lista",
+		fmt.Sprintf("**%s** er ikkje lenger på lista over feil ord.\n\n%s", removal.Word, removalDetails(session, removal, state)), EmbedTypeInfo)
+	if _, err := session.ChannelMessageSendEmbed(threadID, embed); err != nil {
+		log.Printf("Failed to post removal to forum thread %s: %v", threadID, err)
+	}
+
+	closed := true
+	if _, err := session.ChannelEdit(threadID, &discordgo.ChannelEdit{Archived: &closed, Locked: &closed}); err != nil {
+		log.Printf("Failed to archive forum thread %s: %v", threadID, err)
+	}
+}
+
+// pendingRemoval returns the removal request if it is still awaiting approval, or nil
+func (s *ApprovalService) pendingRemoval(id int) *database.BannedWordRemoval {
+	removal, err := s.Bot.Database.GetBannedWordRemoval(id)
+	if err != nil || removal.Status != database.RemovalStatusPending {
+		return nil
+	}
+	return removal
+}
+
+// removalApprovalEmbed builds the approval post for a removal request, coloured like a report
+func (s *ApprovalService) removalApprovalEmbed(session discord.Client, removal *database.BannedWordRemoval, state *permissions.ApprovalState) *discordgo.MessageEmbed {
+	requester, err := session.User(removal.RequestedBy)
+	if err != nil {
+		requester = &discordgo.User{ID: removal.RequestedBy, Username: "Ukjend"}
+	}
+	embed := CreateApprovalEmbed(fmt.Sprintf("🗑️ Fjerne «%s» frå lista?", removal.Word), removalDetails(session, removal, state), requester)
+	if state.HasOpplysarApproval || state.HasRettskrivarApproval {
+		embed.Color = ColorWarning
+	}
+	return embed
+}
+
+// removalDetails lists the reason for a removal and who approved it
+func removalDetails(session discord.Client, removal *database.BannedWordRemoval, state *permissions.ApprovalState) string {
+	reason := removal.Reason
+	if reason == "" {
+		reason = "Inga grunngjeving"
+	}
+	return fmt.Sprintf("**Grunn:** %s\n**Bede om av:** <@%s>\n\n%s", reason, removal.RequestedBy, state.GetApprovalSummary(session))
+}
@@ -8,7 +8,7 @@ import (
 
 // GetPratsamRoleID retrieves the ID of the "pratsam" role for the given guild
 func GetPratsamRoleID(bot *bot.Bot, guildID string) (string, error) {
-	roles, err := bot.Session.GuildRoles(guildID)
+	roles, err := bot.Discord.GuildRoles(guildID)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// lifecycle is the bot's root context and the event handlers running under it
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	running  sync.WaitGroup
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Context is the bot's root context, cancelled when the bot starts shutting down.
+// Background work should stop when it is done.
+func (b *Bot) Context() context.Context {
+	return b.lifecycle.ctx
+}
+
+// Shutdown asks the bot to shut down, the same way SIGTERM does. It returns at once;
+// main sees the cancelled context, stops the workers and drains the handlers.
+func (b *Bot) Shutdown() {
+	log.Println("[BOT] Shutdown requested")
+	b.lifecycle.cancel()
+}
+
+// Track registers a running event handler, which must call done when it returns.
+// It reports false once the bot is draining, and the event should then be dropped.
+func (b *Bot) Track() (done func(), ok bool) {
+	l := b.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.draining {
+		return nil, false
+	}
+	l.running.Add(1)
+	return l.running.Done, true
+}
+
+// Drain stops new events from being handled and waits up to timeout for the running
+// handlers to finish. It reports whether they all did.
+func (b *Bot) Drain(timeout time.Duration) bool {
+	l := b.lifecycle
+	l.mu.Lock()
+	l.draining = true
+	l.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		l.running.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return true
+	case <-time.After(timeout):
+		log.Printf("[BOT] Handlers still running after %v, shutting down anyway", timeout)
+		return false
+	}
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestShutdownCancelsContext(t *testing.T) {
+	b := New(nil, nil, nil)
+	if b.Context().Err() != nil {
+		t.Fatalf("context cancelled before shutdown")
+	}
+	b.Shutdown()
+	select {
+	case <-b.Context().Done():
+	default:
+		t.Fatalf("context not cancelled by Shutdown")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	b := New(nil, nil, nil)
+	done, ok := b.Track()
+	if !ok {
+		t.Fatalf("Track refused work before draining")
+	}
+
+	finished := make(chan bool)
+	go func() { finished <- b.Drain(time.Second) }()
+	// Events arriving while draining are dropped, the running one is waited for
+	for {
+		early, ok := b.Track()
+		if !ok {
+			break
+		}
+		early()
+		time.Sleep(time.Millisecond)
+	}
+	done()
+	if !<-finished {
+		t.Errorf("Drain timed out with no handlers left")
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	b := New(nil, nil, nil)
+	b.Track()
+	if b.Drain(10 * time.Millisecond) {
+		t.Errorf("Drain reported success with a handler still running")
+	}
+}
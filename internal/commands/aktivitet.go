@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["aktivitet"] = Command{
+		name:        "aktivitet",
+		description: "Vis kor aktiv hovudkanalen har vore, slik planleggaren ser det",
+		emoji:       "📈",
+		handler:     handleAktivitet,
+		adminOnly:   true,
+		category:    CategoryAdmin,
+	}
+}
+
+func handleAktivitet(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
+	now := time.Now()
+	snapshot := bot.Activity.Snapshot(now)
+	if snapshot.ChannelID == "" {
+		embed := services.CreateBotEmbed(s, "📈 Aktivitet", "Hovudkanalen er ikkje sett opp, så aktiviteten blir ikkje følgd.", services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	last := "Ingen enno"
+	if !snapshot.LastActivity.IsZero() {
+		last = fmt.Sprintf("<t:%d:R> (<t:%d:f>)", snapshot.LastActivity.Unix(), snapshot.LastActivity.Unix())
+	}
+	embed := services.NewEmbedBuilder().
+		SetTitle("📈 Aktivitet").
+		SetColorByType(services.EmbedTypeInfo).
+		SetAuthorFromBot(s).
+		AddField("Kanal", fmt.Sprintf("<#%s>", snapshot.ChannelID), true).
+		AddField("Sist aktiv", last, true).
+		AddField("Skribentar siste "+formatWindow(snapshot.Window), fmt.Sprintf("%d (treng %d)", snapshot.RecentAuthors, snapshot.MinAuthors), true).
+		Build()
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
+
+// formatWindow writes an activity window as hours, or minutes if shorter
+func formatWindow(window time.Duration) string {
+	if window < time.Hour {
+		return fmt.Sprintf("%d min", int(window.Minutes()))
+	}
+	return fmt.Sprintf("%d t", int(window.Hours()))
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ArgType is the kind of value a command argument takes
+type ArgType int
+
+const (
+	ArgString     ArgType = iota // a single word
+	ArgInt                       // a whole number
+	ArgRest                      // the rest of the line; must be the last argument
+	ArgUser                      // a user mention or ID
+	ArgChannel                   // a channel mention or ID
+	ArgEnum                      // one of Choices
+	ArgAttachment                // a file attached to the message; prefix commands give it no text
+)
+
+// Arg declares one argument of a command
+type Arg struct {
+	Name        string
+	Description string
+	Type        ArgType
+	Optional    bool
+	// Choices are accepted as-is. For ArgEnum they are the only valid values;
+	// for other types they are keywords accepted in place of a value, e.g. "neste" for an ID.
+	Choices []string
+}
+
+// Args holds the parsed arguments of one command invocation
+type Args map[string]interface{}
+
+// Has reports whether the argument was given
+func (a Args) Has(name string) bool {
+	_, exists := a[name]
+	return exists
+}
+
+// String returns the argument as text: the word, line, choice, or user/channel ID
+func (a Args) String(name string) string {
+	switch value := a[name].(type) {
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	}
+	return ""
+}
+
+// Int returns the argument as a number, and false if it was not given or was a choice keyword
+func (a Args) Int(name string) (int, bool) {
+	value, ok := a[name].(int)
+	return value, ok
+}
+
+// ArgError is a user mistake in a command's arguments
+type ArgError struct {
+	Message string
+}
+
+func (e *ArgError) Error() string {
+	return e.Message
+}
+
+var (
+	userMention    = regexp.MustCompile(`^(?:<@!?(\d+)>|(\d+))$`)
+	channelMention = regexp.MustCompile(`^(?:<#(\d+)>|(\d+))$`)
+)
+
+// parseArgs parses the text after a command name against the command's argument spec
+func parseArgs(spec []Arg, input string) (Args, error) {
+	args := make(Args)
+	rest := strings.TrimSpace(input)
+
+	for _, arg := range spec {
+		if arg.Type == ArgAttachment {
+			continue
+		}
+		var raw string
+		if arg.Type == ArgRest {
+			raw, rest = rest, ""
+		} else {
+			raw, rest = nextWord(rest)
+		}
+		if err := args.set(arg, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if rest != "" {
+		return nil, &ArgError{Message: fmt.Sprintf("For mange argument: «%s».", rest)}
+	}
+	return args, nil
+}
+
+// parseOptions parses slash command options against the command's argument spec
+func parseOptions(spec []Arg, options []*discordgo.ApplicationCommandInteractionDataOption) (Args, error) {
+	given := make(map[string]string, len(options))
+	for _, option := range options {
+		given[option.Name] = optionText(option)
+	}
+
+	args := make(Args)
+	for _, arg := range spec {
+		if err := args.set(arg, given[arg.Name]); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// optionText returns a slash command option value as the text a prefix command would have had
+func optionText(option *discordgo.ApplicationCommandInteractionDataOption) string {
+	switch value := option.Value.(type) {
+	case float64: // JSON numbers
+		return strconv.FormatInt(int64(value), 10)
+	case string:
+		return value
+	}
+	return fmt.Sprint(option.Value)
+}
+
+func nextWord(input string) (word, rest string) {
+	end := strings.IndexFunc(input, unicode.IsSpace)
+	if end < 0 {
+		return input, ""
+	}
+	return input[:end], strings.TrimSpace(input[end:])
+}
+
+// set validates raw against arg and stores the parsed value
+func (a Args) set(arg Arg, raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		if arg.Optional {
+			return nil
+		}
+		return &ArgError{Message: fmt.Sprintf("Du må oppgje `%s`.", arg.Name)}
+	}
+
+	for _, choice := range arg.Choices {
+		if strings.EqualFold(raw, choice) {
+			a[arg.Name] = choice
+			return nil
+		}
+	}
+
+	switch arg.Type {
+	case ArgInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			if len(arg.Choices) > 0 {
+				return &ArgError{Message: fmt.Sprintf("`%s` må vere eit tal eller ein av %s, ikkje «%s».", arg.Name, choiceList(arg.Choices), raw)}
+			}
+			return &ArgError{Message: fmt.Sprintf("`%s` må vere eit tal, ikkje «%s».", arg.Name, raw)}
+		}
+		a[arg.Name] = n
+	case ArgUser:
+		match := userMention.FindStringSubmatch(raw)
+		if match == nil {
+			return &ArgError{Message: fmt.Sprintf("`%s` må vere ein brukar, til dømes @namn, ikkje «%s».", arg.Name, raw)}
+		}
+		a[arg.Name] = match[1] + match[2]
+	case ArgChannel:
+		match := channelMention.FindStringSubmatch(raw)
+		if match == nil {
+			return &ArgError{Message: fmt.Sprintf("`%s` må vere ein kanal, til dømes #namn, ikkje «%s».", arg.Name, raw)}
+		}
+		a[arg.Name] = match[1] + match[2]
+	case ArgEnum:
+		return &ArgError{Message: fmt.Sprintf("`%s` må vere ein av %s, ikkje «%s».", arg.Name, choiceList(arg.Choices), raw)}
+	default:
+		a[arg.Name] = raw
+	}
+	return nil
+}
+
+func choiceList(choices []string) string {
+	quoted := make([]string, len(choices))
+	for i, choice := range choices {
+		quoted[i] = "«" + choice + "»"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Usage returns how to call a command, e.g. "?godkjenn <spørsmål|neste|alle>"
+func Usage(cmd Command, prefix string) string {
+	parts := []string{prefix + cmd.name}
+	for _, arg := range cmd.args {
+		placeholder := arg.Name
+		if arg.Type == ArgEnum {
+			placeholder = strings.Join(arg.Choices, "|")
+		} else if len(arg.Choices) > 0 {
+			placeholder += "|" + strings.Join(arg.Choices, "|")
+		}
+		if arg.Type == ArgRest {
+			placeholder += "..."
+		}
+		if arg.Optional {
+			parts = append(parts, "["+placeholder+"]")
+		} else {
+			parts = append(parts, "<"+placeholder+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// applicationCommandOptions returns the slash command options for an argument spec
+func applicationCommandOptions(spec []Arg) []*discordgo.ApplicationCommandOption {
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(spec))
+	for _, arg := range spec {
+		option := &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        arg.Name,
+			Description: arg.Description,
+			Required:    !arg.Optional,
+		}
+		switch {
+		case arg.Type == ArgInt && len(arg.Choices) == 0:
+			option.Type = discordgo.ApplicationCommandOptionInteger
+		case arg.Type == ArgUser:
+			option.Type = discordgo.ApplicationCommandOptionUser
+		case arg.Type == ArgChannel:
+			option.Type = discordgo.ApplicationCommandOptionChannel
+		case arg.Type == ArgAttachment:
+			option.Type = discordgo.ApplicationCommandOptionAttachment
+		case arg.Type == ArgEnum:
+			for _, choice := range arg.Choices {
+				option.Choices = append(option.Choices, &discordgo.ApplicationCommandOptionChoice{Name: choice, Value: choice})
+			}
+		}
+		options = append(options, option)
+	}
+	return options
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["avbann"] = Command{
+		name:        "avbann",
+		description: "Be om at eit godkjent ord blir fjerna frå lista (kun for opplysarar og rettskrivarar)",
+		emoji:       "🗑️",
+		handler:     handleAvbann,
+		args: []Arg{
+			{Name: "ord", Description: "Det forbodne ordet", Type: ArgString},
+			{Name: "grunn", Description: "Kvifor ordet ikkje skal vere på lista", Type: ArgRest},
+		},
+		category: CategoryWords,
+		examples: []string{"ikke Det er lov i nynorsk òg"},
+	}
+}
+
+func handleAvbann(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	if permissions.NewPermissionManager(bot.Config).GetUserRole(s, m.GuildID, m.Author.ID) == permissions.RoleNone {
+		embed := services.CreateBotEmbed(s, "⛔ Ingen tilgang", "Berre opplysarar og rettskrivarar kan be om at ord blir fjerna.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	word := args.String("ord")
+	entry, err := bannedwords.ParseEntry(word)
+	var bannedWord *database.BannedWord
+	if err == nil {
+		_, bannedWord, err = bot.Database.IsBannedWord(entry.String())
+	}
+	if err != nil || bannedWord == nil || bannedWord.ApprovalStatus != "fully_approved" {
+		embed := services.CreateBotEmbed(s, "❓ Ukjent ord", fmt.Sprintf("«%s» er ikkje eit godkjent ord på lista.", word), services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	if _, err := bot.Database.GetPendingBannedWordRemoval(bannedWord.ID); err == nil {
+		embed := services.CreateBotEmbed(s, "⏳ Allereie førespurt", fmt.Sprintf("Det er alt bede om at «%s» blir fjerna. Førespurnaden ventar på godkjenning.", bannedWord.Word), services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	if err := (&services.ApprovalService{Bot: bot}).RequestBannedWordRemoval(s, bannedWord, args.String("grunn"), m.Author.ID); err != nil {
+		log.Printf("Failed to request removal of %s: %v", bannedWord.Word, err)
+		embed := services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje lagre førespurnaden.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	embed := services.CreateBotEmbed(s, "🗑️ Fjerning førespurd", fmt.Sprintf("«%s» blir fjerna når ein opplysar og ein rettskrivar har godkjent det.", bannedWord.Word), services.EmbedTypeSuccess)
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
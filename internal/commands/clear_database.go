@@ -5,6 +5,7 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -15,11 +16,12 @@ func init() {
 		emoji:       "🗑️",
 		handler:     ClearDatabase,
 		adminOnly:   true,
+		category:    CategoryAdmin,
 	}
 }
 
 // ClearDatabase handles the command to clear the database
-func ClearDatabase(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func ClearDatabase(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
 	// Send a confirmation message with a button
 	confirmationEmbed := &discordgo.MessageEmbed{
 		Title:       "🗑️ Stadfesting av databasetømming",
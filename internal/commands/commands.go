@@ -3,10 +3,13 @@ package commands
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -15,16 +18,30 @@ type Command struct {
 	name        string
 	description string
 	emoji       string
-	handler     func(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot)
+	handler     func(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args)
 	aliases     []string
 	adminOnly   bool
+	args        []Arg // parsed before the handler runs, in the order the prefix command takes them
+	category    string
+	examples    []string      // argument text for the help page, e.g. "neste" for godkjenn
+	cooldown    time.Duration // minimum time between uses per user
 }
 
+// Command categories, in the order the help list shows them
+const (
+	CategoryGeneral   = "Generelt"
+	CategoryQuestions = "Spørsmål"
+	CategoryWords     = "Ord"
+	CategoryAdmin     = "Administrasjon"
+)
+
+var categoryOrder = []string{CategoryGeneral, CategoryQuestions, CategoryWords, CategoryAdmin}
+
 // commands holds all the registered commands
 var commands = make(map[string]Command)
 
 // MatchAndRunCommand finds and executes a command based on its name or alias.
-func MatchAndRunCommand(input string, s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func MatchAndRunCommand(input string, s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot) {
 	// `input` is the command with prefix, e.g., "?spør"
 	// Remove prefix to get the actual command
 	commandWithoutPrefix := strings.TrimPrefix(input, bot.Config.Discord.Prefix)
@@ -37,7 +54,7 @@ func MatchAndRunCommand(input string, s *discordgo.Session, m *discordgo.Message
 	// Try to find command by name without prefix
 	if cmd, exists := commands[commandWithoutPrefix]; exists {
 		log.Printf("[DEBUG] Found command '%s', executing", commandWithoutPrefix)
-		cmd.handler(s, m, bot)
+		runCommand(cmd, strings.TrimPrefix(m.Content, input), s, m, bot)
 		return
 	}
 
@@ -47,7 +64,7 @@ func MatchAndRunCommand(input string, s *discordgo.Session, m *discordgo.Message
 		for _, alias := range cmd.aliases {
 			if alias == commandWithoutPrefix {
 				log.Printf("[DEBUG] Found alias '%s', executing", alias)
-				cmd.handler(s, m, bot)
+				runCommand(cmd, strings.TrimPrefix(m.Content, input), s, m, bot)
 				return
 			}
 		}
@@ -56,15 +73,59 @@ func MatchAndRunCommand(input string, s *discordgo.Session, m *discordgo.Message
 	log.Printf("[DEBUG] No command or alias found for '%s'", commandWithoutPrefix)
 }
 
-// Helper function to get command names for debugging
+// runCommand parses the arguments and runs the command, or explains what was wrong with them
+func runCommand(cmd Command, argsText string, s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot) {
+	args, err := parseArgs(cmd.args, argsText)
+	if err != nil {
+		sendArgError(s, m.ChannelID, cmd, bot.Config.Discord.Prefix, err)
+		return
+	}
+	execute(cmd, s, m, bot, args)
+}
+
+// execute runs a command with parsed arguments unless the user is still on cooldown
+func execute(cmd Command, s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	if remaining := cooldowns.use(cmd, m.Author.ID); remaining > 0 {
+		description := fmt.Sprintf("Du kan bruke `%s` att om %s.", cmd.name, formatDuration(remaining))
+		embed := services.CreateBotEmbed(s, "⏳ Vent litt", description, services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	cmd.handler(s, m, bot, args)
+}
+
+// sendArgError tells the user what was wrong with their arguments and how to use the command
+func sendArgError(s discord.Client, channelID string, cmd Command, prefix string, err error) {
+	description := fmt.Sprintf("%s\n\nBruk: `%s`", err.Error(), Usage(cmd, prefix))
+	embed := services.CreateBotEmbed(s, "❓ Feil", description, services.EmbedTypeError)
+	s.ChannelMessageSendEmbed(channelID, embed)
+}
+
+// Helper function to get command names, sorted
 func getCommandNames() []string {
 	var names []string
 	for name := range commands {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
+// findCommand looks a command up by name or alias
+func findCommand(name string) (Command, bool) {
+	if cmd, exists := commands[name]; exists {
+		return cmd, true
+	}
+	for _, cmd := range commands {
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return Command{}, false
+}
+
 // IsAdminCommand checks if a command is admin-only
 func IsAdminCommand(commandName string) bool {
 	// Remove prefix from command name for lookup
@@ -76,49 +137,3 @@ func IsAdminCommand(commandName string) bool {
 	}
 	return false
 }
-
-// GetHelpText generates the help text for all commands.
-func GetHelpText() string {
-	var helpText strings.Builder
-	helpText.WriteString("**Askeladden - Kommandoer:**\n")
-
-	for _, cmd := range commands {
-		helpText.WriteString(fmt.Sprintf("%s `%s` - %s\n", cmd.emoji, cmd.name, cmd.description))
-	}
-
-	return strings.TrimSpace(helpText.String())
-}
-
-// ListCommands lists commands based on admin status
-func ListCommands(isAdmin bool) *discordgo.MessageEmbed {
-	var generalCommands, adminCommands strings.Builder
-
-	for _, cmd := range commands {
-		commandLine := fmt.Sprintf("%s `%s` - %s\n", cmd.emoji, cmd.name, cmd.description)
-		if cmd.adminOnly {
-			adminCommands.WriteString(commandLine)
-		} else {
-			generalCommands.WriteString(commandLine)
-		}
-	}
-
-	embed := &discordgo.MessageEmbed{
-		Title: "Askeladden - Kommandoer",
-		Color: services.ColorInfo, // Blue color
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  "Generelle kommandoer",
-				Value: generalCommands.String(),
-			},
-		},
-	}
-
-	if isAdmin && adminCommands.Len() > 0 {
-		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:  "Admin-kommandoer",
-			Value: adminCommands.String(),
-		})
-	}
-
-	return embed
-}
@@ -0,0 +1,939 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"askeladden/internal/activity"
+	"askeladden/internal/bot"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/fake"
+	"askeladden/internal/scheduler"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	testGuild      = "guild"
+	testChannel    = "general"
+	testQueue      = "queue"
+	testOpplysar   = "opplysar-role"
+	testPratsam    = "pratsam-role"
+	testBotRole    = "bot-role"
+	testBotID      = "bot"
+	testAuthorID   = "author"
+	testAuthorName = "kari"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// newTestBot returns a bot wired to an in-memory database and a recording fake
+func newTestBot(t *testing.T) (*bot.Bot, *fake.Client) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Discord.Prefix = "?"
+	cfg.Approval.QueueChannelID = testQueue
+	cfg.Approval.OpplysarRoleID = testOpplysar
+
+	client := fake.New(testBotID)
+	client.AddChannel(testGuild, testChannel, "general")
+	client.AddChannel(testGuild, testQueue, "godkjenning")
+	client.AddUser(testAuthorID, testAuthorName)
+
+	b := bot.New(cfg, database.NewMemory(), nil)
+	b.Discord = client
+	cooldowns = newCooldownTracker()
+	return b, client
+}
+
+// message builds a MessageCreate event from the test author
+func message(content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:        "msg",
+		ChannelID: testChannel,
+		GuildID:   testGuild,
+		Content:   content,
+		Author:    &discordgo.User{ID: testAuthorID, Username: testAuthorName},
+	}}
+}
+
+// run dispatches a message the way the message handler does, so arguments are parsed first
+func run(client *fake.Client, m *discordgo.MessageCreate, b *bot.Bot) {
+	MatchAndRunCommand(strings.Fields(m.Content)[0], client, m, b)
+}
+
+// titles returns the embed titles of the given messages
+func titles(messages []*discordgo.Message) []string {
+	var result []string
+	for _, msg := range messages {
+		for _, embed := range msg.Embeds {
+			result = append(result, embed.Title)
+		}
+	}
+	return result
+}
+
+func TestSpor(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantTitle    string
+		wantQuestion string
+		wantApproval bool
+		wantDM       bool
+	}{
+		{name: "missing question", content: "?spør", wantTitle: "❓ Feil"},
+		{name: "blank question", content: "?spør    ", wantTitle: "❓ Feil"},
+		{
+			name:         "question is queued",
+			content:      "?spør Kva et du til frukost?",
+			wantTitle:    "📝 Spørsmål motteke!",
+			wantQuestion: "Kva et du til frukost?",
+			wantApproval: true,
+			wantDM:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, client := newTestBot(t)
+
+			run(client, message(tt.content), b)
+
+			sent := client.SentTo(testChannel)
+			if len(sent) != 1 || titles(sent)[0] != tt.wantTitle {
+				t.Fatalf("channel replies = %v, want one %q", titles(sent), tt.wantTitle)
+			}
+
+			if got := len(client.SentTo("dm-" + testAuthorID)); (got > 0) != tt.wantDM {
+				t.Errorf("DMs to author = %d, want DM: %v", got, tt.wantDM)
+			}
+
+			queued := client.SentTo(testQueue)
+			if !tt.wantApproval {
+				if len(queued) != 0 {
+					t.Fatalf("posted %d messages to the approval queue, want none", len(queued))
+				}
+				return
+			}
+			if len(queued) != 1 || titles(queued)[0] != tt.wantQuestion {
+				t.Fatalf("approval queue = %v, want %q", titles(queued), tt.wantQuestion)
+			}
+			if len(queued[0].Components) != 1 {
+				t.Errorf("approval message components = %+v, want one row of buttons", queued[0].Components)
+			}
+
+			question, err := b.Database.GetQuestionByApprovalMessageID(queued[0].ID)
+			if err != nil {
+				t.Fatalf("question not linked to approval message: %v", err)
+			}
+			if question.Question != tt.wantQuestion || question.ApprovalStatus != "pending" || question.AuthorID != testAuthorID {
+				t.Errorf("stored question = %+v", question)
+			}
+		})
+	}
+}
+
+func TestGodkjenn(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		seed         bool
+		wantTitle    string
+		wantApproved bool
+	}{
+		{name: "missing argument", content: "?godkjenn", wantTitle: "❓ Feil"},
+		{name: "no pending questions", content: "?godkjenn neste", wantTitle: "🎉 Ingen ventande spørsmål!"},
+		{name: "next pending question", content: "?godkjenn neste", seed: true, wantTitle: "✅ Spørsmål godkjent!", wantApproved: true},
+		{name: "by ID", content: "?godkjenn 1", seed: true, wantTitle: "✅ Spørsmål godkjent!", wantApproved: true},
+		{name: "invalid ID", content: "?godkjenn éin", seed: true, wantTitle: "❓ Feil"},
+		{name: "too many arguments", content: "?godkjenn 1 2", seed: true, wantTitle: "❓ Feil"},
+		{name: "unknown ID", content: "?godkjenn 42", seed: true, wantTitle: "❌ Feil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, client := newTestBot(t)
+			if tt.seed {
+				if _, err := b.Database.AddQuestion("Kva les du no?", testAuthorID, testAuthorName, "m1", testChannel); err != nil {
+					t.Fatalf("AddQuestion: %v", err)
+				}
+			}
+
+			run(client, message(tt.content), b)
+
+			sent := client.SentTo(testChannel)
+			if len(sent) != 1 || titles(sent)[0] != tt.wantTitle {
+				t.Fatalf("channel replies = %v, want one %q", titles(sent), tt.wantTitle)
+			}
+
+			pending, approved, _, err := b.Database.GetApprovalStats()
+			if err != nil {
+				t.Fatalf("GetApprovalStats: %v", err)
+			}
+			if (approved == 1) != tt.wantApproved {
+				t.Errorf("approved = %d, pending = %d; want approved: %v", approved, pending, tt.wantApproved)
+			}
+
+			dms := client.SentTo("dm-" + testAuthorID)
+			if tt.wantApproved && (len(dms) != 1 || !strings.Contains(dms[0].Embeds[0].Description, testAuthorName)) {
+				t.Errorf("approval DM = %v, want one naming the approver", titles(dms))
+			}
+			if !tt.wantApproved && len(dms) != 0 {
+				t.Errorf("sent %d DMs without approving anything", len(dms))
+			}
+		})
+	}
+}
+
+func TestKjeften(t *testing.T) {
+	tests := []struct {
+		name        string
+		guildID     string
+		noRole      bool
+		hasRole     bool
+		botPosition int
+		wantTitle   string
+		wantChange  *fake.RoleChange
+	}{
+		{name: "outside a guild", guildID: "", botPosition: 10, wantTitle: "Feil"},
+		{name: "role missing", guildID: testGuild, noRole: true, botPosition: 10, wantTitle: "Feil"},
+		{name: "bot role too low", guildID: testGuild, botPosition: 1, wantTitle: "Feil"},
+		{
+			name:        "adds role",
+			guildID:     testGuild,
+			botPosition: 10,
+			wantTitle:   "Hei du! 📢",
+			wantChange:  &fake.RoleChange{GuildID: testGuild, UserID: testAuthorID, RoleID: testPratsam, Added: true},
+		},
+		{
+			name:        "removes role",
+			guildID:     testGuild,
+			hasRole:     true,
+			botPosition: 10,
+			wantTitle:   "Orsak! 🤐",
+			wantChange:  &fake.RoleChange{GuildID: testGuild, UserID: testAuthorID, RoleID: testPratsam, Added: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, client := newTestBot(t)
+			if !tt.noRole {
+				client.AddRole(testGuild, testPratsam, "Pratsam", 5)
+			}
+			client.AddRole(testGuild, testBotRole, "Askeladden", tt.botPosition)
+			client.AddMember(testGuild, testBotID, testBotRole)
+			if tt.hasRole {
+				client.AddMember(testGuild, testAuthorID, testPratsam)
+			} else {
+				client.AddMember(testGuild, testAuthorID)
+			}
+
+			m := message("?kjeften")
+			m.GuildID = tt.guildID
+			run(client, m, b)
+
+			sent := client.SentTo(testChannel)
+			if len(sent) != 1 || titles(sent)[0] != tt.wantTitle {
+				t.Fatalf("channel replies = %v, want one %q", titles(sent), tt.wantTitle)
+			}
+
+			if tt.wantChange == nil {
+				if len(client.RoleChanges) != 0 {
+					t.Fatalf("role changes = %+v, want none", client.RoleChanges)
+				}
+				return
+			}
+			if len(client.RoleChanges) != 1 || client.RoleChanges[0] != *tt.wantChange {
+				t.Fatalf("role changes = %+v, want %+v", client.RoleChanges, *tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestMatchAndRunCommand(t *testing.T) {
+	b, client := newTestBot(t)
+
+	MatchAndRunCommand("?spor", client, message("?spor Kva heiter katten din?"), b)
+	MatchAndRunCommand("?finst-ikkje", client, message("?finst-ikkje"), b)
+
+	if got := titles(client.SentTo(testChannel)); len(got) != 1 || got[0] != "📝 Spørsmål motteke!" {
+		t.Fatalf("replies = %v, want the alias to run spør once", got)
+	}
+}
+
+func TestApplicationCommands(t *testing.T) {
+	valid := regexp.MustCompile(`^[-_\p{Ll}\p{N}]{1,32}$`)
+
+	all := ApplicationCommands()
+	var registered []*discordgo.ApplicationCommand
+	var contextMenu []string
+	for _, cmd := range all {
+		if cmd.Type == discordgo.MessageApplicationCommand {
+			contextMenu = append(contextMenu, cmd.Name)
+		} else {
+			registered = append(registered, cmd)
+		}
+	}
+	if len(registered) != len(commands) {
+		t.Fatalf("got %d slash commands, want one per command (%d)", len(registered), len(commands))
+	}
+	if len(contextMenu) != 1 || contextMenu[0] != ReportWordCommandName {
+		t.Fatalf("context menu commands = %v, want %q", contextMenu, ReportWordCommandName)
+	}
+	for i, cmd := range registered {
+		if i > 0 && registered[i-1].Name >= cmd.Name {
+			t.Errorf("commands not sorted: %q before %q", registered[i-1].Name, cmd.Name)
+		}
+		if !valid.MatchString(cmd.Name) {
+			t.Errorf("%q is not a valid slash command name", cmd.Name)
+		}
+		if n := len([]rune(cmd.Description)); n == 0 || n > maxDescriptionLength {
+			t.Errorf("/%s description has %d characters", cmd.Name, n)
+		}
+		for _, option := range cmd.Options {
+			if !valid.MatchString(option.Name) || option.Description == "" {
+				t.Errorf("/%s has invalid option %+v", cmd.Name, option)
+			}
+		}
+	}
+}
+
+// slashCommand builds a slash command interaction from the test author
+func slashCommand(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        "interaction",
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: testChannel,
+		GuildID:   testGuild,
+		Member:    &discordgo.Member{User: &discordgo.User{ID: testAuthorID, Username: testAuthorName}},
+		Data:      discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+	}}
+}
+
+func TestMatchAndRunApplicationCommand(t *testing.T) {
+	b, client := newTestBot(t)
+
+	MatchAndRunApplicationCommand(client, slashCommand("spør", &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "spørsmål",
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: "Kva heiter katten din?",
+	}), b)
+
+	// The confirmation becomes the interaction reply; the DM and approval post are sent as usual
+	if len(client.InteractionResponses) != 1 {
+		t.Fatalf("interaction responses = %d, want 1", len(client.InteractionResponses))
+	}
+	reply := client.InteractionResponses[0].Response
+	if reply.Type != discordgo.InteractionResponseChannelMessageWithSource || reply.Data.Embeds[0].Title != "📝 Spørsmål motteke!" {
+		t.Fatalf("reply = %+v", reply.Data)
+	}
+	if got := len(client.SentTo("dm-" + testAuthorID)); got != 1 {
+		t.Errorf("DMs to author = %d, want 1", got)
+	}
+
+	question, err := b.Database.GetPendingQuestion()
+	if err != nil || question == nil {
+		t.Fatalf("GetPendingQuestion = %+v, %v", question, err)
+	}
+	replyMessage := client.SentTo(testChannel)[0]
+	if question.Question != "Kva heiter katten din?" || question.MessageID != replyMessage.ID {
+		t.Errorf("stored question = %+v, want it linked to reply %s", question, replyMessage.ID)
+	}
+}
+
+func TestMatchAndRunApplicationCommandAcknowledges(t *testing.T) {
+	b, client := newTestBot(t)
+	b.Config.Discord.DefaultChannelID = "daily"
+	id, err := b.Database.AddQuestion("Kva er best, sol eller snø?", testAuthorID, testAuthorName, "msg", testChannel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Database.ApproveQuestion(int(id), "opplysar"); err != nil {
+		t.Fatal(err)
+	}
+
+	// poke only posts to the daily question channel, so the interaction needs its own reply
+	MatchAndRunApplicationCommand(client, slashCommand("poke"), b)
+	MatchAndRunApplicationCommand(client, slashCommand("finst-ikkje"), b)
+
+	if got := titles(client.SentTo("daily")); len(got) != 1 {
+		t.Fatalf("daily channel = %v, want the daily question", got)
+	}
+	if len(client.InteractionResponses) != 2 {
+		t.Fatalf("interaction responses = %d, want 2", len(client.InteractionResponses))
+	}
+	for _, response := range client.InteractionResponses {
+		if response.Response.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Errorf("response %+v is not ephemeral", response.Response.Data)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	spec := []Arg{
+		{Name: "id", Type: ArgInt, Choices: []string{"neste"}},
+		{Name: "kven", Type: ArgUser, Optional: true},
+		{Name: "kvar", Type: ArgChannel, Optional: true},
+		{Name: "tekst", Type: ArgRest, Optional: true},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Args
+		wantErr string
+	}{
+		{name: "number", input: "42", want: Args{"id": 42}},
+		{name: "choice keyword", input: " NESTE ", want: Args{"id": "neste"}},
+		{name: "mentions", input: "1 <@!123> <#456>", want: Args{"id": 1, "kven": "123", "kvar": "456"}},
+		{name: "plain IDs", input: "1 123 456", want: Args{"id": 1, "kven": "123", "kvar": "456"}},
+		{name: "rest of line", input: "1 <@123> <#456>  hei  på   deg ", want: Args{"id": 1, "kven": "123", "kvar": "456", "tekst": "hei  på   deg"}},
+		{name: "missing required", input: "", wantErr: "Du må oppgje `id`."},
+		{name: "not a number", input: "x", wantErr: "`id` må vere eit tal eller ein av «neste», ikkje «x»."},
+		{name: "not a user", input: "1 kari", wantErr: "`kven` må vere ein brukar, til dømes @namn, ikkje «kari»."},
+		{name: "not a channel", input: "1 <@1> <@2>", wantErr: "`kvar` må vere ein kanal, til dømes #namn, ikkje «<@2>»."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(spec, tt.input)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("args = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseArgs([]Arg{{Name: "kva", Type: ArgEnum, Choices: []string{"alle"}, Optional: true}}, "nokon"); err == nil {
+		t.Errorf("enum accepted a value outside its choices")
+	}
+	if _, err := parseArgs(nil, "ekstra"); err == nil || err.Error() != "For mange argument: «ekstra»." {
+		t.Errorf("extra arguments err = %v", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	b, client := newTestBot(t)
+	b.Config.Discord.Prefix = "!"
+
+	tests := map[string]string{
+		"spør":     "!spør <spørsmål...>",
+		"godkjenn": "!godkjenn <spørsmål|neste|next|alle>",
+		"poke":     "!poke [alle]",
+		"ping":     "!ping",
+	}
+	for name, want := range tests {
+		if got := Usage(commands[name], b.Config.Discord.Prefix); got != want {
+			t.Errorf("Usage(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	// Argument errors use the configured prefix
+	MatchAndRunCommand("!godkjenn", client, message("!godkjenn"), b)
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 || !strings.Contains(sent[0].Embeds[0].Description, "`!godkjenn <spørsmål|neste|next|alle>`") {
+		t.Fatalf("argument error = %+v", sent)
+	}
+}
+
+// fields returns an embed's fields by name
+func fields(embed *discordgo.MessageEmbed) map[string]string {
+	result := make(map[string]string)
+	for _, field := range embed.Fields {
+		result[field.Name] = field.Value
+	}
+	return result
+}
+
+func TestHjelpCommandPage(t *testing.T) {
+	b, client := newTestBot(t)
+
+	run(client, message("?hjelp ?spor"), b)
+	run(client, message("?hjelp finst-ikkje"), b)
+
+	sent := client.SentTo(testChannel)
+	if len(sent) != 2 {
+		t.Fatalf("replies = %v, want two", titles(sent))
+	}
+	page := sent[0].Embeds[0]
+	if page.Title != "❓ ?spør" {
+		t.Errorf("title = %q", page.Title)
+	}
+	want := map[string]string{
+		"Bruk":       "`?spør <spørsmål...>`",
+		"Argument":   "`spørsmål` - Spørsmålet du vil leggje til",
+		"Alias":      "`?spor`",
+		"Døme":       "`?spør Kva er yndlingsmaten din?`",
+		"Krev rolle": "Ingen",
+		"Ventetid":   "30 sekund",
+	}
+	if got := fields(page); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+	if sent[1].Embeds[0].Title != "❓ Ukjend kommando" {
+		t.Errorf("unknown command reply = %q", sent[1].Embeds[0].Title)
+	}
+}
+
+func TestHelpPages(t *testing.T) {
+	b, client := newTestBot(t)
+
+	// Regular members see general, question and word commands, in a fixed order
+	pages := HelpPages(client, false, "?")
+	if len(pages) != 1 {
+		t.Fatalf("pages = %d, want 1", len(pages))
+	}
+	var names []string
+	for _, field := range pages[0].Fields {
+		names = append(names, field.Name)
+	}
+	if !reflect.DeepEqual(names, []string{CategoryGeneral, CategoryQuestions, CategoryWords}) {
+		t.Fatalf("categories = %v", names)
+	}
+	general := pages[0].Fields[0].Value
+	if strings.Index(general, "`?hei`") > strings.Index(general, "`?hjelp`") || strings.Contains(general, "loggav") {
+		t.Errorf("general commands = %q", general)
+	}
+	if admin := HelpPages(client, true, "?"); admin[0].Fields[3].Name != CategoryAdmin {
+		t.Errorf("admin page = %+v, want an admin category", admin[0].Fields)
+	}
+
+	// A long list is split across pages with a pager
+	for i := 0; i < 80; i++ {
+		name := fmt.Sprintf("test-%02d", i)
+		commands[name] = Command{name: name, description: strings.Repeat("lang skildring ", 5), emoji: "🧪"}
+		t.Cleanup(func() { delete(commands, name) })
+	}
+	pages = HelpPages(client, false, "?")
+	if len(pages) < 2 {
+		t.Fatalf("pages = %d, want the long list paged", len(pages))
+	}
+	for i, page := range pages {
+		size := 0
+		for _, field := range page.Fields {
+			if len(field.Value) > maxFieldLength {
+				t.Errorf("page %d field %q has %d characters", i, field.Name, len(field.Value))
+			}
+			size += len(field.Name) + len(field.Value)
+		}
+		if size > helpPageBudget || !strings.HasPrefix(page.Footer.Text, fmt.Sprintf("Side %d/%d", i+1, len(pages))) {
+			t.Errorf("page %d: %d characters, footer %q", i, size, page.Footer.Text)
+		}
+	}
+
+	run(client, message("?hjelp"), b)
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 || len(sent[0].Components) != 1 {
+		t.Fatalf("help message = %+v, want a pager", sent)
+	}
+	next := sent[0].Components[0].(discordgo.ActionsRow).Components[1].(discordgo.Button)
+	if next.CustomID != HelpPagePrefix+"1:false" {
+		t.Fatalf("next button = %q", next.CustomID)
+	}
+
+	HandleHelpPage(client, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: next.CustomID},
+	}}, b)
+	if len(client.InteractionResponses) != 1 {
+		t.Fatalf("interaction responses = %d, want 1", len(client.InteractionResponses))
+	}
+	turned := client.InteractionResponses[0].Response
+	if turned.Type != discordgo.InteractionResponseUpdateMessage || !strings.HasPrefix(turned.Data.Embeds[0].Footer.Text, "Side 2/") {
+		t.Fatalf("turned page = %+v", turned.Data)
+	}
+}
+
+func TestCooldown(t *testing.T) {
+	b, client := newTestBot(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cooldowns.now = func() time.Time { return now }
+
+	run(client, message("?spør Kva er klokka?"), b)
+	now = now.Add(10 * time.Second)
+	run(client, message("?spør Kva er klokka no?"), b)
+	now = now.Add(20 * time.Second)
+	run(client, message("?spør Kva er klokka no då?"), b)
+
+	got := titles(client.SentTo(testChannel))
+	want := []string{"📝 Spørsmål motteke!", "⏳ Vent litt", "📝 Spørsmål motteke!"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("replies = %v, want %v", got, want)
+	}
+	if wait := client.SentTo(testChannel)[1].Embeds[0].Description; !strings.Contains(wait, "20 sekund") {
+		t.Errorf("cooldown message = %q", wait)
+	}
+}
+
+func TestRettform(t *testing.T) {
+	const testRettskrivar = "rettskrivar-role"
+	tests := []struct {
+		name      string
+		content   string
+		role      string
+		wantTitle string
+		want      string
+	}{
+		{name: "not a rettskrivar", content: "?rettform ikke ikkje", wantTitle: "⛔ Ingen tilgang"},
+		{name: "unknown word", content: "?rettform noe noko", role: testRettskrivar, wantTitle: "❓ Ukjent ord"},
+		{name: "set", content: "?rettform IKKE ikkje / ikkje-", role: testRettskrivar, wantTitle: "✏️ Rett form lagra", want: "[ikkje ikkje-]"},
+		{name: "clear", content: "?rettform ikke fjern", role: testRettskrivar, wantTitle: "✏️ Rett form lagra", want: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, client := newTestBot(t)
+			b.Config.BannedWords.RettskrivarRoleID = testRettskrivar
+			client.AddMember(testGuild, testAuthorID, tt.role)
+			id, err := b.Database.AddBannedWordPending("ikke", "", testAuthorID, testAuthorName, "", "")
+			if err != nil {
+				t.Fatalf("AddBannedWordPending: %v", err)
+			}
+			b.Database.UpdateBannedWordReplacements(int(id), []string{"ikkje"})
+
+			run(client, message(tt.content), b)
+
+			sent := client.SentTo(testChannel)
+			if len(sent) != 1 || titles(sent)[0] != tt.wantTitle {
+				t.Fatalf("channel replies = %v, want one %q", titles(sent), tt.wantTitle)
+			}
+			bw, _ := b.Database.GetBannedWordByID(int(id))
+			if tt.want != "" && fmt.Sprint(bw.Replacements) != tt.want {
+				t.Errorf("replacements = %v, want %s", bw.Replacements, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReplacements(t *testing.T) {
+	tests := []struct {
+		text    string
+		words   []string
+		want    string
+		wantErr bool
+	}{
+		{text: "ikkje", words: []string{"ikke"}, want: "map[ikke:[ikkje]]"},
+		{text: "Ikke → ikkje\nnoe -> noko / noka\n\n", words: []string{"ikke", "noe"}, want: "map[ikke:[ikkje] noe:[noko noka]]"},
+		{text: "ikke sant = ikkje sant", words: []string{"ikke sant"}, want: "map[ikke sant:[ikkje sant]]"},
+		{text: "", words: []string{"ikke"}, want: "map[]"},
+		{text: "ikkje", words: []string{"ikke", "noe"}, wantErr: true},
+		{text: "hvem → kven", words: []string{"ikke"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseReplacements(tt.text, tt.words)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && fmt.Sprint(got) != tt.want) {
+			t.Errorf("parseReplacements(%q, %v) = %v, %v; want %s", tt.text, tt.words, got, err, tt.want)
+		}
+	}
+}
+
+func TestRettleiing(t *testing.T) {
+	b, client := newTestBot(t)
+
+	run(client, message("?rettleiing"), b)
+	run(client, message("?rettleiing DM"), b)
+	run(client, message("?rettleiing"), b)
+	run(client, message("?rettleiing kanskje"), b)
+
+	sent := client.SentTo(testChannel)
+	want := []string{"📝 Språkrettleiing", "✅ Språkrettleiing endra", "📝 Språkrettleiing", "❓ Feil"}
+	if !reflect.DeepEqual(titles(sent), want) {
+		t.Fatalf("replies = %v, want %v", titles(sent), want)
+	}
+	if !strings.Contains(sent[2].Embeds[0].Description, "direktemelding") {
+		t.Errorf("current mode = %q, want dm", sent[2].Embeds[0].Description)
+	}
+	if mode, _ := b.Database.GetWarningMode(testAuthorID); mode != database.WarningModeDM {
+		t.Errorf("stored mode = %q, want %q", mode, database.WarningModeDM)
+	}
+}
+
+func TestOrd(t *testing.T) {
+	b, client := newTestBot(t)
+	for i := 0; i < 25; i++ {
+		id, _ := b.Database.AddBannedWordPending(fmt.Sprintf("ord%02d", i), "", testAuthorID, testAuthorName, "", "")
+		b.Database.ApproveBannedWordCombined(int(id), []string{"o1"}, []string{"r1"})
+	}
+	id, _ := b.Database.AddBannedWordPending("ikke", "Bokmål", testAuthorID, testAuthorName, "", "")
+	b.Database.UpdateBannedWordReplacements(int(id), []string{"ikkje"})
+	b.Database.ApproveBannedWordCombined(int(id), []string{"o1"}, []string{"r1", "r2"})
+	b.Database.UpdateBannedWordForumThreadID(int(id), "thread")
+	b.Database.AddBannedWordPending("noe", "", testAuthorID, testAuthorName, "", "")
+	b.BannedWords.Reload(b.Database)
+
+	run(client, message("?ord IKKE"), b)
+	run(client, message("?ord noe"), b)
+	run(client, message("?ord ord0"), b)
+	sent := client.SentTo(testChannel)
+	if got := titles(sent); !reflect.DeepEqual(got, []string{"📖 ikke", "📖 noe", "❓ Ukjent ord"}) {
+		t.Fatalf("replies = %v", got)
+	}
+	want := map[string]string{
+		"Status":        "✅ Godkjent",
+		"Rapportert av": "<@" + testAuthorID + ">",
+		"Opplysar":      "<@o1>",
+		"Rettskrivar":   "<@r1>, <@r2>",
+		"Grunn":         "Bokmål",
+		"Rett form":     "ikkje",
+		"Diskusjon":     "<#thread>",
+	}
+	if got := fields(sent[0].Embeds[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+	if got := fields(sent[1].Embeds[0])["Status"]; got != "⏳ Ventar på godkjenning" {
+		t.Errorf("pending status = %q", got)
+	}
+	if !strings.Contains(sent[2].Embeds[0].Description, "**ord09**") {
+		t.Errorf("unknown word reply = %q, want similar words suggested", sent[2].Embeds[0].Description)
+	}
+
+	run(client, message("?ord ventar"), b)
+	pending := client.SentTo(testChannel)[3]
+	if !strings.Contains(pending.Embeds[0].Description, "**noe**") || len(pending.Components) != 0 {
+		t.Errorf("pending list = %q with %d pager rows", pending.Embeds[0].Description, len(pending.Components))
+	}
+
+	run(client, message("?ord liste"), b)
+	list := client.SentTo(testChannel)[4]
+	if !strings.HasPrefix(list.Embeds[0].Description, "• **ikke** → ikkje\n• **ord00**") || !strings.HasPrefix(list.Embeds[0].Footer.Text, "Side 1/2 · 26 ord") {
+		t.Fatalf("first page = %q, footer %q", list.Embeds[0].Description, list.Embeds[0].Footer.Text)
+	}
+	next := list.Components[0].(discordgo.ActionsRow).Components[1].(discordgo.Button)
+	if next.CustomID != WordListPagePrefix+"liste:1" {
+		t.Fatalf("next button = %q", next.CustomID)
+	}
+
+	HandleWordListPage(client, &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: next.CustomID},
+	}}, b)
+	turned := client.InteractionResponses[0].Response
+	if turned.Type != discordgo.InteractionResponseUpdateMessage || !strings.HasPrefix(turned.Data.Embeds[0].Footer.Text, "Side 2/2") {
+		t.Fatalf("turned page = %+v", turned.Data)
+	}
+	if n := strings.Count(turned.Data.Embeds[0].Description, "•"); n != 6 {
+		t.Errorf("second page has %d words, want 6", n)
+	}
+}
+
+func TestImporter(t *testing.T) {
+	const (
+		testRettskrivar = "rettskrivar-role"
+		testRetting     = "retting"
+	)
+	original := fetchAttachment
+	t.Cleanup(func() { fetchAttachment = original })
+	fetchAttachment = func(url string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("ord\trett form\tgrunn\nikke\tikkje\tBokmål\nnoe\tnoko\nhvordan\n*ikk*\n")), nil
+	}
+	withFile := func(m *discordgo.MessageCreate) *discordgo.MessageCreate {
+		m.Attachments = []*discordgo.MessageAttachment{{Filename: "ord.tsv", URL: "https://cdn.example/ord.tsv", Size: 64}}
+		return m
+	}
+
+	tests := []struct {
+		name      string
+		roles     []string
+		file      bool
+		wantTitle string
+		noRetting bool
+		wantState string // approval status of the imported words
+	}{
+		{name: "not allowed", file: true, wantTitle: "⛔ Ingen tilgang"},
+		{name: "no attachment", roles: []string{testOpplysar}, wantTitle: "📎 Manglar fil"},
+		{name: "pending", roles: []string{testRettskrivar}, file: true, wantTitle: "📥 Import ferdig", wantState: "pending"},
+		{name: "both roles approve", roles: []string{testOpplysar, testRettskrivar}, file: true, wantTitle: "📥 Import ferdig", wantState: "fully_approved"},
+		{name: "no retting channel", roles: []string{testRettskrivar}, file: true, noRetting: true, wantTitle: "❌ Ikkje importert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, client := newTestBot(t)
+			b.Config.BannedWords.RettskrivarRoleID = testRettskrivar
+			if !tt.noRetting {
+				b.Config.BannedWords.ApprovalChannelID = testRetting
+			}
+			client.AddChannel(testGuild, testRetting, "retting")
+			client.AddMember(testGuild, testAuthorID, tt.roles...)
+			b.Database.AddBannedWordPending("hvordan", "", "someone", "ola", "", "")
+
+			m := message("?importer")
+			if tt.file {
+				m = withFile(m)
+			}
+			run(client, m, b)
+
+			sent := client.SentTo(testChannel)
+			if len(sent) != 1 || sent[0].Embeds[0].Title != tt.wantTitle {
+				t.Fatalf("replies = %v, want %q", titles(sent), tt.wantTitle)
+			}
+			if tt.wantState == "" {
+				if n, _ := b.Database.CountBannedWords("pending"); n != 1 {
+					t.Errorf("pending words = %d, want nothing imported", n)
+				}
+				return
+			}
+
+			report := fields(sent[0].Embeds[0])
+			if !strings.HasPrefix(report["Lagt til"], "2 ord") || report["Fanst frå før (1)"] != "hvordan" || !strings.HasPrefix(report["Hoppa over (1)"], "linje 5:") {
+				t.Errorf("report = %v", report)
+			}
+			_, bw, _ := b.Database.IsBannedWord("ikke")
+			if bw == nil || bw.ApprovalStatus != tt.wantState || bw.Reason != "Bokmål" || fmt.Sprint(bw.Replacements) != "[ikkje]" || bw.AuthorID != testAuthorID {
+				t.Fatalf("imported word = %+v", bw)
+			}
+			if _, matched := b.BannedWords.Lookup("ikke"); matched != (tt.wantState == "fully_approved") {
+				t.Errorf("word in index = %v", matched)
+			}
+
+			summary := client.SentTo(testRetting)
+			if len(summary) != 1 || bw.ImportMessageID == nil || *bw.ImportMessageID != summary[0].ID {
+				t.Fatalf("retting channel = %v, want one summary", titles(summary))
+			}
+			if pending := tt.wantState == "pending"; pending != (len(summary[0].Components) == 1) {
+				t.Errorf("summary buttons = %+v", summary[0].Components)
+			}
+		})
+	}
+}
+
+func TestImporterSlashCommand(t *testing.T) {
+	original := fetchAttachment
+	t.Cleanup(func() { fetchAttachment = original })
+	fetchAttachment = func(url string) (io.ReadCloser, error) {
+		if url != "https://cdn.example/ord.csv" {
+			t.Errorf("downloaded %s", url)
+		}
+		return io.NopCloser(strings.NewReader("ikke\nnoe\n")), nil
+	}
+
+	b, client := newTestBot(t)
+	b.Config.BannedWords.ApprovalChannelID = "retting"
+	client.AddChannel(testGuild, "retting", "retting")
+	client.AddMember(testGuild, testAuthorID, testOpplysar)
+
+	interaction := slashCommand("importer", &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "fil",
+		Type:  discordgo.ApplicationCommandOptionAttachment,
+		Value: "vedlegg",
+	})
+	data := interaction.Data.(discordgo.ApplicationCommandInteractionData)
+	data.Resolved = &discordgo.ApplicationCommandInteractionDataResolved{Attachments: map[string]*discordgo.MessageAttachment{
+		"vedlegg": {ID: "vedlegg", Filename: "ord.csv", URL: "https://cdn.example/ord.csv", Size: 9},
+	}}
+	interaction.Data = data
+	MatchAndRunApplicationCommand(client, interaction, b)
+
+	// The interaction is acknowledged before the download, and the report edits the reply in
+	if len(client.InteractionResponses) != 1 || client.InteractionResponses[0].Response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("interaction responses = %+v, want one deferred", client.InteractionResponses)
+	}
+	if got := titles(client.SentTo(testChannel)); len(got) != 1 || got[0] != "📥 Import ferdig" {
+		t.Fatalf("reply = %v, want the import report", got)
+	}
+	if n, _ := b.Database.CountBannedWords("pending"); n != 2 {
+		t.Errorf("pending words = %d, want the two imported", n)
+	}
+}
+
+func TestAktivitet(t *testing.T) {
+	b, client := newTestBot(t)
+	b.Activity = activity.NewTracker(testChannel, 2, 6*time.Hour)
+	b.Activity.Record(testChannel, testAuthorID, time.Now())
+
+	handleAktivitet(client, message("?aktivitet"), b, nil)
+	sent := client.SentTo(testChannel)
+	if len(sent) != 1 || len(sent[0].Embeds) != 1 {
+		t.Fatalf("sent %+v", sent)
+	}
+	fields := sent[0].Embeds[0].Fields
+	if len(fields) != 3 || fields[1].Value != "Ingen enno" || fields[2].Name != "Skribentar siste 6 t" || fields[2].Value != "1 (treng 2)" {
+		t.Errorf("fields = %+v", fields)
+	}
+}
+
+func TestJobbar(t *testing.T) {
+	b, client := newTestBot(t)
+	handleJobbar(client, message("?jobbar"), b, Args{})
+	if sent := client.SentTo(testChannel); len(sent) != 1 || sent[0].Embeds[0].Description != "Planleggaren køyrer ikkje." {
+		t.Fatalf("without a scheduler: %+v", sent)
+	}
+
+	runs := 0
+	b.Jobs = scheduler.New(b.Database, time.UTC)
+	b.Jobs.Register(scheduler.Job{Name: "rydding", Description: "Ryddar", Schedule: scheduler.MustParseCron("0 4 * * *"),
+		Run: func(context.Context) error { runs++; return nil }})
+
+	tests := []struct {
+		args  Args
+		title string
+	}{
+		{Args{}, "⏰ Jobbar"},
+		{Args{"jobb": "rydding"}, "✅ Ferdig"},
+		{Args{"jobb": "ukjend"}, "❌ Ukjend jobb"},
+	}
+	for _, tt := range tests {
+		client.Sent = nil
+		handleJobbar(client, message("?jobbar"), b, tt.args)
+		sent := client.SentTo(testChannel)
+		if len(sent) != 1 || sent[0].Embeds[0].Title != tt.title {
+			t.Errorf("%v: sent %+v", tt.args, sent)
+		}
+	}
+	if runs != 1 {
+		t.Errorf("job ran %d times, want 1", runs)
+	}
+
+	client.Sent = nil
+	handleJobbar(client, message("?jobbar"), b, Args{})
+	fields := client.SentTo(testChannel)[0].Embeds[0].Fields
+	if len(fields) != 1 || fields[0].Name != "rydding" || !strings.Contains(fields[0].Value, "`0 4 * * *`") || strings.Contains(fields[0].Value, "aldri") {
+		t.Errorf("fields = %+v", fields)
+	}
+}
+
+func TestJobbarSlashCommandDefers(t *testing.T) {
+	b, client := newTestBot(t)
+	b.Jobs = scheduler.New(b.Database, time.UTC)
+	b.Jobs.Register(scheduler.Job{Name: "rydding", Description: "Ryddar", Schedule: scheduler.MustParseCron("0 4 * * *"),
+		Run: func(context.Context) error {
+			// The interaction must be acknowledged before a slow job starts
+			if len(client.InteractionResponses) != 1 || client.InteractionResponses[0].Response.Type != discordgo.InteractionResponseDeferredChannelMessageWithSource {
+				t.Errorf("interaction responses when the job runs = %+v, want one deferred", client.InteractionResponses)
+			}
+			return nil
+		}})
+
+	MatchAndRunApplicationCommand(client, slashCommand("jobbar", &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "jobb",
+		Type:  discordgo.ApplicationCommandOptionString,
+		Value: "rydding",
+	}), b)
+	if got := titles(client.SentTo(testChannel)); len(got) != 1 || got[0] != "✅ Ferdig" {
+		t.Fatalf("reply = %v, want the result", got)
+	}
+	if len(client.InteractionResponses) != 1 {
+		t.Errorf("interaction responses = %+v, want only the deferral", client.InteractionResponses)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -15,10 +16,11 @@ func init() {
 		emoji:       "🔧",
 		handler:     handleConfigCommand,
 		adminOnly:   true,
+		category:    CategoryAdmin,
 	}
 }
 
-func handleConfigCommand(s *discordgo.Session, m *discordgo.MessageCreate, b *bot.Bot) {
+func handleConfigCommand(s discord.Client, m *discordgo.MessageCreate, b *bot.Bot, _ Args) {
 	cfg := b.Config
 
 	// Helper to get channel name from ID
@@ -37,9 +39,13 @@ func handleConfigCommand(s *discordgo.Session, m *discordgo.MessageCreate, b *bo
 		if roleID == "" || guildID == "" {
 			return "[ingen]"
 		}
-		role, err := s.State.Role(guildID, roleID)
+		roles, err := s.GuildRoles(guildID)
 		if err == nil {
-			return fmt.Sprintf("<@&%s> `%s`", roleID, role.Name)
+			for _, role := range roles {
+				if role.ID == roleID {
+					return fmt.Sprintf("<@&%s> `%s`", roleID, role.Name)
+				}
+			}
 		}
 		return fmt.Sprintf("<@&%s>", roleID)
 	}
@@ -62,9 +68,7 @@ func handleConfigCommand(s *discordgo.Session, m *discordgo.MessageCreate, b *bo
 	configInfo += fmt.Sprintf("• Emoji: %s\n\n", cfg.Starboard.Emoji)
 
 	configInfo += "**Reaction Emojis:**\n"
-	configInfo += fmt.Sprintf("• Question: %s\n", cfg.Reactions.Question)
-	configInfo += "• Approval: 👍\n"
-	configInfo += "• Reject: 👎\n\n"
+	configInfo += fmt.Sprintf("• Question: %s\n\n", cfg.Reactions.Question)
 
 	configInfo += "**Database Settings:**\n"
 	configInfo += fmt.Sprintf("• Host: %s\n", cfg.Database.Host)
@@ -81,12 +85,13 @@ func handleConfigCommand(s *discordgo.Session, m *discordgo.MessageCreate, b *bo
 	}
 
 	if cfg.Scheduler.Enabled {
-		configInfo += fmt.Sprintf("\n\n**Scheduler:**\n• Status: %s\n• Timezone: %s\n• Morning Time: %s\n• Evening Time: %s\n• Inactivity Threshold: %d hours",
+		configInfo += fmt.Sprintf("\n\n**Scheduler:**\n• Status: %s\n• Timezone: %s\n• Morning Time: %s\n• Evening Time: %s\n• Inactivity Threshold: %d hours\n• Min. Active Authors: %d",
 			map[bool]string{true: "✅ Enabled", false: "❌ Disabled"}[cfg.Scheduler.Enabled],
 			cfg.Scheduler.Timezone,
 			cfg.Scheduler.MorningTime,
 			cfg.Scheduler.EveningTime,
-			cfg.Scheduler.InactivityHours)
+			cfg.Scheduler.InactivityHours,
+			max(cfg.Scheduler.ActivityMinAuthors, 1))
 		if cfg.Scheduler.CronString != "" {
 			configInfo += fmt.Sprintf("\n• Fallback Cron: `%s`", cfg.Scheduler.CronString)
 		}
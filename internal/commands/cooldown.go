@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// cooldownTracker remembers when each user last ran each command
+type cooldownTracker struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time // command name + user ID -> last use
+	now      func() time.Time
+}
+
+// cooldowns is shared by the prefix and slash command paths
+var cooldowns = newCooldownTracker()
+
+func newCooldownTracker() *cooldownTracker {
+	return &cooldownTracker{lastUsed: make(map[string]time.Time), now: time.Now}
+}
+
+// use records a use of cmd by userID and returns zero, or returns how long
+// the user still has to wait without recording anything
+func (c *cooldownTracker) use(cmd Command, userID string) time.Duration {
+	if cmd.cooldown <= 0 {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cmd.name + "|" + userID
+	now := c.now()
+	if last, exists := c.lastUsed[key]; exists {
+		if remaining := cmd.cooldown - now.Sub(last); remaining > 0 {
+			return remaining
+		}
+	}
+	c.lastUsed[key] = now
+	return 0
+}
+
+// formatDuration writes a duration in whole minutes or seconds, rounding up
+func formatDuration(d time.Duration) string {
+	if d >= time.Minute {
+		return fmt.Sprintf("%d minutt", int((d+time.Minute-1)/time.Minute))
+	}
+	return fmt.Sprintf("%d sekund", int((d+time.Second-1)/time.Second))
+}
@@ -3,12 +3,11 @@ package commands
 import (
 	"fmt"
 	"log"
-	"strconv"
-	"strings"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
 	"askeladden/internal/database"
+	"askeladden/internal/discord"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -21,21 +20,18 @@ func init() {
 		handler:     Godkjenn,
 		aliases:     []string{},
 		adminOnly:   true,
+		args: []Arg{
+			{Name: "spørsmål", Description: "Spørsmål-ID, «neste» for neste ventande spørsmål eller «alle»", Type: ArgInt, Choices: []string{"neste", "next", "alle"}},
+		},
+		category: CategoryQuestions,
+		examples: []string{"neste", "12"},
 	}
 }
 
 // Godkjenn handsamer godkjenn-kommandoen
-func Godkjenn(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func Godkjenn(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
 	db := bot.Database
-	// Parse kommandoen for å hente spørsmål ID eller søkeord
-	parts := strings.SplitN(m.Content, " ", 2)
-	if len(parts) < 2 {
-		embed := services.CreateBotEmbed(s, "❓ Feil", "Bruk: `!godkjenn [spørsmål-ID]` eller `!godkjenn next` for neste ventande spørsmål", services.EmbedTypeError)
-		s.ChannelMessageSendEmbed(m.ChannelID, embed)
-		return
-	}
-
-	arg := strings.TrimSpace(parts[1])
+	arg := args.String("spørsmål")
 
 	if arg == "alle" {
 		// TODO: Implement ApproveAllPendingQuestions functionality
@@ -64,14 +60,7 @@ func Godkjenn(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
 			return
 		}
 	} else {
-		// Try to parse as question ID
-		questionID, parseErr := strconv.Atoi(arg)
-		if parseErr != nil {
-			embed := services.CreateBotEmbed(s, "❓ Feil", "Ugyldig spørsmål-ID. Bruk eit tal eller «next» for neste ventande spørsmål.", services.EmbedTypeError)
-			s.ChannelMessageSendEmbed(m.ChannelID, embed)
-
-			return
-		}
+		questionID, _ := args.Int("spørsmål")
 
 		// Find pending question by ID
 		question, err = db.GetPendingQuestionByID(questionID)
@@ -3,6 +3,7 @@ package commands
 import (
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -13,12 +14,13 @@ func init() {
 		emoji:       "👋",
 		handler:     Hei,
 		aliases:     []string{"hallo"},
+		category:    CategoryGeneral,
 	}
 }
 
 // Hei handsamer hei-kommandoen
 
-func Hei(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func Hei(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
 	embed := services.CreateBotEmbed(s, "Heisann! 👋", "Eg er Askeladden, laga av rørsla!", services.EmbedTypeInfo)
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 }
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -15,38 +19,251 @@ func init() {
 		emoji:       "❓",
 		handler:     Hjelp,
 		aliases:     []string{"help", "h"},
+		category:    CategoryGeneral,
+		args: []Arg{
+			{Name: "kommando", Description: "Kommandoen du vil vite meir om", Type: ArgString, Optional: true},
+		},
+		examples: []string{"", "spør"},
 	}
 }
 
+// helpPageBudget keeps each help page well inside Discord's 6000 character embed limit
+const helpPageBudget = 4000
+
+// maxFieldLength is Discord's limit for an embed field value
+const maxFieldLength = 1024
+
+// HelpPagePrefix starts the custom ID of the help pager buttons: "hjelp_side:<page>:<admin>"
+const HelpPagePrefix = "hjelp_side:"
+
 // Hjelp handsamer hjelp-kommandoen
 // --------------------------------------------------------------------------------
-func Hjelp(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
-	// Check if user has admin role (we need to implement role checking here)
-	// For now, let's use a placeholder implementation
-	isAdmin := false
-
-	// Try to get guild member to check roles
-	if m.GuildID != "" {
-		member, err := s.GuildMember(m.GuildID, m.Author.ID)
-		if err == nil {
-			// Check for opplysar role (need to get the role ID from config)
-			// This is a placeholder - we'll need to pass config or implement differently
-			for _, roleID := range member.Roles {
-				if roleID == bot.Config.Approval.OpplysarRoleID { // Use config for role ID
-					isAdmin = true
-					break
-				}
+func Hjelp(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	prefix := bot.Config.Discord.Prefix
+
+	if args.Has("kommando") {
+		name := strings.TrimPrefix(args.String("kommando"), prefix)
+		cmd, exists := findCommand(strings.TrimPrefix(name, "/"))
+		if !exists {
+			description := fmt.Sprintf("Fann ingen kommando som heiter «%s». Skriv `%shjelp` for å sjå alle.", name, prefix)
+			embed := services.CreateBotEmbed(s, "❓ Ukjend kommando", description, services.EmbedTypeError)
+			s.ChannelMessageSendEmbed(m.ChannelID, embed)
+			return
+		}
+		s.ChannelMessageSendEmbed(m.ChannelID, CommandHelp(s, cmd, prefix))
+		return
+	}
+
+	isAdmin := isOpplysar(s, m.GuildID, m.Author.ID, bot)
+	pages := HelpPages(s, isAdmin, prefix)
+	_, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{pages[0]},
+		Components: helpPager(0, len(pages), isAdmin),
+	})
+	if err != nil {
+		log.Printf("Failed to send help: %v", err)
+	}
+}
+
+// isOpplysar reports whether the user has the opplysar role in the guild
+func isOpplysar(s discord.Client, guildID, userID string, bot *bot.Bot) bool {
+	if guildID == "" {
+		return false
+	}
+	member, err := s.GuildMember(guildID, userID)
+	if err != nil {
+		log.Printf("Failed to get guild member for role check: %v", err)
+		return false
+	}
+	for _, roleID := range member.Roles {
+		if roleID == bot.Config.Approval.OpplysarRoleID {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedCommands returns the commands in help order: by category, then by name
+func sortedCommands(isAdmin bool) []Command {
+	var sorted []Command
+	for _, category := range categoryOrder {
+		for _, name := range getCommandNames() {
+			cmd := commands[name]
+			if commandCategory(cmd) == category && (isAdmin || !cmd.adminOnly) {
+				sorted = append(sorted, cmd)
+			}
+		}
+	}
+	return sorted
+}
+
+// commandCategory returns the command's category, defaulting by admin status
+func commandCategory(cmd Command) string {
+	if cmd.category != "" {
+		return cmd.category
+	}
+	if cmd.adminOnly {
+		return CategoryAdmin
+	}
+	return CategoryGeneral
+}
+
+// GetHelpText generates the help text for all commands.
+func GetHelpText() string {
+	var helpText strings.Builder
+	helpText.WriteString("**Askeladden - Kommandoer:**\n")
+
+	for _, cmd := range sortedCommands(true) {
+		helpText.WriteString(fmt.Sprintf("%s `%s` - %s\n", cmd.emoji, cmd.name, cmd.description))
+	}
+
+	return strings.TrimSpace(helpText.String())
+}
+
+// HelpPages lists the commands grouped by category, split into pages that fit in an embed
+func HelpPages(s discord.Client, isAdmin bool, prefix string) []*discordgo.MessageEmbed {
+	type field struct{ name, value string }
+	var fields []field
+	for _, cmd := range sortedCommands(isAdmin) {
+		line := fmt.Sprintf("%s `%s%s` - %s\n", cmd.emoji, prefix, cmd.name, cmd.description)
+		category := commandCategory(cmd)
+		last := len(fields) - 1
+		if last >= 0 && strings.HasPrefix(fields[last].name, category) && len(fields[last].value)+len(line) <= maxFieldLength {
+			fields[last].value += line
+			continue
+		}
+		name := category
+		if last >= 0 && strings.HasPrefix(fields[last].name, category) {
+			name = category + " (forts.)"
+		}
+		fields = append(fields, field{name: name, value: line})
+	}
+
+	newPage := func() *services.EmbedBuilder {
+		return services.NewEmbedBuilder().
+			SetTitle("Askeladden - Kommandoer").
+			SetColorByType(services.EmbedTypePrimary).
+			SetAuthorFromBot(s)
+	}
+	pages := []*services.EmbedBuilder{newPage()}
+	size := 0
+	for _, f := range fields {
+		if size > 0 && size+len(f.name)+len(f.value) > helpPageBudget {
+			pages = append(pages, newPage())
+			size = 0
+		}
+		pages[len(pages)-1].AddField(f.name, f.value, false)
+		size += len(f.name) + len(f.value)
+	}
+
+	embeds := make([]*discordgo.MessageEmbed, len(pages))
+	for i, page := range pages {
+		footer := fmt.Sprintf("Skriv %shjelp <kommando> for meir om ein kommando", prefix)
+		if len(pages) > 1 {
+			footer = fmt.Sprintf("Side %d/%d · %s", i+1, len(pages), footer)
+		}
+		embeds[i] = page.SetFooter(footer, "").Build()
+	}
+	return embeds
+}
+
+// CommandHelp builds the detailed help page for one command
+func CommandHelp(s discord.Client, cmd Command, prefix string) *discordgo.MessageEmbed {
+	builder := services.NewEmbedBuilder().
+		SetTitle(fmt.Sprintf("%s %s%s", cmd.emoji, prefix, cmd.name)).
+		SetDescription(cmd.description).
+		SetColorByType(services.EmbedTypePrimary).
+		SetAuthorFromBot(s).
+		AddField("Bruk", "`"+Usage(cmd, prefix)+"`", false)
+
+	if len(cmd.args) > 0 {
+		var lines []string
+		for _, arg := range cmd.args {
+			line := fmt.Sprintf("`%s` - %s", arg.Name, arg.Description)
+			if arg.Optional {
+				line += " (valfritt)"
 			}
-		} else {
-			log.Printf("Failed to get guild member for role check: %v", err)
+			lines = append(lines, line)
 		}
+		builder.AddField("Argument", strings.Join(lines, "\n"), false)
+	}
+
+	if len(cmd.aliases) > 0 {
+		aliases := make([]string, len(cmd.aliases))
+		for i, alias := range cmd.aliases {
+			aliases[i] = "`" + prefix + alias + "`"
+		}
+		builder.AddField("Alias", strings.Join(aliases, ", "), true)
+	}
+
+	if len(cmd.examples) > 0 {
+		examples := make([]string, len(cmd.examples))
+		for i, example := range cmd.examples {
+			examples[i] = "`" + strings.TrimSpace(prefix+cmd.name+" "+example) + "`"
+		}
+		builder.AddField("Døme", strings.Join(examples, "\n"), false)
+	}
+
+	role := "Ingen"
+	if cmd.adminOnly {
+		role = "Opplysar"
+	}
+	builder.AddField("Krev rolle", role, true)
+
+	if cmd.cooldown > 0 {
+		builder.AddField("Ventetid", formatDuration(cmd.cooldown), true)
+	}
+
+	return builder.Build()
+}
+
+// helpPager returns the previous/next buttons for a help page, or nothing for a single page
+func helpPager(page, pages int, isAdmin bool) []discordgo.MessageComponent {
+	if pages <= 1 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "◀ Førre",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s%d:%t", HelpPagePrefix, page-1, isAdmin),
+				Disabled: page == 0,
+			},
+			discordgo.Button{
+				Label:    "Neste ▶",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s%d:%t", HelpPagePrefix, page+1, isAdmin),
+				Disabled: page == pages-1,
+			},
+		}},
+	}
+}
+
+// HandleHelpPage turns the help message to the page a pager button points at
+func HandleHelpPage(s discord.Client, i *discordgo.InteractionCreate, bot *bot.Bot) {
+	parts := strings.Split(strings.TrimPrefix(i.MessageComponentData().CustomID, HelpPagePrefix), ":")
+	if len(parts) != 2 {
+		log.Printf("Malformed help pager ID: %s", i.MessageComponentData().CustomID)
+		return
+	}
+	page, err := strconv.Atoi(parts[0])
+	if err != nil {
+		log.Printf("Malformed help pager ID: %s", i.MessageComponentData().CustomID)
+		return
 	}
+	isAdmin := parts[1] == "true"
 
-	helpEmbed := ListCommands(isAdmin)
-	helpBotEmbed := services.CreateBotEmbed(s, helpEmbed.Title, helpEmbed.Description, services.EmbedTypePrimary)
-	helpBotEmbed.Fields = helpEmbed.Fields
-	if helpEmbed.Footer != nil {
-		helpBotEmbed.Footer = helpEmbed.Footer
+	pages := HelpPages(s, isAdmin, bot.Config.Discord.Prefix)
+	page = max(0, min(page, len(pages)-1))
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{pages[page]},
+			Components: helpPager(page, len(pages), isAdmin),
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to turn help page: %v", err)
 	}
-	s.ChannelMessageSendEmbed(m.ChannelID, helpBotEmbed)
 }
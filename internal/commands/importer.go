@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["importer"] = Command{
+		name:        "importer",
+		description: "Importer forbodne ord frå ei vedlagd CSV- eller TSV-fil (kun for opplysarar og rettskrivarar)",
+		emoji:       "📥",
+		handler:     handleImporter,
+		aliases:     []string{"import"},
+		args: []Arg{
+			{Name: "fil", Description: "CSV- eller TSV-fila med orda, lagd ved meldinga", Type: ArgAttachment},
+		},
+		category: CategoryWords,
+	}
+}
+
+// maxWordlistSize is the largest wordlist attachment read
+const maxWordlistSize = 512 * 1024
+
+// maxImportReportLength keeps each list in the import reply inside an embed field
+const maxImportReportLength = 1000
+
+// attachmentClient downloads attachments; tests replace fetchAttachment
+var attachmentClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchAttachment opens the contents of an attachment
+var fetchAttachment = func(url string) (io.ReadCloser, error) {
+	resp, err := attachmentClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func handleImporter(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	role := permissions.NewPermissionManager(bot.Config).GetUserRole(s, m.GuildID, m.Author.ID)
+	if role == permissions.RoleNone {
+		embed := services.CreateBotEmbed(s, "⛔ Ingen tilgang", "Berre opplysarar og rettskrivarar kan importere ord.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	if len(m.Attachments) == 0 {
+		embed := services.CreateBotEmbed(s, "📎 Manglar fil",
+			fmt.Sprintf("Legg ved ei CSV- eller TSV-fil når du skriv `%simporter`. Kvar linje er eitt ord, med rett form og grunn i dei neste kolonnane om du vil.", bot.Config.Discord.Prefix),
+			services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	attachment := m.Attachments[0]
+	if attachment.Size > maxWordlistSize {
+		embed := services.CreateBotEmbed(s, "❌ For stor fil", fmt.Sprintf("Fila kan vere på høgst %d kB.", maxWordlistSize/1024), services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	// Downloading and storing a long list can take longer than a slash command may wait
+	deferReply(s)
+	body, err := fetchAttachment(attachment.URL)
+	if err != nil {
+		log.Printf("Failed to download wordlist %s: %v", attachment.Filename, err)
+		embed := services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje laste ned fila.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	defer body.Close()
+
+	list, err := bannedwords.ParseWordlist(io.LimitReader(body, maxWordlistSize))
+	if err != nil {
+		embed := services.CreateBotEmbed(s, "❌ Ugyldig fil", fmt.Sprintf("%s: %v", attachment.Filename, err), services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	approve := role == permissions.RoleBoth
+	result, err := (&services.ApprovalService{Bot: bot}).ImportBannedWords(s, list, m.Author.ID, m.Author.Username, approve)
+	if err != nil {
+		embed := services.CreateBotEmbed(s, "❌ Ikkje importert", fmt.Sprintf("Importen vart avbroten: %v.", err), services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	s.ChannelMessageSendEmbed(m.ChannelID, importReport(s, bot, result))
+}
+
+// importReport tells the importer what was added, what was already there and which rows were skipped
+func importReport(s discord.Client, bot *bot.Bot, result *services.ImportResult) *discordgo.MessageEmbed {
+	added := fmt.Sprintf("%d ord", len(result.Added))
+	switch {
+	case len(result.Added) == 0:
+	case result.Approved:
+		added += ", godkjende med ein gong"
+	default:
+		added += fmt.Sprintf(", ventar på godkjenning i <#%s>", bot.Config.BannedWords.ApprovalChannelID)
+	}
+
+	embedType := services.EmbedTypeSuccess
+	if len(result.Added) == 0 {
+		embedType = services.EmbedTypeWarning
+	}
+	builder := services.NewEmbedBuilder().
+		SetTitle("📥 Import ferdig").
+		SetColorByType(embedType).
+		SetAuthorFromBot(s).
+		AddField("Lagt til", added, false)
+	if len(result.Duplicates) > 0 {
+		builder.AddField(fmt.Sprintf("Fanst frå før (%d)", len(result.Duplicates)), reportList(result.Duplicates, ", "), false)
+	}
+	if len(result.Invalid) > 0 {
+		builder.AddField(fmt.Sprintf("Hoppa over (%d)", len(result.Invalid)), reportList(result.Invalid, "\n"), false)
+	}
+	return builder.Build()
+}
+
+// reportList joins items, cut short to fit an embed field
+func reportList(items []string, separator string) string {
+	list := ""
+	for n, item := range items {
+		next := item
+		if n > 0 {
+			next = separator + item
+		}
+		if len(list)+len(next) > maxImportReportLength {
+			return fmt.Sprintf("%s%s… og %d til", list, separator, len(items)-n)
+		}
+		list += next
+	}
+	return strings.TrimSpace(list)
+}
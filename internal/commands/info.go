@@ -3,6 +3,7 @@ package commands
 import (
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"fmt"
 	"github.com/bwmarrin/discordgo"
 )
@@ -13,20 +14,25 @@ func init() {
 		description: "Syn opplysingar om boten",
 		emoji:       "📊",
 		handler:     Info,
+		category:    CategoryGeneral,
 	}
 }
 
 // Info handsamer info-kommandoen
 // --------------------------------------------------------------------------------
-func Info(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
-	guildCount := len(s.State.Guilds)
+func Info(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
+	guildCount := s.GuildCount()
+	botUser := s.BotUser()
+	if botUser == nil {
+		botUser = &discordgo.User{Username: "Askeladden", Discriminator: "0"}
+	}
 	infoText := fmt.Sprintf("**Om Askeladden:**\n"+
 		"🤖 Ein norsk Discord-bot\n"+
 		"💻 Skriven i Go\n"+
 		"🏠 Laga av rørsla\n"+
 		"🖥️ Køyrer på %d servarar\n"+
 		"🤖 Bot-brukar: %s#%s",
-		guildCount, s.State.User.Username, s.State.User.Discriminator)
+		guildCount, botUser.Username, botUser.Discriminator)
 	embed := services.CreateBotEmbed(s, "📊 Om Askeladden", infoText, services.EmbedTypeInfo)
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 }
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
+	"askeladden/internal/scheduler"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["jobbar"] = Command{
+		name:        "jobbar",
+		description: "Vis dei planlagde jobbane, eller køyr ein av dei no",
+		emoji:       "⏰",
+		handler:     handleJobbar,
+		aliases:     []string{"jobb"},
+		adminOnly:   true,
+		args: []Arg{
+			{Name: "jobb", Description: "Namnet på jobben som skal køyrast no", Type: ArgString, Optional: true},
+		},
+		category: CategoryAdmin,
+		examples: []string{"", "rydd-åtvaringar"},
+	}
+}
+
+func handleJobbar(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	if bot.Jobs == nil {
+		embed := services.CreateBotEmbed(s, "⏰ Jobbar", "Planleggaren køyrer ikkje.", services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	if !args.Has("jobb") {
+		s.ChannelMessageSendEmbed(m.ChannelID, jobsEmbed(s, bot.Jobs))
+		return
+	}
+
+	name := args.String("jobb")
+	// A job can run for longer than a slash command may wait for its reply
+	deferReply(s)
+	err := bot.Jobs.RunNow(bot.Context(), name)
+	var embed *discordgo.MessageEmbed
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		embed = services.CreateBotEmbed(s, "❌ Ukjend jobb",
+			fmt.Sprintf("Det finst ingen jobb som heiter `%s`. Skriv `%sjobbar` for å sjå dei.", name, bot.Config.Discord.Prefix), services.EmbedTypeError)
+	case errors.Is(err, scheduler.ErrJobRunning):
+		embed = services.CreateBotEmbed(s, "⏳ Køyrer allereie", fmt.Sprintf("`%s` køyrer allereie.", name), services.EmbedTypeWarning)
+	case err != nil:
+		embed = services.CreateBotEmbed(s, "❌ Jobben feila", fmt.Sprintf("`%s`: %v", name, err), services.EmbedTypeError)
+	default:
+		embed = services.CreateBotEmbed(s, "✅ Ferdig", fmt.Sprintf("`%s` er køyrd.", name), services.EmbedTypeSuccess)
+	}
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
+
+// jobsEmbed lists every job with its schedule, last run and next run
+func jobsEmbed(s discord.Client, jobs *scheduler.Scheduler) *discordgo.MessageEmbed {
+	builder := services.NewEmbedBuilder().
+		SetTitle("⏰ Jobbar").
+		SetDescription(fmt.Sprintf("Tidsplanane gjeld i tidssona %s.", jobs.Location())).
+		SetColorByType(services.EmbedTypeInfo).
+		SetAuthorFromBot(s)
+	for _, job := range jobs.Jobs() {
+		lines := []string{job.Description, fmt.Sprintf("**Tidsplan:** `%s`", job.Schedule)}
+		switch {
+		case job.Running:
+			lines = append(lines, "**Sist:** køyrer no")
+		case job.LastRun.IsZero():
+			lines = append(lines, "**Sist:** aldri")
+		case job.LastError != "":
+			lines = append(lines, fmt.Sprintf("**Sist:** <t:%d:R>, feila: %s", job.LastRun.Unix(), job.LastError))
+		default:
+			lines = append(lines, fmt.Sprintf("**Sist:** <t:%d:R>", job.LastRun.Unix()))
+		}
+		if !job.Next.IsZero() {
+			lines = append(lines, fmt.Sprintf("**Neste:** <t:%d:R>", job.Next.Unix()))
+		}
+		builder.AddField(job.Name, strings.Join(lines, "\n"), false)
+	}
+	return builder.Build()
+}
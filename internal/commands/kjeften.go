@@ -7,6 +7,7 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -17,13 +18,14 @@ func init() {
 		description: "Si Askeladden han må teie for dei upratsame.",
 		emoji:       "🤐",
 		handler:     Kjeften,
+		category:    CategoryGeneral,
 	}
 }
 
 // Kjeften toggles the "pratsam" role on the invoking user. If the role does not
 // exist it reports an error. It will also check bot role hierarchy and return
 // a friendly embed explaining why the action failed if the bot cannot modify the role.
-func Kjeften(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func Kjeften(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
 	// Must be used in a guild
 	if m.GuildID == "" {
 		embed := services.CreateBotEmbed(s, "Feil", "Denne kommandoen må brukast i ein server (ikkje PM).", services.EmbedTypeError)
@@ -57,15 +59,12 @@ func Kjeften(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
 	}
 
 	// Fetch the member invoking the command
-	member, err := s.State.Member(m.GuildID, m.Author.ID)
+	member, err := s.GuildMember(m.GuildID, m.Author.ID)
 	if err != nil {
-		member, err = s.GuildMember(m.GuildID, m.Author.ID)
-		if err != nil {
-			log.Printf("failed to fetch member: %v", err)
-			embed := services.CreateBotEmbed(s, "Feil", "Klarte ikkje hente medlem sin informasjon.", services.EmbedTypeError)
-			s.ChannelMessageSendEmbed(m.ChannelID, embed)
-			return
-		}
+		log.Printf("failed to fetch member: %v", err)
+		embed := services.CreateBotEmbed(s, "Feil", "Klarte ikkje hente medlem sin informasjon.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
 	}
 
 	// Check whether the member already has the role
@@ -79,22 +78,14 @@ func Kjeften(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
 
 	// Determine bot identity and its highest role position to check hierarchy
 	var botUserID string
-	if s.State != nil && s.State.User != nil {
-		botUserID = s.State.User.ID
-	} else {
-		u, err := s.User("@me")
-		if err == nil {
-			botUserID = u.ID
-		}
+	if botUser := s.BotUser(); botUser != nil {
+		botUserID = botUser.ID
 	}
 
 	// Try to fetch the bot's guild member to inspect its roles (may fail)
 	var botMember *discordgo.Member
 	if botUserID != "" {
-		botMember, _ = s.State.Member(m.GuildID, botUserID)
-		if botMember == nil {
-			botMember, _ = s.GuildMember(m.GuildID, botUserID)
-		}
+		botMember, _ = s.GuildMember(m.GuildID, botUserID)
 	}
 
 	// If we have botMember and role info, check hierarchy: bot must be higher than the target role
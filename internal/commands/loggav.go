@@ -1,9 +1,8 @@
 package commands
 
 import (
-	"os"
-
 	"askeladden/internal/bot"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -14,11 +13,12 @@ func init() {
 		emoji:       "👋",
 		handler:     Loggav,
 		adminOnly:   true,
+		category:    CategoryAdmin,
 	}
 }
 
-// Loggav handsamar loggav-kommandoen
-func Loggav(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
-	bot.Stop()
-	os.Exit(0)
+// Loggav handsamar loggav-kommandoen. Boten blir slått av på same måte som ved SIGTERM,
+// så kommandoar som køyrer får gjere seg ferdige og «Offline» blir posta.
+func Loggav(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
+	bot.Shutdown()
 }
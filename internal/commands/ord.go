@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["ord"] = Command{
+		name:        "ord",
+		description: "Slå opp eit forbode ord, eller bla i lista",
+		emoji:       "📖",
+		handler:     handleOrd,
+		args: []Arg{
+			{Name: "ord", Description: "Ordet du vil slå opp, «liste» for alle godkjende eller «ventar» for dei som ventar", Type: ArgRest, Choices: []string{wordListApproved, wordListPending}},
+		},
+		category: CategoryWords,
+		examples: []string{"ikke", wordListApproved, wordListPending},
+	}
+}
+
+// The word lists ?ord can page through
+const (
+	wordListApproved = "liste"
+	wordListPending  = "ventar"
+)
+
+// WordListPagePrefix starts the custom ID of the word list pager buttons: "ord_side:<list>:<page>"
+const WordListPagePrefix = "ord_side:"
+
+// wordListPageSize is the number of words on each page of a word list
+const wordListPageSize = 20
+
+// wordSuggestions is the number of similar words suggested when a lookup finds nothing
+const wordSuggestions = 10
+
+// approvalStatusLabels describes the approval statuses of a banned word
+var approvalStatusLabels = map[string]string{
+	"pending":           "⏳ Ventar på godkjenning",
+	"opplysar_approved": "⏳ Godkjent av opplysar, ventar på rettskrivar",
+	"fully_approved":    "✅ Godkjent",
+	"rejected":          "❌ Avvist",
+}
+
+func handleOrd(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	text := args.String("ord")
+	if text == wordListApproved || text == wordListPending {
+		embed, pager, err := wordListPage(s, bot, text, 0)
+		if err != nil {
+			log.Printf("Failed to list banned words: %v", err)
+			s.ChannelMessageSendEmbed(m.ChannelID, services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje hente lista.", services.EmbedTypeError))
+			return
+		}
+		if _, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}, Components: pager}); err != nil {
+			log.Printf("Failed to send word list: %v", err)
+		}
+		return
+	}
+
+	if bannedWord := lookupBannedWord(bot, text); bannedWord != nil {
+		s.ChannelMessageSendEmbed(m.ChannelID, wordDetails(s, bot, bannedWord))
+		return
+	}
+
+	description := fmt.Sprintf("«%s» er ikkje på lista over forbodne ord.", text)
+	similar, err := bot.Database.SearchBannedWords(text, wordSuggestions)
+	if err != nil {
+		log.Printf("Failed to search banned words: %v", err)
+	}
+	if len(similar) > 0 {
+		words := make([]string, len(similar))
+		for i, bw := range similar {
+			words[i] = "**" + bw.Word + "**"
+		}
+		description += "\n\nMeinte du " + strings.Join(words, ", ") + "?"
+	}
+	s.ChannelMessageSendEmbed(m.ChannelID, services.CreateBotEmbed(s, "❓ Ukjent ord", description, services.EmbedTypeWarning))
+}
+
+// lookupBannedWord finds an entry by its text in any status, or an approved entry that
+// text is an inflected form of
+func lookupBannedWord(bot *bot.Bot, text string) *database.BannedWord {
+	if entry, err := bannedwords.ParseEntry(text); err == nil {
+		if _, bannedWord, err := bot.Database.IsBannedWord(entry.String()); err == nil && bannedWord != nil {
+			return bannedWord
+		}
+	}
+	if bannedWord, ok := bot.BannedWords.Lookup(text); ok {
+		return bannedWord
+	}
+	return nil
+}
+
+// wordDetails describes a banned word: its status, why it was reported, the correct
+// form, who reported and approved it and where it is discussed
+func wordDetails(s discord.Client, bot *bot.Bot, bw *database.BannedWord) *discordgo.MessageEmbed {
+	status, ok := approvalStatusLabels[bw.ApprovalStatus]
+	if !ok {
+		status = bw.ApprovalStatus
+	}
+	builder := services.NewEmbedBuilder().
+		SetTitle("📖 "+bw.Word).
+		SetColorByType(services.EmbedTypePrimary).
+		AddField("Status", status, true).
+		AddField("Rapportert av", "<@"+bw.AuthorID+">", true)
+
+	if bw.ApprovalStatus == "rejected" {
+		builder.SetColorByType(services.EmbedTypeError)
+		builder.AddField("Avvist av", mentions(bw.OpplysarApprovedBy), true)
+		if bw.RejectionReason != "" {
+			builder.AddField("Grunn til avvising", bw.RejectionReason, false)
+		}
+	} else {
+		if bw.OpplysarApprovedBy != nil && *bw.OpplysarApprovedBy != "" {
+			builder.AddField("Opplysar", mentions(bw.OpplysarApprovedBy), true)
+		}
+		if bw.RettskrivarApprovedBy != nil && *bw.RettskrivarApprovedBy != "" {
+			builder.AddField("Rettskrivar", mentions(bw.RettskrivarApprovedBy), true)
+		}
+	}
+	if bw.Reason != "" {
+		builder.AddField("Grunn", bw.Reason, false)
+	}
+	if len(bw.Replacements) > 0 {
+		builder.AddField("Rett form", strings.Join(bw.Replacements, " / "), false)
+	}
+	if bw.ForumThreadID != nil && *bw.ForumThreadID != "" {
+		builder.AddField("Diskusjon", "<#"+*bw.ForumThreadID+">", false)
+	}
+	return builder.SetFooter(fmt.Sprintf("Skriv %sord liste for alle godkjende ord", bot.Config.Discord.Prefix), "").Build()
+}
+
+// mentions turns a comma-separated list of user IDs into mentions
+func mentions(ids *string) string {
+	if ids == nil || *ids == "" {
+		return "Ukjend"
+	}
+	parts := strings.Split(*ids, ",")
+	for i, id := range parts {
+		parts[i] = "<@" + strings.TrimSpace(id) + ">"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wordListPage builds one page of a word list and its pager buttons. Pages past the
+// end show the last page, so a pager on an old message never runs off the list.
+func wordListPage(s discord.Client, bot *bot.Bot, list string, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	status, title, empty := "fully_approved", "📖 Forbodne ord", "Lista er tom."
+	if list == wordListPending {
+		status, title, empty = "pending", "⏳ Ord som ventar på godkjenning", "Ingen ord ventar på godkjenning."
+	}
+
+	count, err := bot.Database.CountBannedWords(status)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages := max(1, (count+wordListPageSize-1)/wordListPageSize)
+	page = max(0, min(page, pages-1))
+	words, err := bot.Database.ListBannedWords(status, page*wordListPageSize, wordListPageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var lines []string
+	for _, bw := range words {
+		line := "• **" + bw.Word + "**"
+		if list == wordListPending {
+			line += " – rapportert av " + bw.AuthorName
+		} else if len(bw.Replacements) > 0 {
+			line += " → " + strings.Join(bw.Replacements, " / ")
+		}
+		lines = append(lines, line)
+	}
+	description := empty
+	if len(lines) > 0 {
+		description = strings.Join(lines, "\n")
+	}
+
+	embed := services.NewEmbedBuilder().
+		SetTitle(title).
+		SetDescription(description).
+		SetColorByType(services.EmbedTypePrimary).
+		SetAuthorFromBot(s).
+		SetFooter(fmt.Sprintf("Side %d/%d · %d ord · Skriv %sord <ord> for meir om eitt", page+1, pages, count, bot.Config.Discord.Prefix), "").
+		Build()
+	return embed, wordListPager(list, page, pages), nil
+}
+
+// wordListPager returns the previous/next buttons for a word list page, or nothing for a single page
+func wordListPager(list string, page, pages int) []discordgo.MessageComponent {
+	if pages <= 1 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "◀ Førre",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s%s:%d", WordListPagePrefix, list, page-1),
+				Disabled: page == 0,
+			},
+			discordgo.Button{
+				Label:    "Neste ▶",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("%s%s:%d", WordListPagePrefix, list, page+1),
+				Disabled: page == pages-1,
+			},
+		}},
+	}
+}
+
+// HandleWordListPage turns a word list message to the page a pager button points at
+func HandleWordListPage(s discord.Client, i *discordgo.InteractionCreate, bot *bot.Bot) {
+	list, pageText, found := strings.Cut(strings.TrimPrefix(i.MessageComponentData().CustomID, WordListPagePrefix), ":")
+	page, err := strconv.Atoi(pageText)
+	if !found || err != nil || (list != wordListApproved && list != wordListPending) {
+		log.Printf("Malformed word list pager ID: %s", i.MessageComponentData().CustomID)
+		return
+	}
+
+	embed, pager, err := wordListPage(s, bot, list, page)
+	if err != nil {
+		log.Printf("Failed to list banned words: %v", err)
+		return
+	}
+	if pager == nil {
+		pager = []discordgo.MessageComponent{}
+	}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: pager,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to turn word list page: %v", err)
+	}
+}
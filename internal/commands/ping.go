@@ -3,6 +3,7 @@ package commands
 import (
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -12,13 +13,14 @@ func init() {
 		description: "Sjekk om boten svarar",
 		emoji:       "🏓",
 		handler:     Ping,
+		category:    CategoryGeneral,
 	}
 }
 
 // Ping handsamer ping-kommandoen
 //--------------------------------------------------------------------------------
 
-func Ping(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func Ping(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, _ Args) {
 	embed := services.CreateBotEmbed(s, "Pong! 🏓", "Bot er oppe og svarar.", services.EmbedTypeSuccess)
 	s.ChannelMessageSendEmbed(m.ChannelID, embed)
 }
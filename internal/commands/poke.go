@@ -3,10 +3,11 @@ package commands
 import (
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -17,19 +18,20 @@ func init() {
 		emoji:       "👉",
 		handler:     handlePoke,
 		adminOnly:   true,
+		args: []Arg{
+			{Name: "mottakarar", Description: "Kven som skal nemnast, standard er den som sende inn spørsmålet", Type: ArgEnum, Choices: []string{"alle"}, Optional: true},
+		},
+		category: CategoryQuestions,
+		examples: []string{"", "alle"},
+		cooldown: time.Minute,
 	}
 }
 
-func handlePoke(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func handlePoke(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
 	db := bot.Database
 	log.Printf("Manual daily question trigger requested by %s", m.Author.Username)
 
-	// Support !poke alle
-	pokeAlle := false
-	args := strings.Fields(m.Content)
-	if len(args) > 1 && args[1] == "alle" {
-		pokeAlle = true
-	}
+	pokeAlle := args.String("mottakarar") == "alle"
 
 	question, err := db.GetLeastAskedApprovedQuestion()
 	if err != nil {
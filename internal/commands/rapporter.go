@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ReportWordCommandName is the message context-menu command for reporting incorrect words
+const ReportWordCommandName = "Rapporter feil ord"
+
+// ReportWordModalPrefix starts the custom ID of the report modal: "rapporter_ord:<channel>:<message>",
+// so the reported message travels with the interaction
+const ReportWordModalPrefix = "rapporter_ord:"
+
+// Text inputs in the report modal
+const (
+	reportWordsField        = "ord"
+	reportReplacementsField = "rett"
+	reportReasonField       = "grunn"
+)
+
+// contextMenuCommands returns the message context-menu commands
+func contextMenuCommands() []*discordgo.ApplicationCommand {
+	return []*discordgo.ApplicationCommand{
+		{Name: ReportWordCommandName, Type: discordgo.MessageApplicationCommand},
+	}
+}
+
+// HandleReportWordCommand opens the report modal for the message the command was used on
+func HandleReportWordCommand(s discord.Client, i *discordgo.InteractionCreate, bot *bot.Bot) {
+	messageID := i.ApplicationCommandData().TargetID
+	log.Printf("[REPORT] Opening report modal for message %s", messageID)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("%s%s:%s", ReportWordModalPrefix, i.ChannelID, messageID),
+			Title:    ReportWordCommandName,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    reportWordsField,
+						Label:       "Ord som er feil",
+						Style:       discordgo.TextInputShort,
+						Placeholder: "ikke, ikke sant, *heten eller /hv(a|em)/ – skil med komma",
+						Required:    true,
+						MaxLength:   255,
+					},
+				}},
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    reportReplacementsField,
+						Label:       "Rett form (valfritt)",
+						Style:       discordgo.TextInputParagraph,
+						Placeholder: "ikke → ikkje, éi linje per ord. Berre «ikkje» held når du rapporterer eitt ord.",
+						Required:    false,
+						MaxLength:   1000,
+					},
+				}},
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  reportReasonField,
+						Label:     "Kvifor er det feil? (valfritt)",
+						Style:     discordgo.TextInputParagraph,
+						Required:  false,
+						MaxLength: 1000,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to open report modal: %v", err)
+	}
+}
+
+// HandleReportWordModal stores the reported words as pending and posts them for approval
+func HandleReportWordModal(s discord.Client, i *discordgo.InteractionCreate, bot *bot.Bot) {
+	data := i.ModalSubmitData()
+	originalChannelID, originalMessageID, found := strings.Cut(strings.TrimPrefix(data.CustomID, ReportWordModalPrefix), ":")
+	if !found {
+		log.Printf("Malformed report modal ID: %s", data.CustomID)
+		return
+	}
+
+	reporter := i.User
+	if i.Member != nil {
+		reporter = i.Member.User
+	}
+	if reporter == nil {
+		return
+	}
+
+	var words, invalid []string
+	for _, word := range bannedwords.SplitEntries(services.ModalValue(data, reportWordsField)) {
+		entry, err := bannedwords.ParseEntry(word)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("«%s»: %v", word, err))
+			continue
+		}
+		if !containsFold(words, entry.String()) {
+			words = append(words, entry.String())
+		}
+	}
+	if len(invalid) > 0 || len(words) == 0 {
+		description := "Skriv minst eitt ord som er feil."
+		if len(invalid) > 0 {
+			description = "Kunne ikkje tolke:\n" + strings.Join(invalid, "\n")
+		}
+		embed := services.CreateBotEmbed(s, "❓ Ingen ord", description, services.EmbedTypeWarning)
+		respondEphemeral(s, i.Interaction, &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}})
+		return
+	}
+
+	replacements, err := parseReplacements(services.ModalValue(data, reportReplacementsField), words)
+	if err != nil {
+		embed := services.CreateBotEmbed(s, "❓ Ukjent ord", err.Error(), services.EmbedTypeWarning)
+		respondEphemeral(s, i.Interaction, &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}})
+		return
+	}
+
+	reason := strings.TrimSpace(services.ModalValue(data, reportReasonField))
+	if reason == "" {
+		reason = "Reported via context menu"
+	}
+
+	approvalService := &services.ApprovalService{Bot: bot}
+	log.Printf("[REPORT] %s reported %v in message %s", reporter.ID, words, originalMessageID)
+	newWords, existingWords := approvalService.ReportBannedWords(words, replacements, reason, reporter.ID, reporter.Username, originalChannelID, originalMessageID)
+
+	var confirmText string
+	if len(newWords) > 0 && len(existingWords) > 0 {
+		confirmText = fmt.Sprintf("Takk! Nye ord lagt til: %s. Finst allereie: %s", strings.Join(newWords, ", "), strings.Join(existingWords, ", "))
+	} else if len(newWords) > 0 {
+		confirmText = fmt.Sprintf("Takk! Desse orda har blitt lagt til som forbodne: %s", strings.Join(newWords, ", "))
+	} else {
+		confirmText = fmt.Sprintf("Alle orda finst allereie i lista over forbodne ord: %s", strings.Join(existingWords, ", "))
+	}
+
+	if len(newWords) > 0 {
+		confirmText += "\n\nEi diskusjonstråd vil bli oppretta etter godkjenning. Sjekk grammatikkforumet seinare."
+	} else {
+		confirmText += "\n\nSjå eksisterande diskusjonar i grammatikkforumet for desse orda."
+	}
+
+	embed := services.CreateSuccessEmbed("Ord rapporterte", confirmText)
+	respondEphemeral(s, i.Interaction, &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+// replacementArrows separate a word from its replacements in "ikke → ikkje"
+var replacementArrows = []string{"→", "->", "="}
+
+// parseReplacements reads one "ord → rett form" line per word, keyed by the word's entry.
+// A line without an arrow belongs to the only reported word.
+func parseReplacements(text string, words []string) (map[string][]string, error) {
+	replacements := make(map[string][]string)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		word, value := "", line
+		for _, arrow := range replacementArrows {
+			if before, after, found := strings.Cut(line, arrow); found {
+				word, value = before, after
+				break
+			}
+		}
+
+		if word == "" {
+			if len(words) != 1 {
+				return nil, fmt.Errorf("Skriv «ord → rett form» når du rapporterer fleire ord, ikkje «%s».", strings.TrimSpace(line))
+			}
+			word = words[0]
+		}
+		entry, err := bannedwords.ParseEntry(word)
+		if err != nil || !containsFold(words, entry.String()) {
+			return nil, fmt.Errorf("«%s» er ikkje mellom orda du rapporterte.", strings.TrimSpace(word))
+		}
+		replacements[entry.String()] = append(replacements[entry.String()], bannedwords.SplitReplacements(value)...)
+	}
+	return replacements, nil
+}
+
+func containsFold(words []string, word string) bool {
+	for _, w := range words {
+		if strings.EqualFold(w, word) {
+			return true
+		}
+	}
+	return false
+}
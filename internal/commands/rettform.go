@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"askeladden/internal/bannedwords"
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"askeladden/internal/permissions"
+	"github.com/bwmarrin/discordgo"
+)
+
+func init() {
+	commands["rettform"] = Command{
+		name:        "rettform",
+		description: "Set kva eit forbode ord skal bytast ut med (kun for rettskrivarar)",
+		emoji:       "✏️",
+		handler:     handleRettform,
+		args: []Arg{
+			{Name: "ord", Description: "Det forbodne ordet", Type: ArgString},
+			{Name: "forslag", Description: "Rett form, fleire skilde med /, eller «fjern»", Type: ArgRest, Choices: []string{"fjern"}},
+		},
+		category: CategoryWords,
+		examples: []string{"ikke ikkje", "noe noko / noka", "ikke fjern"},
+	}
+}
+
+func handleRettform(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	if !permissions.NewPermissionManager(bot.Config).HasRettskrivarRole(s, m.GuildID, m.Author.ID) {
+		embed := services.CreateBotEmbed(s, "⛔ Ingen tilgang", "Berre rettskrivarar kan endre rett form på forbodne ord.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	word := args.String("ord")
+	entry, err := bannedwords.ParseEntry(word)
+	var bannedWord *database.BannedWord
+	if err == nil {
+		_, bannedWord, err = bot.Database.IsBannedWord(entry.String())
+	}
+	if err != nil || bannedWord == nil {
+		embed := services.CreateBotEmbed(s, "❓ Ukjent ord", fmt.Sprintf("«%s» er ikkje i lista over forbodne ord.", word), services.EmbedTypeWarning)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	var replacements []string
+	if args.String("forslag") != "fjern" {
+		replacements = bannedwords.SplitReplacements(args.String("forslag"))
+	}
+	if err := bot.Database.UpdateBannedWordReplacements(bannedWord.ID, replacements); err != nil {
+		log.Printf("Failed to update replacements of %s: %v", bannedWord.Word, err)
+		embed := services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje lagre rett form.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	log.Printf("%s set replacements of %s to %v", m.Author.Username, bannedWord.Word, replacements)
+	bot.BannedWords.Reload(bot.Database)
+	(&services.ApprovalService{Bot: bot}).RefreshBannedWordApproval(s, bannedWord.ID)
+
+	description := fmt.Sprintf("**%s** har ikkje lenger noko forslag til rett form.", bannedWord.Word)
+	if len(replacements) > 0 {
+		description = fmt.Sprintf("**%s** → **%s**", bannedWord.Word, strings.Join(replacements, "** / **"))
+	}
+	embed := services.CreateBotEmbed(s, "✏️ Rett form lagra", description, services.EmbedTypeSuccess)
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"log"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// warningModes maps the choices of ?rettleiing to stored warning modes
+var warningModes = map[string]string{
+	"på": database.WarningModeOn,
+	"av": database.WarningModeOff,
+	"dm": database.WarningModeDM,
+}
+
+// warningModeText describes each warning mode to the user
+var warningModeText = map[string]string{
+	database.WarningModeOn:  "Du får språkrettleiing som svar i kanalen.",
+	database.WarningModeOff: "Du får ikkje språkrettleiing.",
+	database.WarningModeDM:  "Du får språkrettleiing på direktemelding.",
+}
+
+func init() {
+	commands["rettleiing"] = Command{
+		name:        "rettleiing",
+		description: "Vel om og korleis du vil få språkrettleiing",
+		emoji:       "📝",
+		handler:     handleRettleiing,
+		args: []Arg{
+			{Name: "modus", Description: "«på» for svar i kanalen, «dm» for direktemelding, «av» for inga rettleiing", Type: ArgEnum, Choices: []string{"på", "av", "dm"}, Optional: true},
+		},
+		category: CategoryWords,
+		examples: []string{"", "dm", "av"},
+	}
+}
+
+func handleRettleiing(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
+	if !args.Has("modus") {
+		mode, err := bot.Database.GetWarningMode(m.Author.ID)
+		if err != nil {
+			embed := services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje hente innstillinga di.", services.EmbedTypeError)
+			s.ChannelMessageSendEmbed(m.ChannelID, embed)
+			return
+		}
+		embed := services.CreateBotEmbed(s, "📝 Språkrettleiing", warningModeText[mode]+"\n\nEndre med `"+Usage(commands["rettleiing"], bot.Config.Discord.Prefix)+"`.", services.EmbedTypeInfo)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+
+	mode := warningModes[args.String("modus")]
+	if err := bot.Database.SetWarningMode(m.Author.ID, mode); err != nil {
+		log.Printf("Failed to set warning mode for %s: %v", m.Author.ID, err)
+		embed := services.CreateBotEmbed(s, "❌ Feil", "Kunne ikkje lagre innstillinga di.", services.EmbedTypeError)
+		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		return
+	}
+	log.Printf("%s set warning mode to %s", m.Author.Username, mode)
+	embed := services.CreateBotEmbed(s, "✅ Språkrettleiing endra", warningModeText[mode], services.EmbedTypeSuccess)
+	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+}
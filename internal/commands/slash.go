@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxDescriptionLength is Discord's limit for application command descriptions
+const maxDescriptionLength = 100
+
+// ApplicationCommands returns a slash command definition for every registered command, sorted by name,
+// followed by the message context-menu commands
+func ApplicationCommands() []*discordgo.ApplicationCommand {
+	names := getCommandNames()
+
+	applicationCommands := make([]*discordgo.ApplicationCommand, 0, len(names))
+	for _, name := range names {
+		cmd := commands[name]
+		applicationCommands = append(applicationCommands, &discordgo.ApplicationCommand{
+			Name:        cmd.name,
+			Description: truncateDescription(cmd.description),
+			Options:     applicationCommandOptions(cmd.args),
+		})
+	}
+	return append(applicationCommands, contextMenuCommands()...)
+}
+
+// RegisterApplicationCommands replaces the bot's global slash commands with the command registry,
+// so commands that were removed or renamed disappear from Discord as well
+func RegisterApplicationCommands(s discord.Client, appID string) error {
+	registered, err := s.ApplicationCommandBulkOverwrite(appID, "", ApplicationCommands())
+	if err != nil {
+		return fmt.Errorf("failed to register application commands: %w", err)
+	}
+	log.Printf("[COMMANDS] Registered %d application commands", len(registered))
+	return nil
+}
+
+// MatchAndRunApplicationCommand runs the command a slash command interaction invokes.
+// The interaction is turned into the message the prefix command would have been,
+// and the command's first message to the channel becomes the interaction reply.
+func MatchAndRunApplicationCommand(s discord.Client, i *discordgo.InteractionCreate, bot *bot.Bot) {
+	name := i.ApplicationCommandData().Name
+	cmd, exists := commands[name]
+	if !exists {
+		log.Printf("[COMMANDS] Unknown application command '%s'", name)
+		respondEphemeral(s, i.Interaction, &discordgo.InteractionResponseData{Content: "Ukjend kommando."})
+		return
+	}
+
+	client := &interactionClient{Client: s, interaction: i.Interaction}
+	options := i.ApplicationCommandData().Options
+	if args, err := parseOptions(cmd.args, options); err != nil {
+		sendArgError(client, i.ChannelID, cmd, "/", err)
+	} else {
+		execute(cmd, client, messageFromInteraction(i, cmd, options, bot.Config.Discord.Prefix), bot, args)
+	}
+
+	// Commands that only post elsewhere still have to acknowledge the interaction
+	if !client.responded {
+		embed := services.CreateBotEmbed(s, "✅ Utført", fmt.Sprintf("`/%s` er utført.", cmd.name), services.EmbedTypeSuccess)
+		if client.deferred {
+			client.ChannelMessageSendEmbed(i.ChannelID, embed)
+		} else {
+			respondEphemeral(s, i.Interaction, &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}})
+		}
+	}
+}
+
+// messageFromInteraction builds the prefix command message equivalent to a slash command,
+// with attachment options as the message's attachments
+func messageFromInteraction(i *discordgo.InteractionCreate, cmd Command, options []*discordgo.ApplicationCommandInteractionDataOption, prefix string) *discordgo.MessageCreate {
+	given := make(map[string]string, len(options))
+	for _, option := range options {
+		given[option.Name] = optionText(option)
+	}
+	content := []string{prefix + cmd.name}
+	var attachments []*discordgo.MessageAttachment
+	for _, arg := range cmd.args {
+		text, exists := given[arg.Name]
+		switch {
+		case !exists:
+		case arg.Type == ArgAttachment:
+			if resolved := i.ApplicationCommandData().Resolved; resolved != nil && resolved.Attachments[text] != nil {
+				attachments = append(attachments, resolved.Attachments[text])
+			}
+		default:
+			content = append(content, text)
+		}
+	}
+
+	author := i.User
+	if i.Member != nil {
+		author = i.Member.User
+	}
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ID:          i.ID,
+		ChannelID:   i.ChannelID,
+		GuildID:     i.GuildID,
+		Content:     strings.Join(content, " "),
+		Author:      author,
+		Member:      i.Member,
+		Attachments: attachments,
+	}}
+}
+
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxDescriptionLength {
+		return description
+	}
+	return string(runes[:maxDescriptionLength-1]) + "…"
+}
+
+// respondEphemeral replies to an interaction with a message only the invoking user sees
+func respondEphemeral(s discord.Client, interaction *discordgo.Interaction, data *discordgo.InteractionResponseData) {
+	data.Flags |= discordgo.MessageFlagsEphemeral
+	err := s.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	})
+	if err != nil {
+		log.Printf("Failed to send interaction response: %v", err)
+	}
+}
+
+// interactionClient sends the first message a command posts to the interaction's
+// channel as the interaction reply, and everything else as usual
+type interactionClient struct {
+	discord.Client
+	interaction *discordgo.Interaction
+	deferred    bool // acknowledged; the reply is sent by editing the response
+	responded   bool
+}
+
+// deferReply acknowledges a slash command before slow work, so that its reply can come
+// after Discord's 3-second deadline. Prefix commands are left alone.
+func deferReply(s discord.Client) {
+	client, ok := s.(*interactionClient)
+	if !ok || client.deferred || client.responded {
+		return
+	}
+	err := client.Client.InteractionRespond(client.interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Failed to defer interaction response: %v", err)
+		return
+	}
+	client.deferred = true
+}
+
+func (c *interactionClient) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return c.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content}, options...)
+}
+
+func (c *interactionClient) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return c.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}, options...)
+}
+
+func (c *interactionClient) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if c.responded || channelID != c.interaction.ChannelID {
+		return c.Client.ChannelMessageSendComplex(channelID, data, options...)
+	}
+	c.responded = true
+
+	embeds := append([]*discordgo.MessageEmbed(nil), data.Embeds...)
+	if data.Embed != nil {
+		embeds = append(embeds, data.Embed)
+	}
+	if c.deferred {
+		return c.Client.InteractionResponseEdit(c.interaction, &discordgo.WebhookEdit{
+			Content:    &data.Content,
+			Embeds:     &embeds,
+			Components: &data.Components,
+		}, options...)
+	}
+	err := c.Client.InteractionRespond(c.interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    data.Content,
+			Embeds:     embeds,
+			Components: data.Components,
+		},
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.InteractionResponse(c.interaction, options...)
+}
@@ -3,10 +3,11 @@ package commands
 import (
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -17,26 +18,19 @@ func init() {
 		emoji:       "❓",
 		handler:     Spor,
 		aliases:     []string{"spor"},
+		args: []Arg{
+			{Name: "spørsmål", Description: "Spørsmålet du vil leggje til", Type: ArgRest},
+		},
+		category: CategoryQuestions,
+		examples: []string{"Kva er yndlingsmaten din?"},
+		cooldown: 30 * time.Second,
 	}
 }
 
 // Spor handsamer spør-kommandoen
-func Spor(s *discordgo.Session, m *discordgo.MessageCreate, bot *bot.Bot) {
+func Spor(s discord.Client, m *discordgo.MessageCreate, bot *bot.Bot, args Args) {
 	db := bot.Database
-	// Parse kommandoen for å hente spørsmålet
-	parts := strings.SplitN(m.Content, " ", 2)
-	if len(parts) < 2 {
-		embed := services.CreateBotEmbed(s, "❓ Feil", "Du må skrive eit spørsmål! Døme: `!spør Kva er din yndlingsmat?`", services.EmbedTypeError)
-		s.ChannelMessageSendEmbed(m.ChannelID, embed)
-		return
-	}
-
-	question := strings.TrimSpace(parts[1])
-	if question == "" {
-		embed := services.CreateBotEmbed(s, "❓ Feil", "Spørsmålet kan ikkje vere tomt!", services.EmbedTypeError)
-		s.ChannelMessageSendEmbed(m.ChannelID, embed)
-		return
-	}
+	question := args.String("spørsmål")
 
 	// Send bekreftelse til brukaren
 	embed := services.CreateBotEmbed(s, "📝 Spørsmål motteke!", fmt.Sprintf("Takk! Spørsmålet ditt er sendt til godkjenning: \"%s\"\n\n*Du får ei melding når det vert godkjent av opplysarane våre! ✨*", question), services.EmbedTypeInfo)
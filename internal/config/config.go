@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,6 +23,18 @@ type Config struct {
 	BannedWords struct {
 		ApprovalChannelID string `yaml:"approvalChannelID"`
 		RettskrivarRoleID string `yaml:"rettskrivarRoleID"`
+		LexiconFile       string `yaml:"lexiconFile"` // lemma/form list used to match inflected forms
+
+		// Grammar warnings: at most one per user per UserCooldown and one per channel per
+		// ChannelCooldown (defaults 10m and 1m). Detection runs in Channels, or everywhere
+		// if empty, except IgnoredChannels. Threads count as their parent channel.
+		UserCooldown    time.Duration `yaml:"userCooldown"`
+		ChannelCooldown time.Duration `yaml:"channelCooldown"`
+		Channels        []string      `yaml:"channels"`
+		IgnoredChannels []string      `yaml:"ignoredChannels"`
+
+		// React ✅ when the author edits away every flagged word
+		ReactOnCorrection bool `yaml:"reactOnCorrection"`
 	} `yaml:"bannedwords"`
 
 	Grammar struct {
@@ -35,6 +48,8 @@ type Config struct {
 	} `yaml:"starboard"`
 
 	Database struct {
+		Driver   string `yaml:"driver"` // mysql (default) or sqlite
+		Path     string `yaml:"path"`   // database file, only used by sqlite
 		User     string `yaml:"user"`
 		Password string `yaml:"password"`
 		Host     string `yaml:"host"`
@@ -43,12 +58,13 @@ type Config struct {
 	} `yaml:"database"`
 
 	Scheduler struct {
-		CronString      string `yaml:"cron_string"`
-		Timezone        string `yaml:"timezone"`
-		MorningTime     string `yaml:"morning_time"`
-		EveningTime     string `yaml:"evening_time"`
-		InactivityHours int    `yaml:"inactivity_hours"`
-		Enabled         bool   `yaml:"enabled"`
+		CronString         string `yaml:"cron_string"`
+		Timezone           string `yaml:"timezone"`
+		MorningTime        string `yaml:"morning_time"`
+		EveningTime        string `yaml:"evening_time"`
+		InactivityHours    int    `yaml:"inactivity_hours"`
+		ActivityMinAuthors int    `yaml:"activity_min_authors"` // distinct authors within InactivityHours that count as activity
+		Enabled            bool   `yaml:"enabled"`
 	} `yaml:"scheduler"`
 
 	// Reaction emojis
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Entity types that can be approved
+const (
+	ApprovalEntityQuestion          = "question"
+	ApprovalEntityBannedWord        = "banned_word"
+	ApprovalEntityBannedWordRemoval = "banned_word_removal"
+	ApprovalEntityBannedWordImport  = "banned_word_import" // keyed by the ID of the first word imported
+)
+
+// Roles an approval can be given under
+const (
+	ApprovalRoleOpplysar    = "opplysar"
+	ApprovalRoleRettskrivar = "rettskrivar"
+)
+
+// Approval is one user's approval of a question or banned word under one role
+type Approval struct {
+	ID         int
+	EntityType string
+	EntityID   int
+	UserID     string
+	Role       string
+	CreatedAt  time.Time
+}
+
+// AddApproval records that a user approved an entity under a role. Approving twice is a no-op.
+func (db *DB) AddApproval(entityType string, entityID int, userID, role string) error {
+	log.Printf("Recording %s approval of %s %d by %s", role, entityType, entityID, userID)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE entity_type = ? AND entity_id = ? AND user_id = ? AND role = ?", db.approvalsTable)
+	if err := db.conn.QueryRow(query, entityType, entityID, userID, role).Scan(&count); err != nil {
+		log.Printf("Failed to check existing approval: %v", err)
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (entity_type, entity_id, user_id, role) VALUES (?, ?, ?, ?)", db.approvalsTable)
+	if _, err := db.conn.Exec(query, entityType, entityID, userID, role); err != nil {
+		log.Printf("Failed to record approval: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetApprovals returns the approvals of an entity in the order they were given
+func (db *DB) GetApprovals(entityType string, entityID int) ([]*Approval, error) {
+	query := fmt.Sprintf("SELECT id, entity_type, entity_id, user_id, role, created_at FROM %s WHERE entity_type = ? AND entity_id = ? ORDER BY created_at ASC, id ASC", db.approvalsTable)
+	rows, err := db.conn.Query(query, entityType, entityID)
+	if err != nil {
+		log.Printf("Failed to get approvals for %s %d: %v", entityType, entityID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []*Approval
+	for rows.Next() {
+		var a Approval
+		if err := rows.Scan(&a.ID, &a.EntityType, &a.EntityID, &a.UserID, &a.Role, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, &a)
+	}
+	return approvals, rows.Err()
+}
@@ -1,11 +1,14 @@
 package database
 
-import "log"
+import (
+	"fmt"
+	"log"
+)
 
 // ApproveAllPendingQuestions approves all questions with status 'pending'
 func (db *DB) ApproveAllPendingQuestions(approverID string) error {
 	log.Printf("Approving ALL pending questions by approver %s", approverID)
-	query := `UPDATE daily_questions SET approval_status='approved', approved_by=?, approved_at=NOW() WHERE approval_status='pending'`
+	query := fmt.Sprintf("UPDATE %s SET approval_status='approved', approved_by=?, approved_at=CURRENT_TIMESTAMP WHERE approval_status='pending'", db.tableName)
 	_, err := db.conn.Exec(query, approverID)
 	if err != nil {
 		log.Printf("Failed to approve all pending questions: %v", err)
@@ -25,19 +25,58 @@ type DatabaseIface interface {
 	GetLeastAskedApprovedQuestion() (*Question, error)
 	IncrementQuestionUsage(questionID int) error
 	GetApprovedQuestionStats() (int, int, int, error)
+	UpdateQuestionText(questionID int, question string) error
 	AddBannedWord(word, reason, authorID string) error
 	AddBannedWordPending(word, reason, authorID, authorName, forumThreadID, originalMessageID string) (int64, error)
 	UpdateBannedWordApprovalMessageID(wordID int, approvalMessageID string) error
 	GetBannedWordByApprovalMessageID(approvalMessageID string) (*BannedWord, error)
+	UpdateBannedWordImportMessageID(wordID int, importMessageID string) error
+	GetBannedWordsByImportMessageID(importMessageID string) ([]*BannedWord, error)
 	ApproveBannedWordByOpplysar(wordID int, approverID string) error
 	ApproveBannedWordByRettskrivar(wordID int, approverID string) error
-	RejectBannedWord(wordID int, rejectorID string) error
+	ApproveBannedWordCombined(wordID int, opplysarApprovers, rettskrivarApprovers []string) error
+	UpdateBannedWordForumThreadID(wordID int, forumThreadID string) error
+	RejectBannedWord(wordID int, rejectorID, reason string) error
 	GetPendingBannedWord() (*BannedWord, error)
 	GetBannedWordByID(wordID int) (*BannedWord, error)
 	GetBannedWordApprovalStats() (int, int, int, int, error)
 	RemoveBannedWord(word string) error
 	IsBannedWord(word string) (bool, *BannedWord, error)
 	GetBannedWords() ([]*BannedWord, error)
+	ListBannedWords(status string, offset, limit int) ([]*BannedWord, error)
+	CountBannedWords(status string) (int, error)
+	SearchBannedWords(text string, limit int) ([]*BannedWord, error)
+	UpdateBannedWordText(wordID int, word string) error
+	UpdateBannedWordForms(wordID int, forms []string) error
+	UpdateBannedWordReplacements(wordID int, replacements []string) error
+	// Approval methods
+	AddApproval(entityType string, entityID int, userID, role string) error
+	GetApprovals(entityType string, entityID int) ([]*Approval, error)
+	// User settings methods
+	GetWarningMode(userID string) (string, error)
+	SetWarningMode(userID, mode string) error
+	// Grammar warning methods
+	AddWarning(originalMessageID, channelID, warningMessageID, warningChannelID string) error
+	GetWarning(originalMessageID string) (*Warning, error)
+	RemoveWarning(originalMessageID string) error
+	PruneWarnings(before time.Time) (int64, error)
+	// Banned word removal methods
+	AddBannedWordRemoval(wordID int, word, reason, requestedBy string) (int64, error)
+	GetBannedWordRemoval(removalID int) (*BannedWordRemoval, error)
+	GetPendingBannedWordRemoval(wordID int) (*BannedWordRemoval, error)
+	UpdateBannedWordRemovalMessageID(removalID int, approvalMessageID string) error
+	ApproveBannedWordRemoval(removalID int, opplysarApprovers, rettskrivarApprovers []string) error
+	RejectBannedWordRemoval(removalID int, rejectorID, reason string) error
+	// Scheduler state methods
+	GetSchedulerState() (*SchedulerState, error)
+	ClaimDailyPost(day string, at time.Time) (bool, error)
+	RecordDailyPost(questionID int, messageID string) error
+	ReleaseDailyPost(day string) error
+	RecordActivity(at time.Time) error
+
+	// Scheduled job methods
+	GetJobRuns() (map[string]*JobRun, error)
+	RecordJobRun(name string, at time.Time, runErr string) error
 	// Starboard methods
 	AddStarboardMessage(originalMessageID, starboardMessageID, channelID string) error
 	GetStarboardMessage(originalMessageID string) (string, error)
@@ -51,26 +90,57 @@ type DatabaseIface interface {
 var _ DatabaseIface = (*DB)(nil)
 
 type DB struct {
-	conn              *sql.DB
-	tableName         string // Dynamic table name (daily_questions or daily_questions_testing)
-	bannedWordsTable  string // banned_bokmal_words or banned_bokmal_words_testing
-	starboardTable    string // starboard_messages or starboard_messages_testing
+	conn             *sql.DB
+	driver           string // DriverMySQL or DriverSQLite
+	tableName        string // Dynamic table name (daily_questions or daily_questions_testing)
+	bannedWordsTable string // banned_bokmal_words or banned_bokmal_words_testing
+	starboardTable   string // starboard_messages or starboard_messages_testing
+	approvalsTable   string // approvals or approvals_testing
+	settingsTable    string // user_settings or user_settings_testing
+	warningsTable    string // warnings or warnings_testing
+	removalsTable    string // banned_word_removals or banned_word_removals_testing
+	schedulerTable   string // scheduler_state or scheduler_state_testing
+	jobsTable        string // scheduled_jobs or scheduled_jobs_testing
+	migrationsTable  string // schema_migrations or schema_migrations_testing
+	migrations       []Migration
 }
 
-// New creates a new database connection
+// New creates a new database connection and applies any pending migrations
 func New(cfg *config.Config) (*DB, error) {
-	log.Printf("Connecting to database at %s:%d", cfg.Database.Host, cfg.Database.Port)
-	connStr := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
-		cfg.Database.User, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
-
-	conn, err := sql.Open("mysql", connStr)
+	db, err := Open(cfg)
 	if err != nil {
-		log.Printf("Failed to open database connection: %v", err)
 		return nil, err
 	}
 
-	if err := conn.Ping(); err != nil {
-		log.Printf("Failed to ping database: %v", err)
+	if err := db.Migrate(); err != nil {
+		log.Printf("Failed to run migrations: %v", err)
+		db.Close()
+		return nil, err
+	}
+
+	log.Println("Database initialization completed")
+	return db, nil
+}
+
+// Open creates a new database connection without touching the schema.
+// The backend is chosen by the database.driver config key (mysql or sqlite).
+func Open(cfg *config.Config) (*DB, error) {
+	driver := cfg.Database.Driver
+	if driver == "" {
+		driver = DriverMySQL
+	}
+
+	var conn *sql.DB
+	var err error
+	switch driver {
+	case DriverMySQL:
+		conn, err = openMySQL(cfg)
+	case DriverSQLite:
+		conn, err = openSQLite(cfg)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", driver)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -79,167 +149,70 @@ func New(cfg *config.Config) (*DB, error) {
 	// Determine table names based on config
 	tableName := "daily_questions"
 	bannedWordsTable := "banned_bokmal_words"
-
 	starboardTable := "starboard_messages"
-	
+	approvalsTable := "approvals"
+	settingsTable := "user_settings"
+	warningsTable := "warnings"
+	removalsTable := "banned_word_removals"
+	schedulerTable := "scheduler_state"
+	jobsTable := "scheduled_jobs"
+	migrationsTable := "schema_migrations"
+
 	if cfg.TableSuffix != "" {
 		tableName += cfg.TableSuffix
 		bannedWordsTable += cfg.TableSuffix
 		starboardTable += cfg.TableSuffix
+		approvalsTable += cfg.TableSuffix
+		settingsTable += cfg.TableSuffix
+		warningsTable += cfg.TableSuffix
+		removalsTable += cfg.TableSuffix
+		schedulerTable += cfg.TableSuffix
+		jobsTable += cfg.TableSuffix
+		migrationsTable += cfg.TableSuffix
 		log.Printf("Using beta table names: %s, %s, %s", tableName, bannedWordsTable, starboardTable)
 	}
 
-	db := &DB{
+	migrations, err := loadMigrations(driver, cfg.TableSuffix)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &DB{
 		conn:             conn,
+		driver:           driver,
 		tableName:        tableName,
 		bannedWordsTable: bannedWordsTable,
 		starboardTable:   starboardTable,
-	}
-
-	// Create tables if they don't exist
-	log.Println("Creating database tables if they don't exist")
-	if err := db.createTables(); err != nil {
-		log.Printf("Failed to create tables: %v", err)
-		return nil, err
-	}
-
-	// Create banned Bokmål words table
-	bannedWordsQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		word VARCHAR(255) NOT NULL UNIQUE,
-		reason TEXT,
-		author_id VARCHAR(255) NOT NULL,
-		author_name VARCHAR(255) NOT NULL,
-		forum_thread_id VARCHAR(255),
-		original_message_id VARCHAR(255),
-		approval_status ENUM('pending', 'opplysar_approved', 'fully_approved', 'rejected') DEFAULT 'pending',
-		approval_message_id VARCHAR(255),
-		opplysar_approved_by VARCHAR(255),
-		opplysar_approved_at TIMESTAMP NULL,
-		rettskrivar_approved_by VARCHAR(255),
-		rettskrivar_approved_at TIMESTAMP NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`, db.bannedWordsTable)
-
-	log.Printf("Creating table: %s", db.bannedWordsTable)
-	if _, err := db.conn.Exec(bannedWordsQuery); err != nil {
-		return nil, fmt.Errorf("failed to create %s table: %w", db.bannedWordsTable, err)
-	}
-
-	// Run migrations
-	if err := db.runMigrations(); err != nil {
-		log.Printf("Failed to run migrations: %v", err)
-		return nil, err
-	}
-
-	log.Println("Database initialization completed")
-	return db, nil
-}
-
-// createTables creates the necessary database tables if they don't exist
-func (db *DB) createTables() error {
-	// Create questions table with approval column and usage tracking
-	// Use dynamic table name (daily_questions or daily_questions_testing)
-	questionsQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		question TEXT NOT NULL,
-		author_id VARCHAR(255) NOT NULL,
-		author_name VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		times_asked INT DEFAULT 0,
-		last_asked_at TIMESTAMP NULL,
-		message_id VARCHAR(255),
-		channel_id VARCHAR(255),
-		approval_status ENUM('pending', 'approved', 'rejected') DEFAULT 'pending',
-		approval_message_id VARCHAR(255),
-		approved_by VARCHAR(255),
-		approved_at TIMESTAMP NULL
-	);`, db.tableName)
-
-	log.Printf("Creating table if not exists: %s", db.tableName)
-	if _, err := db.conn.Exec(questionsQuery); err != nil {
-		return fmt.Errorf("failed to create %s table: %w", db.tableName, err)
-	}
-
-	// Create starboard messages table
-	starboardQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		original_message_id VARCHAR(255) NOT NULL UNIQUE,
-		starboard_message_id VARCHAR(255) NOT NULL,
-		channel_id VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`, db.starboardTable)
-
-	log.Printf("Creating table if not exists: %s", db.starboardTable)
-	if _, err := db.conn.Exec(starboardQuery); err != nil {
-		return fmt.Errorf("failed to create %s table: %w", db.starboardTable, err)
-	}
-
-	return nil
-}
-
-// resetTables creates the necessary database tables (ONLY FOR TESTING - DROPS EXISTING DATA)
-func (db *DB) resetTables() error {
-	// Drop tables if they exist
-	log.Println("Dropping existing tables if any")
-	_, err := db.conn.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s, %s", db.tableName, db.bannedWordsTable))
-	if err != nil {
-		return fmt.Errorf("failed to drop tables: %w", err)
-	}
-	// Create questions table with approval column and usage tracking
-	// Use dynamic table name (daily_questions or daily_questions_testing)
-	questionsQuery := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
-		id INT AUTO_INCREMENT PRIMARY KEY,
-		question TEXT NOT NULL,
-		author_id VARCHAR(255) NOT NULL,
-		author_name VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		times_asked INT DEFAULT 0,
-		last_asked_at TIMESTAMP NULL,
-		message_id VARCHAR(255),
-		channel_id VARCHAR(255),
-		approval_status ENUM('pending', 'approved', 'rejected') DEFAULT 'pending',
-		approval_message_id VARCHAR(255),
-		approved_by VARCHAR(255),
-		approved_at TIMESTAMP NULL
-	);`, db.tableName)
-
-	log.Printf("Creating table: %s", db.tableName)
-	if _, err := db.conn.Exec(questionsQuery); err != nil {
-		return fmt.Errorf("failed to create %s table: %w", db.tableName, err)
-	}
-
-	return nil
+		approvalsTable:   approvalsTable,
+		settingsTable:    settingsTable,
+		warningsTable:    warningsTable,
+		removalsTable:    removalsTable,
+		schedulerTable:   schedulerTable,
+		jobsTable:        jobsTable,
+		migrationsTable:  migrationsTable,
+		migrations:       migrations,
+	}, nil
 }
 
-// runMigrations runs database migrations to update existing tables
-func (db *DB) runMigrations() error {
-	log.Println("Running database migrations")
+// openMySQL connects to the configured MySQL server
+func openMySQL(cfg *config.Config) (*sql.DB, error) {
+	log.Printf("Connecting to database at %s:%d", cfg.Database.Host, cfg.Database.Port)
+	connStr := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
+		cfg.Database.User, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
 
-	// Migration 1: Add forum_thread_id column to banned words table if it doesn't exist
-	columnCheckQuery := fmt.Sprintf("SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = '%s' AND column_name = 'forum_thread_id'", db.bannedWordsTable)
-	var columnExists int
-	err := db.conn.QueryRow(columnCheckQuery).Scan(&columnExists)
+	conn, err := sql.Open("mysql", connStr)
 	if err != nil {
-		log.Printf("Failed to check if forum_thread_id column exists: %v", err)
-		return err
+		log.Printf("Failed to open database connection: %v", err)
+		return nil, err
 	}
 
-	if columnExists == 0 {
-		log.Printf("Adding forum_thread_id column to %s table", db.bannedWordsTable)
-		alterQuery := fmt.Sprintf("ALTER TABLE %s ADD COLUMN forum_thread_id VARCHAR(255) NULL", db.bannedWordsTable)
-		_, err = db.conn.Exec(alterQuery)
-		if err != nil {
-			log.Printf("Failed to add forum_thread_id column: %v", err)
-			return err
-		}
-		log.Printf("Successfully added forum_thread_id column to %s", db.bannedWordsTable)
-	} else {
-		log.Printf("forum_thread_id column already exists in %s", db.bannedWordsTable)
+	if err := conn.Ping(); err != nil {
+		log.Printf("Failed to ping database: %v", err)
+		conn.Close()
+		return nil, err
 	}
-
-	log.Println("Database migrations completed")
-	return nil
+	return conn, nil
 }
 
 // Question represents a question from the database
@@ -275,6 +248,38 @@ type BannedWord struct {
 	RettskrivarApprovedAt *time.Time
 	CreatedAt             time.Time
 	OriginalMessageID     *string
+	Forms                 []string // explicitly listed inflected forms
+	Replacements          []string // suggested correct forms, e.g. "ikkje" for "ikke"
+	RejectionReason       string
+	ImportMessageID       *string // summary post of the wordlist import that added the word
+}
+
+// bannedWordColumns lists the columns read by scanBannedWord, in order
+const bannedWordColumns = "id, word, reason, author_id, author_name, forum_thread_id, approval_status, approval_message_id, opplysar_approved_by, opplysar_approved_at, rettskrivar_approved_by, rettskrivar_approved_at, created_at, original_message_id, forms, replacements, rejection_reason, import_message_id"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanBannedWord scans a row selected with bannedWordColumns
+func scanBannedWord(row rowScanner) (*BannedWord, error) {
+	var bw BannedWord
+	var reason, forms, replacements, rejectionReason sql.NullString
+	err := row.Scan(
+		&bw.ID, &bw.Word, &reason, &bw.AuthorID, &bw.AuthorName, &bw.ForumThreadID,
+		&bw.ApprovalStatus, &bw.ApprovalMessageID, &bw.OpplysarApprovedBy, &bw.OpplysarApprovedAt,
+		&bw.RettskrivarApprovedBy, &bw.RettskrivarApprovedAt, &bw.CreatedAt, &bw.OriginalMessageID, &forms, &replacements, &rejectionReason,
+		&bw.ImportMessageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bw.Reason = reason.String
+	bw.Forms = splitLines(forms.String)
+	bw.Replacements = splitLines(replacements.String)
+	bw.RejectionReason = rejectionReason.String
+	return &bw, nil
 }
 
 // StarboardMessage represents a starboard message mapping from the database
@@ -326,7 +331,7 @@ func (db *DB) GetQuestionByMessageID(messageID string) (*Question, error) {
 // ApproveQuestion updates the approval status for a question
 func (db *DB) ApproveQuestion(questionID int, approverID string) error {
 	log.Printf("Approving question ID %d by approver %s", questionID, approverID)
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'approved', approved_by = ?, approved_at = NOW() WHERE id = ?", db.tableName)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'approved', approved_by = ?, approved_at = CURRENT_TIMESTAMP WHERE id = ?", db.tableName)
 	_, err := db.conn.Exec(query, approverID, questionID)
 	if err != nil {
 		log.Printf("Failed to approve question ID %d: %v", questionID, err)
@@ -339,7 +344,7 @@ func (db *DB) ApproveQuestion(questionID int, approverID string) error {
 // RejectQuestion updates the approval status for a question to rejected
 func (db *DB) RejectQuestion(questionID int, rejectorID string) error {
 	log.Printf("Rejecting question ID %d by rejector %s", questionID, rejectorID)
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'rejected', approved_by = ?, approved_at = NOW() WHERE id = ?", db.tableName)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'rejected', approved_by = ?, approved_at = CURRENT_TIMESTAMP WHERE id = ?", db.tableName)
 	_, err := db.conn.Exec(query, rejectorID, questionID)
 	if err != nil {
 		log.Printf("Failed to reject question ID %d: %v", questionID, err)
@@ -352,7 +357,7 @@ func (db *DB) RejectQuestion(questionID int, rejectorID string) error {
 // GetPendingQuestion retrieves the next pending question for approval
 func (db *DB) GetPendingQuestion() (*Question, error) {
 	log.Println("Retrieving next pending question")
-	query := fmt.Sprintf("SELECT id, question, author_id, author_name, created_at, times_asked, last_asked_at, message_id, channel_id, approval_status, approval_message_id, approved_by, approved_at FROM %s WHERE approval_status = 'pending' ORDER BY created_at ASC LIMIT 1", db.tableName)
+	query := fmt.Sprintf("SELECT id, question, author_id, author_name, created_at, times_asked, last_asked_at, message_id, channel_id, approval_status, approval_message_id, approved_by, approved_at FROM %s WHERE approval_status = 'pending' ORDER BY created_at ASC, id ASC LIMIT 1", db.tableName)
 	var q Question
 	err := db.conn.QueryRow(query).Scan(&q.ID, &q.Question, &q.AuthorID, &q.AuthorName, &q.CreatedAt, &q.TimesAsked, &q.LastAskedAt, &q.MessageID, &q.ChannelID, &q.ApprovalStatus, &q.ApprovalMessageID, &q.ApprovedBy, &q.ApprovedAt)
 	if err != nil {
@@ -454,7 +459,7 @@ func (db *DB) GetApprovalStats() (int, int, int, error) {
 // GetLeastAskedApprovedQuestion gets the least asked approved question for equal distribution
 func (db *DB) GetLeastAskedApprovedQuestion() (*Question, error) {
 	log.Println("Retrieving least asked approved question")
-	query := fmt.Sprintf("SELECT id, question, author_id, author_name, created_at, times_asked, last_asked_at, message_id, channel_id, approval_status, approval_message_id, approved_by, approved_at FROM %s WHERE approval_status = 'approved' ORDER BY times_asked ASC, created_at ASC LIMIT 1", db.tableName)
+	query := fmt.Sprintf("SELECT id, question, author_id, author_name, created_at, times_asked, last_asked_at, message_id, channel_id, approval_status, approval_message_id, approved_by, approved_at FROM %s WHERE approval_status = 'approved' ORDER BY times_asked ASC, created_at ASC, id ASC LIMIT 1", db.tableName)
 	var q Question
 	err := db.conn.QueryRow(query).Scan(
 		&q.ID, &q.Question, &q.AuthorID, &q.AuthorName, &q.CreatedAt, &q.TimesAsked, &q.LastAskedAt,
@@ -476,7 +481,7 @@ func (db *DB) GetLeastAskedApprovedQuestion() (*Question, error) {
 // IncrementQuestionUsage increments the times_asked count and updates last_asked_at for a question
 func (db *DB) IncrementQuestionUsage(questionID int) error {
 	log.Printf("[DATABASE] Incrementing usage count for question ID %d", questionID)
-	query := fmt.Sprintf("UPDATE %s SET times_asked = times_asked + 1, last_asked_at = NOW() WHERE id = ?", db.tableName)
+	query := fmt.Sprintf("UPDATE %s SET times_asked = times_asked + 1, last_asked_at = CURRENT_TIMESTAMP WHERE id = ?", db.tableName)
 	_, err := db.conn.Exec(query, questionID)
 	if err != nil {
 		log.Printf("[DATABASE] Failed to increment usage count for question ID %d: %v", questionID, err)
@@ -486,6 +491,23 @@ func (db *DB) IncrementQuestionUsage(questionID int) error {
 	return nil
 }
 
+// UpdateQuestionText replaces the text of a question that is still pending
+func (db *DB) UpdateQuestionText(questionID int, question string) error {
+	log.Printf("Updating text of question ID %d", questionID)
+	query := fmt.Sprintf("UPDATE %s SET question = ? WHERE id = ? AND approval_status = 'pending'", db.tableName)
+	result, err := db.conn.Exec(query, question, questionID)
+	if err != nil {
+		log.Printf("Failed to update text of question ID %d: %v", questionID, err)
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("no pending question found for editing")
+	}
+	return nil
+}
+
 // GetApprovedQuestionStats returns stats about approved questions usage
 func (db *DB) GetApprovedQuestionStats() (int, int, int, error) {
 	var totalApproved, totalAsked, minAsked int
@@ -522,7 +544,7 @@ func (db *DB) Close() error {
 // AddBannedWord adds a new banned word to the database
 func (db *DB) AddBannedWord(word, reason, authorID string) error {
 	log.Printf("Adding banned word: %s by %s", word, authorID)
-	query := fmt.Sprintf("INSERT INTO %s (word, reason, author_id) VALUES (?, ?, ?)", db.bannedWordsTable)
+	query := fmt.Sprintf("INSERT INTO %s (word, reason, author_id, author_name) VALUES (?, ?, ?, '')", db.bannedWordsTable)
 	_, err := db.conn.Exec(query, word, reason, authorID)
 	if err != nil {
 		log.Printf("Failed to add banned word: %v", err)
@@ -566,13 +588,8 @@ func (db *DB) UpdateBannedWordApprovalMessageID(wordID int, approvalMessageID st
 // GetBannedWordByApprovalMessageID gets a banned word by its approval message ID
 func (db *DB) GetBannedWordByApprovalMessageID(approvalMessageID string) (*BannedWord, error) {
 	log.Printf("Looking up banned word by approval message ID: %s", approvalMessageID)
-	query := fmt.Sprintf("SELECT id, word, reason, author_id, author_name, forum_thread_id, approval_status, approval_message_id, opplysar_approved_by, opplysar_approved_at, rettskrivar_approved_by, rettskrivar_approved_at, created_at, original_message_id FROM %s WHERE approval_message_id = ?", db.bannedWordsTable)
-	var bw BannedWord
-	err := db.conn.QueryRow(query, approvalMessageID).Scan(
-		&bw.ID, &bw.Word, &bw.Reason, &bw.AuthorID, &bw.AuthorName, &bw.ForumThreadID,
-		&bw.ApprovalStatus, &bw.ApprovalMessageID, &bw.OpplysarApprovedBy, &bw.OpplysarApprovedAt,
-		&bw.RettskrivarApprovedBy, &bw.RettskrivarApprovedAt, &bw.CreatedAt, &bw.OriginalMessageID,
-	)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE approval_message_id = ?", bannedWordColumns, db.bannedWordsTable)
+	bw, err := scanBannedWord(db.conn.QueryRow(query, approvalMessageID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("No banned word found for approval message ID: %s", approvalMessageID)
@@ -582,13 +599,29 @@ func (db *DB) GetBannedWordByApprovalMessageID(approvalMessageID string) (*Banne
 		return nil, err
 	}
 	log.Printf("Found banned word ID %d for approval message %s", bw.ID, approvalMessageID)
-	return &bw, nil
+	return bw, nil
+}
+
+// UpdateBannedWordImportMessageID records the summary post of the import that added a banned word
+func (db *DB) UpdateBannedWordImportMessageID(wordID int, importMessageID string) error {
+	query := fmt.Sprintf("UPDATE %s SET import_message_id = ? WHERE id = ?", db.bannedWordsTable)
+	_, err := db.conn.Exec(query, importMessageID, wordID)
+	if err != nil {
+		log.Printf("Failed to update import message ID for banned word %d: %v", wordID, err)
+	}
+	return err
+}
+
+// GetBannedWordsByImportMessageID returns the banned words added by one import, alphabetically
+func (db *DB) GetBannedWordsByImportMessageID(importMessageID string) ([]*BannedWord, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE import_message_id = ? ORDER BY word ASC", bannedWordColumns, db.bannedWordsTable)
+	return db.queryBannedWords(query, importMessageID)
 }
 
 // ApproveBannedWordByOpplysar approves a banned word by opplysar
 func (db *DB) ApproveBannedWordByOpplysar(wordID int, approverID string) error {
 	log.Printf("Opplysar approving banned word ID %d by %s", wordID, approverID)
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'opplysar_approved', opplysar_approved_by = ?, opplysar_approved_at = NOW() WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'opplysar_approved', opplysar_approved_by = ?, opplysar_approved_at = CURRENT_TIMESTAMP WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
 	result, err := db.conn.Exec(query, approverID, wordID)
 	if err != nil {
 		log.Printf("Failed to approve banned word by opplysar ID %d: %v", wordID, err)
@@ -609,7 +642,7 @@ func (db *DB) ApproveBannedWordByOpplysar(wordID int, approverID string) error {
 // ApproveBannedWordByRettskrivar approves a banned word by rettskrivar
 func (db *DB) ApproveBannedWordByRettskrivar(wordID int, approverID string) error {
 	log.Printf("Rettskrivar approving banned word ID %d by %s", wordID, approverID)
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'fully_approved', rettskrivar_approved_by = ?, rettskrivar_approved_at = NOW() WHERE id = ? AND approval_status = 'opplysar_approved'", db.bannedWordsTable)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'fully_approved', rettskrivar_approved_by = ?, rettskrivar_approved_at = CURRENT_TIMESTAMP WHERE id = ? AND approval_status = 'opplysar_approved'", db.bannedWordsTable)
 	result, err := db.conn.Exec(query, approverID, wordID)
 	if err != nil {
 		log.Printf("Failed to approve banned word by rettskrivar ID %d: %v", wordID, err)
@@ -635,7 +668,7 @@ func (db *DB) ApproveBannedWordCombined(wordID int, opplysarApprovers, rettskriv
 	opplysarList := strings.Join(opplysarApprovers, ",")
 	rettskrivarList := strings.Join(rettskrivarApprovers, ",")
 
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'fully_approved', opplysar_approved_by = ?, rettskrivar_approved_by = ?, opplysar_approved_at = NOW(), rettskrivar_approved_at = NOW() WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'fully_approved', opplysar_approved_by = ?, rettskrivar_approved_by = ?, opplysar_approved_at = CURRENT_TIMESTAMP, rettskrivar_approved_at = CURRENT_TIMESTAMP WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
 	result, err := db.conn.Exec(query, opplysarList, rettskrivarList, wordID)
 	if err != nil {
 		log.Printf("Failed to approve banned word with combined approval ID %d: %v", wordID, err)
@@ -667,10 +700,10 @@ func (db *DB) UpdateBannedWordForumThreadID(wordID int, forumThreadID string) er
 }
 
 // RejectBannedWord updates the approval status for a banned word to rejected
-func (db *DB) RejectBannedWord(wordID int, rejectorID string) error {
+func (db *DB) RejectBannedWord(wordID int, rejectorID, reason string) error {
 	log.Printf("Rejecting banned word ID %d by rejector %s", wordID, rejectorID)
-	query := fmt.Sprintf("UPDATE %s SET approval_status = 'rejected', opplysar_approved_by = ?, opplysar_approved_at = NOW() WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
-	result, err := db.conn.Exec(query, rejectorID, wordID)
+	query := fmt.Sprintf("UPDATE %s SET approval_status = 'rejected', opplysar_approved_by = ?, opplysar_approved_at = CURRENT_TIMESTAMP, rejection_reason = ? WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
+	result, err := db.conn.Exec(query, rejectorID, reason, wordID)
 	if err != nil {
 		log.Printf("Failed to reject banned word ID %d: %v", wordID, err)
 		return err
@@ -690,12 +723,8 @@ func (db *DB) RejectBannedWord(wordID int, rejectorID string) error {
 // GetPendingBannedWord retrieves the next pending banned word for approval
 func (db *DB) GetPendingBannedWord() (*BannedWord, error) {
 	log.Println("Retrieving next pending banned word")
-	query := fmt.Sprintf("SELECT id, word, reason, author_id, author_name, forum_thread_id, approval_status, approval_message_id, opplysar_approved_by, opplysar_approved_at, rettskrivar_approved_by, rettskrivar_approved_at, created_at FROM %s WHERE approval_status = 'pending' ORDER BY created_at ASC LIMIT 1", db.bannedWordsTable)
-	var bw BannedWord
-	err := db.conn.QueryRow(query).Scan(&bw.ID, &bw.Word, &bw.Reason, &bw.AuthorID, &bw.AuthorName, &bw.ForumThreadID,
-		&bw.ApprovalStatus, &bw.ApprovalMessageID, &bw.OpplysarApprovedBy, &bw.OpplysarApprovedAt,
-		&bw.RettskrivarApprovedBy, &bw.RettskrivarApprovedAt, &bw.CreatedAt,
-	)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE approval_status = 'pending' ORDER BY created_at ASC, id ASC LIMIT 1", bannedWordColumns, db.bannedWordsTable)
+	bw, err := scanBannedWord(db.conn.QueryRow(query))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Println("No pending banned words found")
@@ -705,19 +734,14 @@ func (db *DB) GetPendingBannedWord() (*BannedWord, error) {
 		return nil, err
 	}
 	log.Printf("Retrieved pending banned word ID %d: %s", bw.ID, bw.Word)
-	return &bw, nil
+	return bw, nil
 }
 
 // GetBannedWordByID gets a banned word by its ID
 func (db *DB) GetBannedWordByID(wordID int) (*BannedWord, error) {
 	log.Printf("Looking up banned word by ID: %d", wordID)
-	query := fmt.Sprintf("SELECT id, word, reason, author_id, author_name, forum_thread_id, approval_status, approval_message_id, opplysar_approved_by, opplysar_approved_at, rettskrivar_approved_by, rettskrivar_approved_at, created_at FROM %s WHERE id = ?", db.bannedWordsTable)
-	var bw BannedWord
-	err := db.conn.QueryRow(query, wordID).Scan(
-		&bw.ID, &bw.Word, &bw.Reason, &bw.AuthorID, &bw.AuthorName, &bw.ForumThreadID,
-		&bw.ApprovalStatus, &bw.ApprovalMessageID, &bw.OpplysarApprovedBy, &bw.OpplysarApprovedAt,
-		&bw.RettskrivarApprovedBy, &bw.RettskrivarApprovedAt, &bw.CreatedAt,
-	)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", bannedWordColumns, db.bannedWordsTable)
+	bw, err := scanBannedWord(db.conn.QueryRow(query, wordID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Printf("No banned word found with ID: %d", wordID)
@@ -727,7 +751,7 @@ func (db *DB) GetBannedWordByID(wordID int) (*BannedWord, error) {
 		return nil, err
 	}
 	log.Printf("Found banned word ID %d: %s", bw.ID, bw.Word)
-	return &bw, nil
+	return bw, nil
 }
 
 // GetBannedWordApprovalStats returns statistics about banned word approvals
@@ -802,25 +826,92 @@ func (db *DB) RemoveBannedWord(word string) error {
 	return nil
 }
 
+// UpdateBannedWordText replaces a banned word that is still pending and drops the
+// approvals given so far, since they were given to the old text
+func (db *DB) UpdateBannedWordText(wordID int, word string) error {
+	log.Printf("Updating text of banned word ID %d to %s", wordID, word)
+	tx, err := db.conn.Begin()
+	if err != nil {
+		log.Printf("Failed to begin update of banned word ID %d: %v", wordID, err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("UPDATE %s SET word = ? WHERE id = ? AND approval_status = 'pending'", db.bannedWordsTable)
+	result, err := tx.Exec(query, word, wordID)
+	if err != nil {
+		log.Printf("Failed to update text of banned word ID %d: %v", wordID, err)
+		return err
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("no pending banned word found for editing")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE entity_type = ? AND entity_id = ?", db.approvalsTable)
+	if _, err := tx.Exec(query, ApprovalEntityBannedWord, wordID); err != nil {
+		log.Printf("Failed to clear approvals of banned word ID %d: %v", wordID, err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateBannedWordForms replaces the explicitly listed inflected forms of a banned word
+func (db *DB) UpdateBannedWordForms(wordID int, forms []string) error {
+	log.Printf("Updating forms of banned word ID %d to %v", wordID, forms)
+	query := fmt.Sprintf("UPDATE %s SET forms = ? WHERE id = ?", db.bannedWordsTable)
+	if _, err := db.conn.Exec(query, strings.Join(forms, "\n"), wordID); err != nil {
+		log.Printf("Failed to update forms of banned word ID %d: %v", wordID, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateBannedWordReplacements replaces the suggested correct forms of a banned word
+func (db *DB) UpdateBannedWordReplacements(wordID int, replacements []string) error {
+	log.Printf("Updating replacements of banned word ID %d to %v", wordID, replacements)
+	query := fmt.Sprintf("UPDATE %s SET replacements = ? WHERE id = ?", db.bannedWordsTable)
+	result, err := db.conn.Exec(query, strings.Join(replacements, "\n"), wordID)
+	if err != nil {
+		log.Printf("Failed to update replacements of banned word ID %d: %v", wordID, err)
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		if _, err := db.GetBannedWordByID(wordID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitLines splits a stored newline-separated column, dropping empty lines
+func splitLines(s string) []string {
+	var forms []string
+	for _, form := range strings.Split(s, "\n") {
+		if form = strings.TrimSpace(form); form != "" {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
 // IsBannedWord checks if a word is banned
 func (db *DB) IsBannedWord(word string) (bool, *BannedWord, error) {
-	query := fmt.Sprintf("SELECT id, word, reason, author_id, forum_thread_id, created_at FROM %s WHERE word = ?", db.bannedWordsTable)
-	var bw BannedWord
-	err := db.conn.QueryRow(query, word).Scan(
-		&bw.ID, &bw.Word, &bw.Reason, &bw.AuthorID, &bw.ForumThreadID, &bw.CreatedAt,
-	)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE word = ?", bannedWordColumns, db.bannedWordsTable)
+	bw, err := scanBannedWord(db.conn.QueryRow(query, word))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil, nil
 		}
 		return false, nil, err
 	}
-	return true, &bw, nil
+	return true, bw, nil
 }
 
 // GetBannedWords returns all banned words
 func (db *DB) GetBannedWords() ([]*BannedWord, error) {
-	query := fmt.Sprintf("SELECT id, word, reason, author_id, forum_thread_id, created_at FROM %s ORDER BY created_at DESC", db.bannedWordsTable)
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY created_at DESC, id DESC", bannedWordColumns, db.bannedWordsTable)
 	rows, err := db.conn.Query(query)
 	if err != nil {
 		return nil, err
@@ -829,14 +920,57 @@ func (db *DB) GetBannedWords() ([]*BannedWord, error) {
 
 	var words []*BannedWord
 	for rows.Next() {
-		var bw BannedWord
-		err := rows.Scan(&bw.ID, &bw.Word, &bw.Reason, &bw.AuthorID, &bw.ForumThreadID, &bw.CreatedAt)
+		bw, err := scanBannedWord(rows)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, bw)
+	}
+	return words, rows.Err()
+}
+
+// ListBannedWords returns a page of banned words with an approval status, in alphabetical order
+func (db *DB) ListBannedWords(status string, offset, limit int) ([]*BannedWord, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE approval_status = ? ORDER BY word ASC LIMIT ? OFFSET ?", bannedWordColumns, db.bannedWordsTable)
+	return db.queryBannedWords(query, status, limit, offset)
+}
+
+// CountBannedWords returns how many banned words have an approval status
+func (db *DB) CountBannedWords(status string) (int, error) {
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE approval_status = ?", db.bannedWordsTable)
+	err := db.conn.QueryRow(query, status).Scan(&count)
+	return count, err
+}
+
+// SearchBannedWords returns up to limit banned words in any status that contain text, in alphabetical order
+func (db *DB) SearchBannedWords(text string, limit int) ([]*BannedWord, error) {
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(text)) + "%"
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE LOWER(word) LIKE ? ESCAPE '!' ORDER BY word ASC LIMIT ?", bannedWordColumns, db.bannedWordsTable)
+	return db.queryBannedWords(query, pattern, limit)
+}
+
+// likeEscaper escapes LIKE wildcards with the ESCAPE character used by SearchBannedWords
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// queryBannedWords runs a query selecting bannedWordColumns
+func (db *DB) queryBannedWords(query string, args ...any) ([]*BannedWord, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		log.Printf("Failed to query banned words: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []*BannedWord
+	for rows.Next() {
+		bw, err := scanBannedWord(rows)
 		if err != nil {
 			return nil, err
 		}
-		words = append(words, &bw)
+		words = append(words, bw)
 	}
-	return words, nil
+	return words, rows.Err()
 }
 
 // AddStarboardMessage adds a new starboard message mapping to the database
@@ -892,10 +1026,11 @@ func (db *DB) RemoveStarboardMessage(originalMessageID string) error {
 	return nil
 }
 
-// ClearDatabase drops all tables from the database
+// ClearDatabase deletes all questions from the database.
+// Rows are deleted rather than the table dropped, since the schema is owned by migrations.
 func (db *DB) ClearDatabase() error {
 	log.Println("Clearing the database")
-	query := fmt.Sprintf("DROP TABLE IF EXISTS %s", db.tableName)
+	query := fmt.Sprintf("DELETE FROM %s", db.tableName)
 	_, err := db.conn.Exec(query)
 	if err != nil {
 		log.Printf("Failed to clear the database: %v", err)
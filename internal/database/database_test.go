@@ -0,0 +1,663 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"askeladden/internal/config"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// backends returns every DatabaseIface implementation that can run without a server
+func backends(t *testing.T) map[string]DatabaseIface {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	cfg.TableSuffix = "_testing"
+
+	sqliteDB, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to open sqlite database: %v", err)
+	}
+	t.Cleanup(func() { sqliteDB.Close() })
+
+	return map[string]DatabaseIface{
+		"sqlite": sqliteDB,
+		"memory": NewMemory(),
+	}
+}
+
+func TestQuestionLifecycle(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			firstID, err := db.AddQuestion("Kva et du til frukost?", "u1", "ola", "m1", "c1")
+			if err != nil {
+				t.Fatalf("AddQuestion: %v", err)
+			}
+			secondID, err := db.AddQuestion("Kva les du no?", "u2", "kari", "m2", "c1")
+			if err != nil {
+				t.Fatalf("AddQuestion: %v", err)
+			}
+
+			pending, err := db.GetPendingQuestion()
+			if err != nil || pending == nil || pending.ID != int(firstID) {
+				t.Fatalf("GetPendingQuestion = %+v, %v; want ID %d", pending, err, firstID)
+			}
+
+			if err := db.UpdateApprovalMessageID(int(firstID), "approval-1"); err != nil {
+				t.Fatalf("UpdateApprovalMessageID: %v", err)
+			}
+			byApproval, err := db.GetQuestionByApprovalMessageID("approval-1")
+			if err != nil || byApproval.ID != int(firstID) {
+				t.Fatalf("GetQuestionByApprovalMessageID = %+v, %v", byApproval, err)
+			}
+			if _, err := db.GetQuestionByApprovalMessageID("missing"); !errors.Is(err, sql.ErrNoRows) {
+				t.Fatalf("GetQuestionByApprovalMessageID(missing) error = %v, want sql.ErrNoRows", err)
+			}
+
+			if err := db.ApproveQuestion(int(firstID), "mod"); err != nil {
+				t.Fatalf("ApproveQuestion: %v", err)
+			}
+			if err := db.ApproveQuestion(int(secondID), "mod"); err != nil {
+				t.Fatalf("ApproveQuestion: %v", err)
+			}
+			if _, err := db.GetPendingQuestionByID(int(firstID)); !errors.Is(err, sql.ErrNoRows) {
+				t.Fatalf("GetPendingQuestionByID(approved) error = %v, want sql.ErrNoRows", err)
+			}
+
+			if err := db.IncrementQuestionUsage(int(firstID)); err != nil {
+				t.Fatalf("IncrementQuestionUsage: %v", err)
+			}
+			least, err := db.GetLeastAskedApprovedQuestion()
+			if err != nil || least == nil || least.ID != int(secondID) {
+				t.Fatalf("GetLeastAskedApprovedQuestion = %+v, %v; want ID %d", least, err, secondID)
+			}
+
+			total, asked, minAsked, err := db.GetApprovedQuestionStats()
+			if err != nil || total != 2 || asked != 1 || minAsked != 0 {
+				t.Fatalf("GetApprovedQuestionStats = %d, %d, %d, %v; want 2, 1, 0", total, asked, minAsked, err)
+			}
+
+			if err := db.ClearDatabase(); err != nil {
+				t.Fatalf("ClearDatabase: %v", err)
+			}
+			pendingCount, approvedCount, rejectedCount, err := db.GetApprovalStats()
+			if err != nil || pendingCount+approvedCount+rejectedCount != 0 {
+				t.Fatalf("GetApprovalStats after clear = %d, %d, %d, %v", pendingCount, approvedCount, rejectedCount, err)
+			}
+		})
+	}
+}
+
+func TestBannedWordLifecycle(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			id, err := db.AddBannedWordPending("ikke", "Reported via hammer emoji", "u1", "ola", "", "c1|m1")
+			if err != nil {
+				t.Fatalf("AddBannedWordPending: %v", err)
+			}
+			if _, err := db.AddBannedWordPending("IKKE", "", "u2", "kari", "", ""); err == nil {
+				t.Fatalf("AddBannedWordPending accepted a duplicate word")
+			}
+
+			if err := db.ApproveBannedWordByRettskrivar(int(id), "r1"); err == nil {
+				t.Fatalf("ApproveBannedWordByRettskrivar accepted a word without opplysar approval")
+			}
+			if err := db.ApproveBannedWordCombined(int(id), []string{"o1"}, []string{"r1", "r2"}); err != nil {
+				t.Fatalf("ApproveBannedWordCombined: %v", err)
+			}
+			if err := db.RejectBannedWord(int(id), "o1", ""); err == nil {
+				t.Fatalf("RejectBannedWord accepted an approved word")
+			}
+			if err := db.UpdateBannedWordForumThreadID(int(id), "thread-1"); err != nil {
+				t.Fatalf("UpdateBannedWordForumThreadID: %v", err)
+			}
+
+			banned, bw, err := db.IsBannedWord("Ikke")
+			if err != nil || !banned {
+				t.Fatalf("IsBannedWord = %v, %v", banned, err)
+			}
+			if bw.ApprovalStatus != "fully_approved" || bw.ForumThreadID == nil || *bw.ForumThreadID != "thread-1" {
+				t.Fatalf("IsBannedWord returned %+v", bw)
+			}
+			if bw.RettskrivarApprovedBy == nil || *bw.RettskrivarApprovedBy != "r1,r2" {
+				t.Fatalf("RettskrivarApprovedBy = %v, want r1,r2", bw.RettskrivarApprovedBy)
+			}
+			if bw.OriginalMessageID == nil || *bw.OriginalMessageID != "c1|m1" {
+				t.Fatalf("OriginalMessageID = %v, want c1|m1", bw.OriginalMessageID)
+			}
+
+			words, err := db.GetBannedWords()
+			if err != nil || len(words) != 1 {
+				t.Fatalf("GetBannedWords = %v, %v", words, err)
+			}
+
+			if err := db.RemoveBannedWord("ikke"); err != nil {
+				t.Fatalf("RemoveBannedWord: %v", err)
+			}
+			if banned, _, _ := db.IsBannedWord("ikke"); banned {
+				t.Fatalf("word still banned after RemoveBannedWord")
+			}
+		})
+	}
+}
+
+func TestApprovalsAndEdits(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			wordID, err := db.AddBannedWordPending("ikkje", "", "u1", "ola", "", "")
+			if err != nil {
+				t.Fatalf("AddBannedWordPending: %v", err)
+			}
+			if _, err := db.AddBannedWordPending("noe", "", "u1", "ola", "", ""); err != nil {
+				t.Fatalf("AddBannedWordPending: %v", err)
+			}
+			if err := db.UpdateBannedWordText(int(wordID), "ikke"); err != nil {
+				t.Fatalf("UpdateBannedWordText: %v", err)
+			}
+			if err := db.UpdateBannedWordText(int(wordID), "NOE"); err == nil {
+				t.Fatalf("UpdateBannedWordText accepted a duplicate word")
+			}
+			if bw, _ := db.GetBannedWordByID(int(wordID)); bw == nil || bw.Word != "ikke" {
+				t.Fatalf("GetBannedWordByID after edit = %+v", bw)
+			}
+			if err := db.UpdateBannedWordForms(int(wordID), []string{"ikkes", " ", "ikkene"}); err != nil {
+				t.Fatalf("UpdateBannedWordForms: %v", err)
+			}
+			if bw, _ := db.GetBannedWordByID(int(wordID)); bw == nil || fmt.Sprint(bw.Forms) != "[ikkes ikkene]" {
+				t.Fatalf("forms after update = %+v", bw)
+			}
+			if err := db.UpdateBannedWordReplacements(int(wordID), []string{"ikkje", "ikkje-"}); err != nil {
+				t.Fatalf("UpdateBannedWordReplacements: %v", err)
+			}
+			if bw, _ := db.GetBannedWordByID(int(wordID)); bw == nil || fmt.Sprint(bw.Replacements) != "[ikkje ikkje-]" {
+				t.Fatalf("replacements after update = %+v", bw)
+			}
+			if err := db.UpdateBannedWordReplacements(9999, []string{"ikkje"}); !errors.Is(err, sql.ErrNoRows) {
+				t.Fatalf("UpdateBannedWordReplacements(missing) error = %v, want sql.ErrNoRows", err)
+			}
+
+			for _, role := range []string{ApprovalRoleOpplysar, ApprovalRoleRettskrivar, ApprovalRoleOpplysar} {
+				if err := db.AddApproval(ApprovalEntityBannedWord, int(wordID), "o1", role); err != nil {
+					t.Fatalf("AddApproval: %v", err)
+				}
+			}
+			if err := db.AddApproval(ApprovalEntityQuestion, int(wordID), "o1", ApprovalRoleOpplysar); err != nil {
+				t.Fatalf("AddApproval: %v", err)
+			}
+			approvals, err := db.GetApprovals(ApprovalEntityBannedWord, int(wordID))
+			if err != nil || len(approvals) != 2 {
+				t.Fatalf("GetApprovals = %d approvals, %v; want 2", len(approvals), err)
+			}
+			if approvals[0].Role != ApprovalRoleOpplysar || approvals[1].Role != ApprovalRoleRettskrivar || approvals[1].UserID != "o1" {
+				t.Fatalf("GetApprovals returned %+v, %+v", approvals[0], approvals[1])
+			}
+
+			// Editing the text drops the approvals given to the old text, and only those
+			if err := db.UpdateBannedWordText(int(wordID), "ikkje"); err != nil {
+				t.Fatalf("UpdateBannedWordText: %v", err)
+			}
+			if approvals, _ := db.GetApprovals(ApprovalEntityBannedWord, int(wordID)); len(approvals) != 0 {
+				t.Fatalf("approvals after edit = %+v, want none", approvals)
+			}
+			if approvals, _ := db.GetApprovals(ApprovalEntityQuestion, int(wordID)); len(approvals) != 1 {
+				t.Fatalf("question approvals after editing a word = %d, want 1", len(approvals))
+			}
+
+			questionID, err := db.AddQuestion("Kva et du?", "u1", "ola", "m1", "c1")
+			if err != nil {
+				t.Fatalf("AddQuestion: %v", err)
+			}
+			if err := db.UpdateQuestionText(int(questionID), "Kva et du til middag?"); err != nil {
+				t.Fatalf("UpdateQuestionText: %v", err)
+			}
+			if err := db.ApproveQuestion(int(questionID), "o1"); err != nil {
+				t.Fatalf("ApproveQuestion: %v", err)
+			}
+			if err := db.UpdateQuestionText(int(questionID), "Kva et du no?"); err == nil {
+				t.Fatalf("UpdateQuestionText edited an approved question")
+			}
+		})
+	}
+}
+
+func TestStarboard(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if id, err := db.GetStarboardMessage("orig"); err != nil || id != "" {
+				t.Fatalf("GetStarboardMessage(unknown) = %q, %v", id, err)
+			}
+			if err := db.AddStarboardMessage("orig", "star", "c1"); err != nil {
+				t.Fatalf("AddStarboardMessage: %v", err)
+			}
+			if err := db.UpdateStarboardMessage("orig", "star2"); err != nil {
+				t.Fatalf("UpdateStarboardMessage: %v", err)
+			}
+			if id, err := db.GetStarboardMessage("orig"); err != nil || id != "star2" {
+				t.Fatalf("GetStarboardMessage = %q, %v; want star2", id, err)
+			}
+			if err := db.RemoveStarboardMessage("orig"); err != nil {
+				t.Fatalf("RemoveStarboardMessage: %v", err)
+			}
+			if id, _ := db.GetStarboardMessage("orig"); id != "" {
+				t.Fatalf("starboard mapping still present after removal")
+			}
+		})
+	}
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "migrate.db")
+
+	db, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.MigrateDown(len(db.migrations)); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	statuses, err := db.MigrationStatus()
+	if err != nil {
+		t.Fatalf("MigrationStatus: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			t.Fatalf("migration %d still applied after full rollback", status.Version)
+		}
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if _, err := db.AddQuestion("q", "u", "n", "m", "c"); err != nil {
+		t.Fatalf("AddQuestion after re-migrating: %v", err)
+	}
+}
+
+func TestMigrationsMatchAcrossDrivers(t *testing.T) {
+	mysqlMigrations, err := loadMigrations(DriverMySQL, "")
+	if err != nil {
+		t.Fatalf("loadMigrations(mysql): %v", err)
+	}
+	sqliteMigrations, err := loadMigrations(DriverSQLite, "")
+	if err != nil {
+		t.Fatalf("loadMigrations(sqlite): %v", err)
+	}
+	if len(mysqlMigrations) != len(sqliteMigrations) {
+		t.Fatalf("mysql has %d migrations, sqlite has %d", len(mysqlMigrations), len(sqliteMigrations))
+	}
+	for i := range mysqlMigrations {
+		if mysqlMigrations[i].Version != sqliteMigrations[i].Version || mysqlMigrations[i].Name != sqliteMigrations[i].Name {
+			t.Errorf("migration %d differs: mysql %04d_%s, sqlite %04d_%s", i,
+				mysqlMigrations[i].Version, mysqlMigrations[i].Name, sqliteMigrations[i].Version, sqliteMigrations[i].Name)
+		}
+		if mysqlMigrations[i].Down == "" || sqliteMigrations[i].Down == "" {
+			t.Errorf("migration %04d_%s is missing a down script", mysqlMigrations[i].Version, mysqlMigrations[i].Name)
+		}
+	}
+}
+
+func TestWarningMode(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if mode, err := db.GetWarningMode("u1"); err != nil || mode != WarningModeOn {
+				t.Fatalf("GetWarningMode for a new user = %q, %v; want %q", mode, err, WarningModeOn)
+			}
+			for _, mode := range []string{WarningModeDM, WarningModeOff} {
+				if err := db.SetWarningMode("u1", mode); err != nil {
+					t.Fatalf("SetWarningMode(%q): %v", mode, err)
+				}
+				if got, err := db.GetWarningMode("u1"); err != nil || got != mode {
+					t.Fatalf("GetWarningMode = %q, %v; want %q", got, err, mode)
+				}
+			}
+			if err := db.SetWarningMode("u1", "kanskje"); err == nil {
+				t.Fatalf("SetWarningMode accepted an unknown mode")
+			}
+			if mode, _ := db.GetWarningMode("u2"); mode != WarningModeOn {
+				t.Fatalf("another user's mode = %q", mode)
+			}
+
+			// Several first choices at once all succeed, as one row
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for range 8 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- db.SetWarningMode("u3", WarningModeDM)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("concurrent SetWarningMode: %v", err)
+				}
+			}
+			if mode, _ := db.GetWarningMode("u3"); mode != WarningModeDM {
+				t.Fatalf("mode after concurrent SetWarningMode = %q", mode)
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := db.GetWarning("m1"); err != sql.ErrNoRows {
+				t.Fatalf("GetWarning before any warning = %v, want sql.ErrNoRows", err)
+			}
+			if err := db.AddWarning("m1", "c1", "w1", "c1"); err != nil {
+				t.Fatalf("AddWarning: %v", err)
+			}
+			if err := db.AddWarning("m1", "c1", "w2", "dm-u1"); err != nil {
+				t.Fatalf("AddWarning again: %v", err)
+			}
+			w, err := db.GetWarning("m1")
+			if err != nil || w.ChannelID != "c1" || w.WarningMessageID != "w2" || w.WarningChannelID != "dm-u1" {
+				t.Fatalf("GetWarning = %+v, %v", w, err)
+			}
+			if err := db.RemoveWarning("m1"); err != nil {
+				t.Fatalf("RemoveWarning: %v", err)
+			}
+			if _, err := db.GetWarning("m1"); err != sql.ErrNoRows {
+				t.Fatalf("GetWarning after RemoveWarning = %v, want sql.ErrNoRows", err)
+			}
+		})
+	}
+}
+
+func TestBannedWordRemovals(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			wordID, err := db.AddBannedWordPending("ikke", "", "u1", "ola", "", "")
+			if err != nil {
+				t.Fatalf("AddBannedWordPending: %v", err)
+			}
+			if _, err := db.GetPendingBannedWordRemoval(int(wordID)); err != sql.ErrNoRows {
+				t.Fatalf("GetPendingBannedWordRemoval before any request = %v, want sql.ErrNoRows", err)
+			}
+
+			first, err := db.AddBannedWordRemoval(int(wordID), "ikke", "Godt nynorsk likevel", "u2")
+			if err != nil {
+				t.Fatalf("AddBannedWordRemoval: %v", err)
+			}
+			if err := db.UpdateBannedWordRemovalMessageID(int(first), "msg-1"); err != nil {
+				t.Fatalf("UpdateBannedWordRemovalMessageID: %v", err)
+			}
+			r, err := db.GetPendingBannedWordRemoval(int(wordID))
+			if err != nil || r.ID != int(first) || r.Reason != "Godt nynorsk likevel" || r.ApprovalMessageID == nil || *r.ApprovalMessageID != "msg-1" {
+				t.Fatalf("GetPendingBannedWordRemoval = %+v, %v", r, err)
+			}
+
+			if err := db.RejectBannedWordRemoval(int(first), "o1", "Nei"); err != nil {
+				t.Fatalf("RejectBannedWordRemoval: %v", err)
+			}
+			if err := db.ApproveBannedWordRemoval(int(first), []string{"o1"}, []string{"r1"}); err == nil {
+				t.Fatalf("ApproveBannedWordRemoval accepted a rejected request")
+			}
+			if _, err := db.GetPendingBannedWordRemoval(int(wordID)); err != sql.ErrNoRows {
+				t.Fatalf("rejected request still pending: %v", err)
+			}
+
+			second, _ := db.AddBannedWordRemoval(int(wordID), "ikke", "", "u2")
+			if err := db.ApproveBannedWordRemoval(int(second), []string{"o1"}, []string{"r1", "r2"}); err != nil {
+				t.Fatalf("ApproveBannedWordRemoval: %v", err)
+			}
+			r, err = db.GetBannedWordRemoval(int(second))
+			if err != nil || r.Status != RemovalStatusApproved || r.RettskrivarApprovedBy == nil || *r.RettskrivarApprovedBy != "r1,r2" || r.DecidedAt == nil {
+				t.Fatalf("GetBannedWordRemoval = %+v, %v", r, err)
+			}
+			if _, err := db.GetBannedWordByID(int(wordID)); err == nil {
+				t.Fatalf("approved removal kept the word")
+			}
+			if r, _ := db.GetBannedWordRemoval(int(first)); r.RejectedBy == nil || *r.RejectedBy != "o1" || r.RejectionReason != "Nei" {
+				t.Fatalf("rejected request = %+v", r)
+			}
+
+			rejectedID, _ := db.AddBannedWordPending("noe", "", "u1", "ola", "", "")
+			if err := db.RejectBannedWord(int(rejectedID), "o1", "Ikkje bokmål"); err != nil {
+				t.Fatalf("RejectBannedWord: %v", err)
+			}
+			if bw, err := db.GetBannedWordByID(int(rejectedID)); err != nil || bw.RejectionReason != "Ikkje bokmål" {
+				t.Fatalf("rejected word = %+v, %v", bw, err)
+			}
+		})
+	}
+}
+
+func TestApproveBannedWordRemovalRollsBack(t *testing.T) {
+	db := backends(t)["sqlite"].(*DB)
+	wordID, _ := db.AddBannedWordPending("ikke", "", "u1", "ola", "", "")
+	removalID, _ := db.AddBannedWordRemoval(int(wordID), "ikke", "", "u2")
+
+	// Make the delete fail after the request has been marked approved
+	trigger := fmt.Sprintf("CREATE TRIGGER keep_words BEFORE DELETE ON %s BEGIN SELECT RAISE(ABORT, 'kept'); END", db.bannedWordsTable)
+	if _, err := db.conn.Exec(trigger); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	if err := db.ApproveBannedWordRemoval(int(removalID), []string{"o1"}, []string{"r1"}); err == nil {
+		t.Fatalf("ApproveBannedWordRemoval succeeded without deleting the word")
+	}
+	if r, err := db.GetPendingBannedWordRemoval(int(wordID)); err != nil || r.ID != int(removalID) {
+		t.Fatalf("request after failed approval = %+v, %v; want it still pending", r, err)
+	}
+
+	if _, err := db.conn.Exec("DROP TRIGGER keep_words"); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if err := db.ApproveBannedWordRemoval(int(removalID), []string{"o1"}, []string{"r1"}); err != nil {
+		t.Fatalf("retrying ApproveBannedWordRemoval: %v", err)
+	}
+	if _, err := db.GetBannedWordByID(int(wordID)); err == nil {
+		t.Fatalf("word kept after the retried approval")
+	}
+}
+
+func TestListAndSearchBannedWords(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, word := range []string{"noe", "ikke", "hvordan", "ikke sant", "100%"} {
+				id, err := db.AddBannedWordPending(word, "", "u1", "ola", "", "")
+				if err != nil {
+					t.Fatalf("AddBannedWordPending(%q): %v", word, err)
+				}
+				if word != "noe" {
+					db.ApproveBannedWordCombined(int(id), []string{"o1"}, []string{"r1"})
+				}
+			}
+
+			words := func(list []*BannedWord) string {
+				var names []string
+				for _, bw := range list {
+					names = append(names, bw.Word)
+				}
+				return fmt.Sprint(names)
+			}
+
+			if n, err := db.CountBannedWords("fully_approved"); err != nil || n != 4 {
+				t.Fatalf("CountBannedWords = %d, %v; want 4", n, err)
+			}
+			page, err := db.ListBannedWords("fully_approved", 0, 3)
+			if err != nil || words(page) != "[100% hvordan ikke]" {
+				t.Fatalf("first page = %s, %v", words(page), err)
+			}
+			if page, _ := db.ListBannedWords("fully_approved", 3, 3); words(page) != "[ikke sant]" {
+				t.Fatalf("second page = %s", words(page))
+			}
+			if page, _ := db.ListBannedWords("fully_approved", 6, 3); len(page) != 0 {
+				t.Fatalf("page past the end = %s", words(page))
+			}
+			if page, _ := db.ListBannedWords("pending", 0, 10); words(page) != "[noe]" {
+				t.Fatalf("pending = %s", words(page))
+			}
+
+			tests := []struct{ text, want string }{
+				{"IKK", "[ikke ikke sant]"},
+				{"o", "[hvordan noe]"},
+				{"%", "[100%]"},
+				{"_", "[]"},
+			}
+			for _, tt := range tests {
+				if found, err := db.SearchBannedWords(tt.text, 10); err != nil || words(found) != tt.want {
+					t.Errorf("SearchBannedWords(%q) = %s, %v; want %s", tt.text, words(found), err, tt.want)
+				}
+			}
+			if found, _ := db.SearchBannedWords("", 2); len(found) != 2 {
+				t.Errorf("SearchBannedWords with limit 2 = %s", words(found))
+			}
+		})
+	}
+}
+
+func TestBannedWordImports(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, word := range []string{"noe", "ikke", "hvordan"} {
+				id, err := db.AddBannedWordPending(word, "", "u1", "ola", "", "")
+				if err != nil {
+					t.Fatalf("AddBannedWordPending(%q): %v", word, err)
+				}
+				if word != "hvordan" {
+					if err := db.UpdateBannedWordImportMessageID(int(id), "summary"); err != nil {
+						t.Fatalf("UpdateBannedWordImportMessageID: %v", err)
+					}
+				}
+			}
+
+			batch, err := db.GetBannedWordsByImportMessageID("summary")
+			if err != nil || len(batch) != 2 || batch[0].Word != "ikke" || batch[1].Word != "noe" {
+				t.Fatalf("GetBannedWordsByImportMessageID = %+v, %v; want ikke and noe", batch, err)
+			}
+			if batch[0].ImportMessageID == nil || *batch[0].ImportMessageID != "summary" || batch[0].ApprovalMessageID != nil {
+				t.Errorf("imported word message IDs = %v, %v", batch[0].ImportMessageID, batch[0].ApprovalMessageID)
+			}
+			if other, err := db.GetBannedWordsByImportMessageID("other"); err != nil || len(other) != 0 {
+				t.Errorf("unknown import = %+v, %v", other, err)
+			}
+		})
+	}
+}
+
+func TestSchedulerState(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			state, err := db.GetSchedulerState()
+			if err != nil || state.LastPostDay != "" || state.LastPostAt != nil || state.LastActivityAt != nil {
+				t.Fatalf("initial state = %+v, %v", state, err)
+			}
+
+			morning := time.Date(2024, 5, 17, 8, 0, 0, 0, time.UTC)
+			if claimed, err := db.ClaimDailyPost("2024-05-17", morning); err != nil || !claimed {
+				t.Fatalf("first claim = %v, %v", claimed, err)
+			}
+			if claimed, _ := db.ClaimDailyPost("2024-05-17", morning.Add(time.Hour)); claimed {
+				t.Fatalf("the same day was claimed twice")
+			}
+			if err := db.RecordDailyPost(7, "msg-1"); err != nil {
+				t.Fatalf("RecordDailyPost: %v", err)
+			}
+			db.RecordActivity(morning.Add(2 * time.Hour))
+			db.RecordActivity(morning.Add(time.Hour)) // older activity does not move it back
+
+			state, _ = db.GetSchedulerState()
+			if state.LastPostDay != "2024-05-17" || state.LastPostAt == nil || !state.LastPostAt.Equal(morning) ||
+				state.LastQuestionID == nil || *state.LastQuestionID != 7 || state.LastMessageID == nil || *state.LastMessageID != "msg-1" {
+				t.Fatalf("state after posting = %+v", state)
+			}
+			if state.LastActivityAt == nil || !state.LastActivityAt.Equal(morning.Add(2*time.Hour)) {
+				t.Fatalf("last activity = %v, want 10:00", state.LastActivityAt)
+			}
+
+			// A posted day cannot be released
+			if err := db.ReleaseDailyPost("2024-05-17"); err != nil {
+				t.Fatalf("ReleaseDailyPost: %v", err)
+			}
+			if state, _ = db.GetSchedulerState(); state.LastPostDay != "2024-05-17" {
+				t.Fatalf("posted day released: %+v", state)
+			}
+
+			// A new day can be claimed and starts without a question
+			if claimed, _ := db.ClaimDailyPost("2024-05-18", morning.Add(24*time.Hour)); !claimed {
+				t.Fatalf("next day not claimed")
+			}
+			if state, _ = db.GetSchedulerState(); state.LastQuestionID != nil || state.LastMessageID != nil {
+				t.Fatalf("state after claiming a new day = %+v", state)
+			}
+
+			// A claim that did not post is released and can be claimed again
+			if err := db.ReleaseDailyPost("2024-05-18"); err != nil {
+				t.Fatalf("ReleaseDailyPost: %v", err)
+			}
+			if state, _ = db.GetSchedulerState(); state.LastPostDay != "" || state.LastPostAt != nil {
+				t.Fatalf("state after releasing = %+v", state)
+			}
+			if claimed, _ := db.ClaimDailyPost("2024-05-18", morning.Add(25*time.Hour)); !claimed {
+				t.Fatalf("released day not claimed again")
+			}
+		})
+	}
+}
+
+func TestJobRuns(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			if runs, err := db.GetJobRuns(); err != nil || len(runs) != 0 {
+				t.Fatalf("initial runs = %v, %v", runs, err)
+			}
+
+			at := time.Date(2024, 5, 17, 8, 0, 0, 0, time.UTC)
+			if err := db.RecordJobRun("dagsspørsmål", at, "ingen spørsmål"); err != nil {
+				t.Fatalf("RecordJobRun: %v", err)
+			}
+			if err := db.RecordJobRun("dagsspørsmål", at.Add(time.Hour), ""); err != nil {
+				t.Fatalf("RecordJobRun again: %v", err)
+			}
+			db.RecordJobRun("rydding", at, "")
+
+			runs, err := db.GetJobRuns()
+			if err != nil || len(runs) != 2 {
+				t.Fatalf("runs = %v, %v", runs, err)
+			}
+			if run := runs["dagsspørsmål"]; !run.LastRunAt.Equal(at.Add(time.Hour)) || run.LastError != "" {
+				t.Errorf("last run = %+v", run)
+			}
+		})
+	}
+}
+
+func TestPruneWarnings(t *testing.T) {
+	for name, db := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			db.AddWarning("m1", "c1", "w1", "c1")
+			if pruned, err := db.PruneWarnings(time.Now().Add(-time.Hour)); err != nil || pruned != 0 {
+				t.Fatalf("pruned %d recent warnings, %v", pruned, err)
+			}
+			if pruned, err := db.PruneWarnings(time.Now().Add(time.Hour)); err != nil || pruned != 1 {
+				t.Fatalf("pruned %d old warnings, %v", pruned, err)
+			}
+			if _, err := db.GetWarning("m1"); err != sql.ErrNoRows {
+				t.Errorf("pruned warning still there: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// JobRun is the last run of a scheduled job
+type JobRun struct {
+	Name      string
+	LastRunAt time.Time
+	LastError string // "" if the run succeeded
+}
+
+// GetJobRuns returns the last run of every job that has run, by job name
+func (db *DB) GetJobRuns() (map[string]*JobRun, error) {
+	query := fmt.Sprintf("SELECT name, last_run_at, last_error FROM %s WHERE last_run_at IS NOT NULL", db.jobsTable)
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		log.Printf("Failed to get job runs: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make(map[string]*JobRun)
+	for rows.Next() {
+		var run JobRun
+		var lastError sql.NullString
+		if err := rows.Scan(&run.Name, &run.LastRunAt, &lastError); err != nil {
+			return nil, err
+		}
+		run.LastError = lastError.String
+		runs[run.Name] = &run
+	}
+	return runs, rows.Err()
+}
+
+// RecordJobRun stores when a job last ran and the error it ended with, "" for none
+func (db *DB) RecordJobRun(name string, at time.Time, runErr string) error {
+	var lastError any
+	if runErr != "" {
+		lastError = runErr
+	}
+	query := fmt.Sprintf("UPDATE %s SET last_run_at = ?, last_error = ? WHERE name = ?", db.jobsTable)
+	result, err := db.conn.Exec(query, at.UTC(), lastError, name)
+	if err == nil {
+		var rows int64
+		if rows, err = result.RowsAffected(); err == nil && rows == 0 {
+			query = fmt.Sprintf("INSERT INTO %s (name, last_run_at, last_error) VALUES (?, ?, ?)", db.jobsTable)
+			_, err = db.conn.Exec(query, name, at.UTC(), lastError)
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to record run of job %s: %v", name, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,991 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryDB is a thread-safe in-memory implementation of DatabaseIface.
+// It mirrors the semantics of DB (including sql.ErrNoRows for missing rows)
+// and is meant for tests and the --memory-db development mode. Nothing is persisted.
+type MemoryDB struct {
+	mu sync.RWMutex
+
+	questions    []*Question
+	bannedWords  []*BannedWord
+	starboard    map[string]*StarboardMessage
+	approvals    []*Approval
+	warningModes map[string]string
+	warnings     map[string]*Warning
+	removals     []*BannedWordRemoval
+	scheduler    SchedulerState
+	jobRuns      map[string]*JobRun
+	nextQuestion int
+	nextWord     int
+	nextStar     int
+	nextApproval int
+	nextRemoval  int
+
+	now func() time.Time
+}
+
+// MemoryDB implements the DatabaseIface
+var _ DatabaseIface = (*MemoryDB)(nil)
+
+// NewMemory creates an empty in-memory database
+func NewMemory() *MemoryDB {
+	log.Println("Using in-memory database (data is lost on exit)")
+	return &MemoryDB{
+		starboard:    make(map[string]*StarboardMessage),
+		warningModes: make(map[string]string),
+		warnings:     make(map[string]*Warning),
+		jobRuns:      make(map[string]*JobRun),
+		nextQuestion: 1,
+		nextWord:     1,
+		nextStar:     1,
+		nextApproval: 1,
+		nextRemoval:  1,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// copyQuestion returns a copy so callers can't modify stored rows
+func copyQuestion(q *Question) *Question {
+	c := *q
+	return &c
+}
+
+// copyBannedWord returns a copy so callers can't modify stored rows
+func copyBannedWord(bw *BannedWord) *BannedWord {
+	c := *bw
+	c.Forms = append([]string(nil), bw.Forms...)
+	c.Replacements = append([]string(nil), bw.Replacements...)
+	return &c
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// findQuestion returns the stored question matching pred, or nil
+func (m *MemoryDB) findQuestion(pred func(q *Question) bool) *Question {
+	for _, q := range m.questions {
+		if pred(q) {
+			return q
+		}
+	}
+	return nil
+}
+
+// findBannedWord returns the stored banned word matching pred, or nil
+func (m *MemoryDB) findBannedWord(pred func(bw *BannedWord) bool) *BannedWord {
+	for _, bw := range m.bannedWords {
+		if pred(bw) {
+			return bw
+		}
+	}
+	return nil
+}
+
+// AddQuestion adds a new question to the database
+func (m *MemoryDB) AddQuestion(question, authorID, authorName, messageID, channelID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := &Question{
+		ID:             m.nextQuestion,
+		Question:       question,
+		AuthorID:       authorID,
+		AuthorName:     authorName,
+		CreatedAt:      m.now(),
+		MessageID:      messageID,
+		ChannelID:      channelID,
+		ApprovalStatus: "pending",
+	}
+	m.nextQuestion++
+	m.questions = append(m.questions, q)
+	return int64(q.ID), nil
+}
+
+// GetQuestionByMessageID gets a question by its Discord message ID
+func (m *MemoryDB) GetQuestionByMessageID(messageID string) (*Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q := m.findQuestion(func(q *Question) bool { return q.MessageID == messageID })
+	if q == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyQuestion(q), nil
+}
+
+// setQuestionStatus updates the approval status of a question, ignoring unknown IDs like an UPDATE would
+func (m *MemoryDB) setQuestionStatus(questionID int, status, userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if q := m.findQuestion(func(q *Question) bool { return q.ID == questionID }); q != nil {
+		q.ApprovalStatus = status
+		q.ApprovedBy = stringPtr(userID)
+		q.ApprovedAt = timePtr(m.now())
+	}
+}
+
+// ApproveQuestion updates the approval status for a question
+func (m *MemoryDB) ApproveQuestion(questionID int, approverID string) error {
+	m.setQuestionStatus(questionID, "approved", approverID)
+	return nil
+}
+
+// RejectQuestion updates the approval status for a question to rejected
+func (m *MemoryDB) RejectQuestion(questionID int, rejectorID string) error {
+	m.setQuestionStatus(questionID, "rejected", rejectorID)
+	return nil
+}
+
+// GetPendingQuestion retrieves the next pending question for approval
+func (m *MemoryDB) GetPendingQuestion() (*Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var oldest *Question
+	for _, q := range m.questions {
+		if q.ApprovalStatus == "pending" && (oldest == nil || q.CreatedAt.Before(oldest.CreatedAt)) {
+			oldest = q
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+	return copyQuestion(oldest), nil
+}
+
+// UpdateApprovalMessageID updates the approval message ID for a question
+func (m *MemoryDB) UpdateApprovalMessageID(questionID int, approvalMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if q := m.findQuestion(func(q *Question) bool { return q.ID == questionID }); q != nil {
+		q.ApprovalMessageID = stringPtr(approvalMessageID)
+	}
+	return nil
+}
+
+// GetQuestionByApprovalMessageID gets a question by its approval message ID
+func (m *MemoryDB) GetQuestionByApprovalMessageID(approvalMessageID string) (*Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q := m.findQuestion(func(q *Question) bool {
+		return q.ApprovalMessageID != nil && *q.ApprovalMessageID == approvalMessageID
+	})
+	if q == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyQuestion(q), nil
+}
+
+// GetPendingQuestionByID gets a pending question by its question ID
+func (m *MemoryDB) GetPendingQuestionByID(questionID int) (*Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q := m.findQuestion(func(q *Question) bool { return q.ID == questionID && q.ApprovalStatus == "pending" })
+	if q == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyQuestion(q), nil
+}
+
+// GetApprovalStats returns statistics about question approvals
+func (m *MemoryDB) GetApprovalStats() (int, int, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pending, approved, rejected int
+	for _, q := range m.questions {
+		switch q.ApprovalStatus {
+		case "pending":
+			pending++
+		case "approved":
+			approved++
+		case "rejected":
+			rejected++
+		}
+	}
+	return pending, approved, rejected, nil
+}
+
+// GetLeastAskedApprovedQuestion gets the least asked approved question for equal distribution
+func (m *MemoryDB) GetLeastAskedApprovedQuestion() (*Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var best *Question
+	for _, q := range m.questions {
+		if q.ApprovalStatus != "approved" {
+			continue
+		}
+		if best == nil || q.TimesAsked < best.TimesAsked ||
+			(q.TimesAsked == best.TimesAsked && q.CreatedAt.Before(best.CreatedAt)) {
+			best = q
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return copyQuestion(best), nil
+}
+
+// IncrementQuestionUsage increments the times_asked count and updates last_asked_at for a question
+func (m *MemoryDB) IncrementQuestionUsage(questionID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if q := m.findQuestion(func(q *Question) bool { return q.ID == questionID }); q != nil {
+		q.TimesAsked++
+		q.LastAskedAt = timePtr(m.now())
+	}
+	return nil
+}
+
+// UpdateQuestionText replaces the text of a question that is still pending
+func (m *MemoryDB) UpdateQuestionText(questionID int, question string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q := m.findQuestion(func(q *Question) bool { return q.ID == questionID && q.ApprovalStatus == "pending" })
+	if q == nil {
+		return fmt.Errorf("no pending question found for editing")
+	}
+	q.Question = question
+	return nil
+}
+
+// GetApprovedQuestionStats returns stats about approved questions usage
+func (m *MemoryDB) GetApprovedQuestionStats() (int, int, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var totalApproved, totalAsked, minAsked int
+	for _, q := range m.questions {
+		if q.ApprovalStatus != "approved" {
+			continue
+		}
+		if totalApproved == 0 || q.TimesAsked < minAsked {
+			minAsked = q.TimesAsked
+		}
+		totalApproved++
+		totalAsked += q.TimesAsked
+	}
+	return totalApproved, totalAsked, minAsked, nil
+}
+
+// Close is a no-op for the in-memory database
+func (m *MemoryDB) Close() error {
+	return nil
+}
+
+// insertBannedWord stores a new banned word, enforcing the case-insensitive unique constraint on word
+func (m *MemoryDB) insertBannedWord(bw *BannedWord) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing := m.findBannedWord(func(b *BannedWord) bool { return strings.EqualFold(b.Word, bw.Word) }); existing != nil {
+		return 0, fmt.Errorf("duplicate entry '%s' for key 'word'", bw.Word)
+	}
+
+	bw.ID = m.nextWord
+	bw.CreatedAt = m.now()
+	if bw.ApprovalStatus == "" {
+		bw.ApprovalStatus = "pending"
+	}
+	m.nextWord++
+	m.bannedWords = append(m.bannedWords, bw)
+	return int64(bw.ID), nil
+}
+
+// AddBannedWord adds a new banned word to the database
+func (m *MemoryDB) AddBannedWord(word, reason, authorID string) error {
+	_, err := m.insertBannedWord(&BannedWord{Word: word, Reason: reason, AuthorID: authorID})
+	return err
+}
+
+// AddBannedWordPending adds a new banned word in pending approval state
+func (m *MemoryDB) AddBannedWordPending(word, reason, authorID, authorName, forumThreadID, originalMessageID string) (int64, error) {
+	return m.insertBannedWord(&BannedWord{
+		Word:              word,
+		Reason:            reason,
+		AuthorID:          authorID,
+		AuthorName:        authorName,
+		ForumThreadID:     stringPtr(forumThreadID),
+		OriginalMessageID: stringPtr(originalMessageID),
+		ApprovalStatus:    "pending",
+	})
+}
+
+// UpdateBannedWordApprovalMessageID updates the approval message ID for a banned word
+func (m *MemoryDB) UpdateBannedWordApprovalMessageID(wordID int, approvalMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID }); bw != nil {
+		bw.ApprovalMessageID = stringPtr(approvalMessageID)
+	}
+	return nil
+}
+
+// GetBannedWordByApprovalMessageID gets a banned word by its approval message ID
+func (m *MemoryDB) GetBannedWordByApprovalMessageID(approvalMessageID string) (*BannedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bw := m.findBannedWord(func(b *BannedWord) bool {
+		return b.ApprovalMessageID != nil && *b.ApprovalMessageID == approvalMessageID
+	})
+	if bw == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyBannedWord(bw), nil
+}
+
+// UpdateBannedWordImportMessageID records the summary post of the import that added a banned word
+func (m *MemoryDB) UpdateBannedWordImportMessageID(wordID int, importMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID }); bw != nil {
+		bw.ImportMessageID = stringPtr(importMessageID)
+	}
+	return nil
+}
+
+// GetBannedWordsByImportMessageID returns the banned words added by one import, alphabetically
+func (m *MemoryDB) GetBannedWordsByImportMessageID(importMessageID string) ([]*BannedWord, error) {
+	return m.sortedBannedWords(func(bw *BannedWord) bool {
+		return bw.ImportMessageID != nil && *bw.ImportMessageID == importMessageID
+	}), nil
+}
+
+// transitionBannedWord applies update to the word if it currently has the given status
+func (m *MemoryDB) transitionBannedWord(wordID int, fromStatus string, update func(bw *BannedWord, now time.Time)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID && b.ApprovalStatus == fromStatus })
+	if bw == nil {
+		return false
+	}
+	update(bw, m.now())
+	return true
+}
+
+// ApproveBannedWordByOpplysar approves a banned word by opplysar
+func (m *MemoryDB) ApproveBannedWordByOpplysar(wordID int, approverID string) error {
+	ok := m.transitionBannedWord(wordID, "pending", func(bw *BannedWord, now time.Time) {
+		bw.ApprovalStatus = "opplysar_approved"
+		bw.OpplysarApprovedBy = stringPtr(approverID)
+		bw.OpplysarApprovedAt = timePtr(now)
+	})
+	if !ok {
+		return fmt.Errorf("no pending banned word found for opplysar approval")
+	}
+	return nil
+}
+
+// ApproveBannedWordByRettskrivar approves a banned word by rettskrivar
+func (m *MemoryDB) ApproveBannedWordByRettskrivar(wordID int, approverID string) error {
+	ok := m.transitionBannedWord(wordID, "opplysar_approved", func(bw *BannedWord, now time.Time) {
+		bw.ApprovalStatus = "fully_approved"
+		bw.RettskrivarApprovedBy = stringPtr(approverID)
+		bw.RettskrivarApprovedAt = timePtr(now)
+	})
+	if !ok {
+		return fmt.Errorf("no opplysar approved banned word found for rettskrivar approval")
+	}
+	return nil
+}
+
+// ApproveBannedWordCombined approves a banned word with combined role approvals
+func (m *MemoryDB) ApproveBannedWordCombined(wordID int, opplysarApprovers, rettskrivarApprovers []string) error {
+	ok := m.transitionBannedWord(wordID, "pending", func(bw *BannedWord, now time.Time) {
+		bw.ApprovalStatus = "fully_approved"
+		bw.OpplysarApprovedBy = stringPtr(strings.Join(opplysarApprovers, ","))
+		bw.RettskrivarApprovedBy = stringPtr(strings.Join(rettskrivarApprovers, ","))
+		bw.OpplysarApprovedAt = timePtr(now)
+		bw.RettskrivarApprovedAt = timePtr(now)
+	})
+	if !ok {
+		return fmt.Errorf("no pending banned word found for combined approval")
+	}
+	return nil
+}
+
+// UpdateBannedWordForumThreadID updates the forum thread ID for a banned word
+func (m *MemoryDB) UpdateBannedWordForumThreadID(wordID int, forumThreadID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID }); bw != nil {
+		bw.ForumThreadID = stringPtr(forumThreadID)
+	}
+	return nil
+}
+
+// RejectBannedWord updates the approval status for a banned word to rejected
+func (m *MemoryDB) RejectBannedWord(wordID int, rejectorID, reason string) error {
+	ok := m.transitionBannedWord(wordID, "pending", func(bw *BannedWord, now time.Time) {
+		bw.ApprovalStatus = "rejected"
+		bw.OpplysarApprovedBy = stringPtr(rejectorID)
+		bw.OpplysarApprovedAt = timePtr(now)
+		bw.RejectionReason = reason
+	})
+	if !ok {
+		return fmt.Errorf("no pending banned word found for rejection")
+	}
+	return nil
+}
+
+// GetPendingBannedWord retrieves the next pending banned word for approval
+func (m *MemoryDB) GetPendingBannedWord() (*BannedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var oldest *BannedWord
+	for _, bw := range m.bannedWords {
+		if bw.ApprovalStatus == "pending" && (oldest == nil || bw.CreatedAt.Before(oldest.CreatedAt)) {
+			oldest = bw
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+	return copyBannedWord(oldest), nil
+}
+
+// GetBannedWordByID gets a banned word by its ID
+func (m *MemoryDB) GetBannedWordByID(wordID int) (*BannedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID })
+	if bw == nil {
+		return nil, sql.ErrNoRows
+	}
+	return copyBannedWord(bw), nil
+}
+
+// GetBannedWordApprovalStats returns statistics about banned word approvals
+func (m *MemoryDB) GetBannedWordApprovalStats() (int, int, int, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pending, opplysarApproved, fullyApproved, rejected int
+	for _, bw := range m.bannedWords {
+		switch bw.ApprovalStatus {
+		case "pending":
+			pending++
+		case "opplysar_approved":
+			opplysarApproved++
+		case "fully_approved":
+			fullyApproved++
+		case "rejected":
+			rejected++
+		}
+	}
+	return pending, opplysarApproved, fullyApproved, rejected, nil
+}
+
+// RemoveBannedWord removes a banned word from the database
+func (m *MemoryDB) RemoveBannedWord(word string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.bannedWords[:0]
+	for _, bw := range m.bannedWords {
+		if !strings.EqualFold(bw.Word, word) {
+			kept = append(kept, bw)
+		}
+	}
+	m.bannedWords = kept
+	return nil
+}
+
+// UpdateBannedWordText replaces a banned word that is still pending and drops its approvals
+func (m *MemoryDB) UpdateBannedWordText(wordID int, word string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing := m.findBannedWord(func(b *BannedWord) bool { return b.ID != wordID && strings.EqualFold(b.Word, word) }); existing != nil {
+		return fmt.Errorf("duplicate entry '%s' for key 'word'", word)
+	}
+	bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID && b.ApprovalStatus == "pending" })
+	if bw == nil {
+		return fmt.Errorf("no pending banned word found for editing")
+	}
+	bw.Word = word
+	var kept []*Approval
+	for _, a := range m.approvals {
+		if a.EntityType != ApprovalEntityBannedWord || a.EntityID != wordID {
+			kept = append(kept, a)
+		}
+	}
+	m.approvals = kept
+	return nil
+}
+
+// UpdateBannedWordForms replaces the explicitly listed inflected forms of a banned word
+func (m *MemoryDB) UpdateBannedWordForms(wordID int, forms []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID }); bw != nil {
+		bw.Forms = splitLines(strings.Join(forms, "\n"))
+	}
+	return nil
+}
+
+// UpdateBannedWordReplacements replaces the suggested correct forms of a banned word
+func (m *MemoryDB) UpdateBannedWordReplacements(wordID int, replacements []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bw := m.findBannedWord(func(b *BannedWord) bool { return b.ID == wordID })
+	if bw == nil {
+		return sql.ErrNoRows
+	}
+	bw.Replacements = splitLines(strings.Join(replacements, "\n"))
+	return nil
+}
+
+// IsBannedWord checks if a word is banned
+func (m *MemoryDB) IsBannedWord(word string) (bool, *BannedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bw := m.findBannedWord(func(b *BannedWord) bool { return strings.EqualFold(b.Word, word) })
+	if bw == nil {
+		return false, nil, nil
+	}
+	return true, copyBannedWord(bw), nil
+}
+
+// GetBannedWords returns all banned words, newest first
+func (m *MemoryDB) GetBannedWords() ([]*BannedWord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	words := make([]*BannedWord, 0, len(m.bannedWords))
+	for _, bw := range m.bannedWords {
+		words = append(words, copyBannedWord(bw))
+	}
+	sort.SliceStable(words, func(i, j int) bool { return words[i].CreatedAt.After(words[j].CreatedAt) })
+	return words, nil
+}
+
+// ListBannedWords returns a page of banned words with an approval status, in alphabetical order
+func (m *MemoryDB) ListBannedWords(status string, offset, limit int) ([]*BannedWord, error) {
+	words := m.sortedBannedWords(func(bw *BannedWord) bool { return bw.ApprovalStatus == status })
+	if offset >= len(words) {
+		return nil, nil
+	}
+	return words[offset:min(offset+limit, len(words))], nil
+}
+
+// CountBannedWords returns how many banned words have an approval status
+func (m *MemoryDB) CountBannedWords(status string) (int, error) {
+	return len(m.sortedBannedWords(func(bw *BannedWord) bool { return bw.ApprovalStatus == status })), nil
+}
+
+// SearchBannedWords returns up to limit banned words in any status that contain text, in alphabetical order
+func (m *MemoryDB) SearchBannedWords(text string, limit int) ([]*BannedWord, error) {
+	text = strings.ToLower(text)
+	words := m.sortedBannedWords(func(bw *BannedWord) bool { return strings.Contains(strings.ToLower(bw.Word), text) })
+	return words[:min(limit, len(words))], nil
+}
+
+// sortedBannedWords returns copies of the words matching pred, sorted by word
+func (m *MemoryDB) sortedBannedWords(pred func(bw *BannedWord) bool) []*BannedWord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var words []*BannedWord
+	for _, bw := range m.bannedWords {
+		if pred(bw) {
+			words = append(words, copyBannedWord(bw))
+		}
+	}
+	sort.Slice(words, func(i, j int) bool { return words[i].Word < words[j].Word })
+	return words
+}
+
+// AddStarboardMessage adds a new starboard message mapping to the database
+func (m *MemoryDB) AddStarboardMessage(originalMessageID, starboardMessageID, channelID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.starboard[originalMessageID]; exists {
+		return fmt.Errorf("duplicate entry '%s' for key 'original_message_id'", originalMessageID)
+	}
+	m.starboard[originalMessageID] = &StarboardMessage{
+		ID:                 m.nextStar,
+		OriginalMessageID:  originalMessageID,
+		StarboardMessageID: starboardMessageID,
+		ChannelID:          channelID,
+		CreatedAt:          m.now(),
+	}
+	m.nextStar++
+	return nil
+}
+
+// GetStarboardMessage gets the starboard message ID for an original message
+func (m *MemoryDB) GetStarboardMessage(originalMessageID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if sm, exists := m.starboard[originalMessageID]; exists {
+		return sm.StarboardMessageID, nil
+	}
+	return "", nil // No starboard message exists yet
+}
+
+// UpdateStarboardMessage updates the starboard message ID for an original message
+func (m *MemoryDB) UpdateStarboardMessage(originalMessageID, starboardMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if sm, exists := m.starboard[originalMessageID]; exists {
+		sm.StarboardMessageID = starboardMessageID
+	}
+	return nil
+}
+
+// RemoveStarboardMessage removes a starboard message mapping from the database
+func (m *MemoryDB) RemoveStarboardMessage(originalMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.starboard, originalMessageID)
+	return nil
+}
+
+// AddApproval records that a user approved an entity under a role. Approving twice is a no-op.
+func (m *MemoryDB) AddApproval(entityType string, entityID int, userID, role string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, a := range m.approvals {
+		if a.EntityType == entityType && a.EntityID == entityID && a.UserID == userID && a.Role == role {
+			return nil
+		}
+	}
+	m.approvals = append(m.approvals, &Approval{
+		ID:         m.nextApproval,
+		EntityType: entityType,
+		EntityID:   entityID,
+		UserID:     userID,
+		Role:       role,
+		CreatedAt:  m.now(),
+	})
+	m.nextApproval++
+	return nil
+}
+
+// GetApprovals returns the approvals of an entity in the order they were given
+func (m *MemoryDB) GetApprovals(entityType string, entityID int) ([]*Approval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var approvals []*Approval
+	for _, a := range m.approvals {
+		if a.EntityType == entityType && a.EntityID == entityID {
+			c := *a
+			approvals = append(approvals, &c)
+		}
+	}
+	return approvals, nil
+}
+
+// GetWarningMode returns how a user wants grammar warnings, WarningModeOn unless they chose otherwise
+func (m *MemoryDB) GetWarningMode(userID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if mode, exists := m.warningModes[userID]; exists {
+		return mode, nil
+	}
+	return WarningModeOn, nil
+}
+
+// SetWarningMode stores how a user wants grammar warnings
+func (m *MemoryDB) SetWarningMode(userID, mode string) error {
+	switch mode {
+	case WarningModeOn, WarningModeOff, WarningModeDM:
+	default:
+		return fmt.Errorf("data truncated for column 'warning_mode': %q", mode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warningModes[userID] = mode
+	return nil
+}
+
+// AddWarning records a warning sent about a message, replacing any earlier one
+func (m *MemoryDB) AddWarning(originalMessageID, channelID, warningMessageID, warningChannelID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.warnings[originalMessageID] = &Warning{
+		OriginalMessageID: originalMessageID,
+		ChannelID:         channelID,
+		WarningMessageID:  warningMessageID,
+		WarningChannelID:  warningChannelID,
+		CreatedAt:         m.now(),
+	}
+	return nil
+}
+
+// GetWarning returns the warning sent about a message, or sql.ErrNoRows if there is none
+func (m *MemoryDB) GetWarning(originalMessageID string) (*Warning, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	w, exists := m.warnings[originalMessageID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+	c := *w
+	return &c, nil
+}
+
+// RemoveWarning forgets the warning sent about a message
+func (m *MemoryDB) RemoveWarning(originalMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.warnings, originalMessageID)
+	return nil
+}
+
+// PruneWarnings forgets warnings sent before the given time and returns how many there were
+func (m *MemoryDB) PruneWarnings(before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pruned int64
+	for id, w := range m.warnings {
+		if w.CreatedAt.Before(before) {
+			delete(m.warnings, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// AddBannedWordRemoval stores a pending request to remove a banned word
+func (m *MemoryDB) AddBannedWordRemoval(wordID int, word, reason, requestedBy string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := &BannedWordRemoval{
+		ID:          m.nextRemoval,
+		WordID:      wordID,
+		Word:        word,
+		Reason:      reason,
+		RequestedBy: requestedBy,
+		Status:      RemovalStatusPending,
+		CreatedAt:   m.now(),
+	}
+	m.removals = append(m.removals, r)
+	m.nextRemoval++
+	return int64(r.ID), nil
+}
+
+// GetBannedWordRemoval returns a removal request, or sql.ErrNoRows if there is none
+func (m *MemoryDB) GetBannedWordRemoval(removalID int) (*BannedWordRemoval, error) {
+	return m.findRemoval(func(r *BannedWordRemoval) bool { return r.ID == removalID })
+}
+
+// GetPendingBannedWordRemoval returns the open removal request for a word, or sql.ErrNoRows if there is none
+func (m *MemoryDB) GetPendingBannedWordRemoval(wordID int) (*BannedWordRemoval, error) {
+	return m.findRemoval(func(r *BannedWordRemoval) bool { return r.WordID == wordID && r.Status == RemovalStatusPending })
+}
+
+// findRemoval returns a copy of the newest removal request matching pred
+func (m *MemoryDB) findRemoval(pred func(r *BannedWordRemoval) bool) (*BannedWordRemoval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.removals) - 1; i >= 0; i-- {
+		if pred(m.removals[i]) {
+			c := *m.removals[i]
+			return &c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// UpdateBannedWordRemovalMessageID stores the ID of the approval post of a removal request
+func (m *MemoryDB) UpdateBannedWordRemovalMessageID(removalID int, approvalMessageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.removals {
+		if r.ID == removalID {
+			r.ApprovalMessageID = stringPtr(approvalMessageID)
+		}
+	}
+	return nil
+}
+
+// ApproveBannedWordRemoval marks a pending removal request as approved by both roles and deletes the word
+func (m *MemoryDB) ApproveBannedWordRemoval(removalID int, opplysarApprovers, rettskrivarApprovers []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.removals {
+		if r.ID != removalID || r.Status != RemovalStatusPending {
+			continue
+		}
+		r.Status = RemovalStatusApproved
+		r.OpplysarApprovedBy = stringPtr(strings.Join(opplysarApprovers, ","))
+		r.RettskrivarApprovedBy = stringPtr(strings.Join(rettskrivarApprovers, ","))
+		r.DecidedAt = timePtr(m.now())
+
+		kept := m.bannedWords[:0]
+		for _, bw := range m.bannedWords {
+			if bw.ID != r.WordID {
+				kept = append(kept, bw)
+			}
+		}
+		m.bannedWords = kept
+		return nil
+	}
+	return fmt.Errorf("no pending removal request found for approval")
+}
+
+// RejectBannedWordRemoval marks a pending removal request as rejected, keeping the word
+func (m *MemoryDB) RejectBannedWordRemoval(removalID int, rejectorID, reason string) error {
+	ok := m.transitionRemoval(removalID, func(r *BannedWordRemoval, now time.Time) {
+		r.Status = RemovalStatusRejected
+		r.RejectedBy = stringPtr(rejectorID)
+		r.RejectionReason = reason
+		r.DecidedAt = timePtr(now)
+	})
+	if !ok {
+		return fmt.Errorf("no pending removal request found for rejection")
+	}
+	return nil
+}
+
+// transitionRemoval applies update to the removal request if it is still pending
+func (m *MemoryDB) transitionRemoval(removalID int, update func(r *BannedWordRemoval, now time.Time)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.removals {
+		if r.ID == removalID && r.Status == RemovalStatusPending {
+			update(r, m.now())
+			return true
+		}
+	}
+	return false
+}
+
+// ClearDatabase deletes all questions from the database
+func (m *MemoryDB) ClearDatabase() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.questions = nil
+	return nil
+}
+
+// GetSchedulerState returns the stored scheduler state
+func (m *MemoryDB) GetSchedulerState() (*SchedulerState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state := m.scheduler
+	return &state, nil
+}
+
+// ClaimDailyPost marks day as the day of the last daily question and reports whether this call did so
+func (m *MemoryDB) ClaimDailyPost(day string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scheduler.LastPostDay == day {
+		return false, nil
+	}
+	m.scheduler.LastPostDay = day
+	m.scheduler.LastPostAt = timePtr(at.UTC())
+	m.scheduler.LastQuestionID = nil
+	m.scheduler.LastMessageID = nil
+	return true, nil
+}
+
+// RecordDailyPost stores which question the claimed daily post was and where it was posted
+func (m *MemoryDB) RecordDailyPost(questionID int, messageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scheduler.LastQuestionID = &questionID
+	m.scheduler.LastMessageID = stringPtr(messageID)
+	return nil
+}
+
+// ReleaseDailyPost gives up a claim on day that was not followed by a post
+func (m *MemoryDB) ReleaseDailyPost(day string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scheduler.LastPostDay == day && m.scheduler.LastMessageID == nil {
+		m.scheduler.LastPostDay = ""
+		m.scheduler.LastPostAt = nil
+	}
+	return nil
+}
+
+// RecordActivity moves the time of the last activity forward to at
+func (m *MemoryDB) RecordActivity(at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scheduler.LastActivityAt == nil || m.scheduler.LastActivityAt.Before(at) {
+		m.scheduler.LastActivityAt = timePtr(at.UTC())
+	}
+	return nil
+}
+
+// GetJobRuns returns the last run of every job that has run, by job name
+func (m *MemoryDB) GetJobRuns() (map[string]*JobRun, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	runs := make(map[string]*JobRun, len(m.jobRuns))
+	for name, run := range m.jobRuns {
+		c := *run
+		runs[name] = &c
+	}
+	return runs, nil
+}
+
+// RecordJobRun stores when a job last ran and the error it ended with, "" for none
+func (m *MemoryDB) RecordJobRun(name string, at time.Time, runErr string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobRuns[name] = &JobRun{Name: name, LastRunAt: at.UTC(), LastError: runErr}
+	return nil
+}
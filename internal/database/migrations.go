@@ -0,0 +1,259 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/mysql/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migration is a single numbered schema change with its rollback.
+// Migration files live in migrations/<driver>/ and are named NNNN_name.up.sql
+// and NNNN_name.down.sql. Every driver must have the same set of versions.
+// The placeholder {{suffix}} is replaced with the configured TableSuffix so
+// beta and production tables never collide.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes whether a migration has been applied.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// loadMigrations reads all embedded migrations for a driver, ordered by version
+func loadMigrations(driver, suffix string) ([]Migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+		version, err := strconv.Atoi(versionPart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", fileName, err)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", fileName, err)
+		}
+		body := strings.ReplaceAll(string(content), "{{suffix}}", suffix)
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = body
+		} else {
+			m.Down = body
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitStatements splits a migration script into individual statements.
+// Neither driver allows several statements in one Exec call.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// ensureMigrationsTable creates the schema_migrations table if needed
+func (db *DB) ensureMigrationsTable() error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version INT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`, db.migrationsTable)
+	if _, err := db.conn.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s table: %w", db.migrationsTable, err)
+	}
+	return nil
+}
+
+// appliedMigrations returns the applied migration versions and when they were applied
+func (db *DB) appliedMigrations() (map[int]time.Time, error) {
+	rows, err := db.conn.Query(fmt.Sprintf("SELECT version, applied_at FROM %s", db.migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// execScript runs every statement of a migration script in order, on one connection
+// so that session variables and prepared statements carry over between them
+func (db *DB) execScript(script string) error {
+	ctx := context.Background()
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%w\nstatement: %s", err, statement)
+		}
+	}
+	return nil
+}
+
+// Migrate applies all pending migrations in version order
+func (db *DB) Migrate() error {
+	log.Println("Running database migrations")
+	if err := db.ensureMigrationsTable(); err != nil {
+		return err
+	}
+
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	for _, m := range db.migrations {
+		if _, done := applied[m.Version]; done {
+			continue
+		}
+
+		log.Printf("Applying migration %04d_%s", m.Version, m.Name)
+		if err := db.execScript(m.Up); err != nil {
+			return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+
+		insert := fmt.Sprintf("INSERT INTO %s (version, name) VALUES (?, ?)", db.migrationsTable)
+		if _, err := db.conn.Exec(insert, m.Version, m.Name); err != nil {
+			return fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	log.Println("Database migrations completed")
+	return nil
+}
+
+// MigrateDown rolls back the given number of most recently applied migrations
+func (db *DB) MigrateDown(steps int) error {
+	if err := db.ensureMigrationsTable(); err != nil {
+		return err
+	}
+
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	for i := len(db.migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := db.migrations[i]
+		if _, done := applied[m.Version]; !done {
+			continue
+		}
+		if m.Down == "" {
+			return fmt.Errorf("migration %04d_%s has no down script", m.Version, m.Name)
+		}
+
+		log.Printf("Rolling back migration %04d_%s", m.Version, m.Name)
+		if err := db.execScript(m.Down); err != nil {
+			return fmt.Errorf("rollback of %04d_%s failed: %w", m.Version, m.Name, err)
+		}
+
+		remove := fmt.Sprintf("DELETE FROM %s WHERE version = ?", db.migrationsTable)
+		if _, err := db.conn.Exec(remove, m.Version); err != nil {
+			return fmt.Errorf("failed to unrecord migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		steps--
+	}
+	return nil
+}
+
+// MigrationStatus lists every known migration and whether it is applied
+func (db *DB) MigrationStatus() ([]MigrationStatus, error) {
+	if err := db.ensureMigrationsTable(); err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(db.migrations))
+	for _, m := range db.migrations {
+		status := MigrationStatus{Migration: m}
+		if appliedAt, done := applied[m.Version]; done {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
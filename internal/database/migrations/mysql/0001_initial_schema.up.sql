@@ -0,0 +1,43 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases created before the
+-- migration system adopt it as-is; 0011 adds the banned word columns that
+-- older tables may lack.
+CREATE TABLE IF NOT EXISTS daily_questions{{suffix}} (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	question TEXT NOT NULL,
+	author_id VARCHAR(255) NOT NULL,
+	author_name VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	times_asked INT DEFAULT 0,
+	last_asked_at TIMESTAMP NULL,
+	message_id VARCHAR(255),
+	channel_id VARCHAR(255),
+	approval_status ENUM('pending', 'approved', 'rejected') DEFAULT 'pending',
+	approval_message_id VARCHAR(255),
+	approved_by VARCHAR(255),
+	approved_at TIMESTAMP NULL
+);
+
+CREATE TABLE IF NOT EXISTS banned_bokmal_words{{suffix}} (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	word VARCHAR(255) NOT NULL UNIQUE,
+	reason TEXT,
+	author_id VARCHAR(255) NOT NULL,
+	author_name VARCHAR(255) NOT NULL,
+	forum_thread_id VARCHAR(255),
+	original_message_id VARCHAR(255),
+	approval_status ENUM('pending', 'opplysar_approved', 'fully_approved', 'rejected') DEFAULT 'pending',
+	approval_message_id VARCHAR(255),
+	opplysar_approved_by VARCHAR(255),
+	opplysar_approved_at TIMESTAMP NULL,
+	rettskrivar_approved_by VARCHAR(255),
+	rettskrivar_approved_at TIMESTAMP NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS starboard_messages{{suffix}} (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	original_message_id VARCHAR(255) NOT NULL UNIQUE,
+	starboard_message_id VARCHAR(255) NOT NULL,
+	channel_id VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
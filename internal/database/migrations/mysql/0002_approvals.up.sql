@@ -0,0 +1,11 @@
+-- One row per user and role that approved a question or banned word, so
+-- dual approvals survive restarts without re-reading message reactions.
+CREATE TABLE IF NOT EXISTS approvals{{suffix}} (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	entity_type VARCHAR(32) NOT NULL,
+	entity_id INT NOT NULL,
+	user_id VARCHAR(255) NOT NULL,
+	role ENUM('opplysar', 'rettskrivar') NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE KEY approvals_entity_user_role (entity_type, entity_id, user_id, role)
+);
@@ -0,0 +1,3 @@
+-- Explicitly listed inflected forms of a banned word, separated by newlines.
+-- Forms from the lexicon file are added when the word list is loaded.
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN forms TEXT NULL;
@@ -0,0 +1,3 @@
+-- Suggested correct forms of a banned word, separated by newlines,
+-- shown in warnings as "ikke → ikkje".
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN replacements TEXT NULL;
@@ -0,0 +1,6 @@
+-- Per-user preferences, e.g. how grammar warnings are delivered.
+CREATE TABLE IF NOT EXISTS user_settings{{suffix}} (
+	user_id VARCHAR(255) NOT NULL PRIMARY KEY,
+	warning_mode ENUM('on', 'off', 'dm') NOT NULL DEFAULT 'on',
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
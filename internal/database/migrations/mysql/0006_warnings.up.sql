@@ -0,0 +1,9 @@
+-- Grammar warnings the bot has sent, so they can be updated or removed when the
+-- flagged message is edited or deleted.
+CREATE TABLE IF NOT EXISTS warnings{{suffix}} (
+	original_message_id VARCHAR(255) NOT NULL PRIMARY KEY,
+	channel_id VARCHAR(255) NOT NULL,
+	warning_message_id VARCHAR(255) NOT NULL,
+	warning_channel_id VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
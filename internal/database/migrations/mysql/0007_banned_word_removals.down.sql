@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS banned_word_removals{{suffix}};
+ALTER TABLE banned_bokmal_words{{suffix}} DROP COLUMN rejection_reason;
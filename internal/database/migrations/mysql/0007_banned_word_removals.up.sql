@@ -0,0 +1,20 @@
+-- Why a reported word was rejected, shown to the reporter.
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN rejection_reason TEXT NULL;
+
+-- Requests to take an approved word off the list. Like reports they need an opplysar
+-- and a rettskrivar; the row is kept as a record after the word itself is deleted.
+CREATE TABLE IF NOT EXISTS banned_word_removals{{suffix}} (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	word_id INT NOT NULL,
+	word VARCHAR(255) NOT NULL,
+	reason TEXT NOT NULL,
+	requested_by VARCHAR(255) NOT NULL,
+	status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+	approval_message_id VARCHAR(255) NULL,
+	opplysar_approved_by TEXT NULL,
+	rettskrivar_approved_by TEXT NULL,
+	rejected_by VARCHAR(255) NULL,
+	rejection_reason TEXT NULL,
+	decided_at TIMESTAMP NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
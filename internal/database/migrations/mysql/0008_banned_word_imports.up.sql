@@ -0,0 +1,3 @@
+-- Words imported from a wordlist share one summary post in the retting channel,
+-- approved or rejected as a batch.
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN import_message_id VARCHAR(255) NULL;
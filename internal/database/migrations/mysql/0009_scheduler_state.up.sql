@@ -0,0 +1,13 @@
+-- What the daily question scheduler last did, so a restart neither posts twice in
+-- a day nor forgets how long the server has been quiet. There is only ever one row;
+-- last_post_day is the calendar day in the scheduler's timezone, e.g. 2024-05-17.
+CREATE TABLE IF NOT EXISTS scheduler_state{{suffix}} (
+	id INT NOT NULL PRIMARY KEY,
+	last_post_day VARCHAR(10) NULL,
+	last_post_at TIMESTAMP NULL,
+	last_question_id INT NULL,
+	last_message_id VARCHAR(255) NULL,
+	last_activity_at TIMESTAMP NULL
+);
+
+INSERT INTO scheduler_state{{suffix}} (id) VALUES (1);
@@ -0,0 +1,7 @@
+-- When each scheduled job last ran, so a restart knows which runs it missed.
+-- last_error is the error of the last run, NULL if it succeeded.
+CREATE TABLE IF NOT EXISTS scheduled_jobs{{suffix}} (
+	name VARCHAR(100) NOT NULL PRIMARY KEY,
+	last_run_at TIMESTAMP NULL,
+	last_error TEXT NULL
+);
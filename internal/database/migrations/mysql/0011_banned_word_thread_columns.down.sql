@@ -0,0 +1 @@
+-- The columns belong to the 0001 baseline, so rolling this back keeps them.
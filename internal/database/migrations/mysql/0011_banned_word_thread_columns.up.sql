@@ -0,0 +1,21 @@
+-- Banned word tables created before the migration system may lack the forum
+-- thread and reported message columns, which 0001 does not add to an existing
+-- table. MySQL has no ADD COLUMN IF NOT EXISTS, so each column is added through
+-- a prepared statement only when information_schema does not list it.
+SET @add_column = IF(
+	(SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'banned_bokmal_words{{suffix}}' AND COLUMN_NAME = 'forum_thread_id') = 0,
+	'ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN forum_thread_id VARCHAR(255) NULL',
+	'DO 0'
+);
+PREPARE add_column FROM @add_column;
+EXECUTE add_column;
+DEALLOCATE PREPARE add_column;
+
+SET @add_column = IF(
+	(SELECT COUNT(*) FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'banned_bokmal_words{{suffix}}' AND COLUMN_NAME = 'original_message_id') = 0,
+	'ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN original_message_id VARCHAR(255) NULL',
+	'DO 0'
+);
+PREPARE add_column FROM @add_column;
+EXECUTE add_column;
+DEALLOCATE PREPARE add_column;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS starboard_messages{{suffix}};
+DROP TABLE IF EXISTS banned_bokmal_words{{suffix}};
+DROP TABLE IF EXISTS daily_questions{{suffix}};
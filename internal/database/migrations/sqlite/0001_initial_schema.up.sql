@@ -0,0 +1,43 @@
+-- Baseline schema, mirroring mysql/0001_initial_schema.up.sql.
+-- ENUM columns become TEXT with a CHECK constraint, and word lookups are
+-- case-insensitive like MySQL's default collation.
+CREATE TABLE IF NOT EXISTS daily_questions{{suffix}} (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	question TEXT NOT NULL,
+	author_id VARCHAR(255) NOT NULL,
+	author_name VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	times_asked INT DEFAULT 0,
+	last_asked_at TIMESTAMP NULL,
+	message_id VARCHAR(255),
+	channel_id VARCHAR(255),
+	approval_status TEXT DEFAULT 'pending' CHECK (approval_status IN ('pending', 'approved', 'rejected')),
+	approval_message_id VARCHAR(255),
+	approved_by VARCHAR(255),
+	approved_at TIMESTAMP NULL
+);
+
+CREATE TABLE IF NOT EXISTS banned_bokmal_words{{suffix}} (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	word VARCHAR(255) NOT NULL UNIQUE COLLATE NOCASE,
+	reason TEXT,
+	author_id VARCHAR(255) NOT NULL,
+	author_name VARCHAR(255) NOT NULL,
+	forum_thread_id VARCHAR(255),
+	original_message_id VARCHAR(255),
+	approval_status TEXT DEFAULT 'pending' CHECK (approval_status IN ('pending', 'opplysar_approved', 'fully_approved', 'rejected')),
+	approval_message_id VARCHAR(255),
+	opplysar_approved_by VARCHAR(255),
+	opplysar_approved_at TIMESTAMP NULL,
+	rettskrivar_approved_by VARCHAR(255),
+	rettskrivar_approved_at TIMESTAMP NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS starboard_messages{{suffix}} (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	original_message_id VARCHAR(255) NOT NULL UNIQUE,
+	starboard_message_id VARCHAR(255) NOT NULL,
+	channel_id VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
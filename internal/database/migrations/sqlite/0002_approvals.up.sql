@@ -0,0 +1,10 @@
+-- Mirrors mysql/0002_approvals.up.sql.
+CREATE TABLE IF NOT EXISTS approvals{{suffix}} (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	entity_type VARCHAR(32) NOT NULL,
+	entity_id INT NOT NULL,
+	user_id VARCHAR(255) NOT NULL,
+	role TEXT NOT NULL CHECK (role IN ('opplysar', 'rettskrivar')),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (entity_type, entity_id, user_id, role)
+);
@@ -0,0 +1 @@
+ALTER TABLE banned_bokmal_words{{suffix}} DROP COLUMN forms;
@@ -0,0 +1,9 @@
+-- Grammar warnings the bot has sent, so they can be updated or removed when the
+-- flagged message is edited or deleted.
+CREATE TABLE IF NOT EXISTS warnings{{suffix}} (
+	original_message_id TEXT NOT NULL PRIMARY KEY,
+	channel_id TEXT NOT NULL,
+	warning_message_id TEXT NOT NULL,
+	warning_channel_id TEXT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Mirrors mysql/0007_banned_word_removals.up.sql.
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN rejection_reason TEXT NULL;
+
+CREATE TABLE IF NOT EXISTS banned_word_removals{{suffix}} (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	word_id INT NOT NULL,
+	word TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	requested_by TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+	approval_message_id TEXT NULL,
+	opplysar_approved_by TEXT NULL,
+	rettskrivar_approved_by TEXT NULL,
+	rejected_by TEXT NULL,
+	rejection_reason TEXT NULL,
+	decided_at TIMESTAMP NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
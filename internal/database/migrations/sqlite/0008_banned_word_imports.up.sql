@@ -0,0 +1,2 @@
+-- Mirrors mysql/0008_banned_word_imports.up.sql.
+ALTER TABLE banned_bokmal_words{{suffix}} ADD COLUMN import_message_id TEXT NULL;
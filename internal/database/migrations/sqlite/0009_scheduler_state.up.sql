@@ -0,0 +1,11 @@
+-- Mirrors mysql/0009_scheduler_state.up.sql.
+CREATE TABLE IF NOT EXISTS scheduler_state{{suffix}} (
+	id INTEGER NOT NULL PRIMARY KEY,
+	last_post_day TEXT NULL,
+	last_post_at TIMESTAMP NULL,
+	last_question_id INT NULL,
+	last_message_id TEXT NULL,
+	last_activity_at TIMESTAMP NULL
+);
+
+INSERT INTO scheduler_state{{suffix}} (id) VALUES (1);
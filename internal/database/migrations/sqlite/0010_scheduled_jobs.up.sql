@@ -0,0 +1,6 @@
+-- Mirrors mysql/0010_scheduled_jobs.up.sql.
+CREATE TABLE IF NOT EXISTS scheduled_jobs{{suffix}} (
+	name TEXT NOT NULL PRIMARY KEY,
+	last_run_at TIMESTAMP NULL,
+	last_error TEXT NULL
+);
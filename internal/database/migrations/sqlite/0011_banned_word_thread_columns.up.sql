@@ -0,0 +1,2 @@
+-- Mirrors mysql/0011_banned_word_thread_columns.up.sql. SQLite databases were
+-- always created by 0001 with both columns, so there is nothing to add.
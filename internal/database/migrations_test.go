@@ -0,0 +1,131 @@
+package database
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"askeladden/internal/config"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- A comment; not a statement
+CREATE TABLE a (
+	id INT -- trailing comments stay with their line
+);
+
+INSERT INTO a VALUES (1); 
+SELECT 1`
+	want := []string{
+		"CREATE TABLE a (\n\tid INT -- trailing comments stay with their line\n);",
+		"INSERT INTO a VALUES (1);",
+		"SELECT 1",
+	}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements = %q, want %q", got, want)
+	}
+	if got := splitStatements("-- only a comment\n"); len(got) != 0 {
+		t.Errorf("splitStatements(comment) = %q, want nothing", got)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	versions := make(map[string][]int)
+	for _, driver := range []string{DriverMySQL, DriverSQLite} {
+		migrations, err := loadMigrations(driver, "_beta")
+		if err != nil {
+			t.Fatalf("loadMigrations(%s): %v", driver, err)
+		}
+		for i, m := range migrations {
+			if m.Version != i+1 {
+				t.Errorf("%s migration %d has version %d", driver, i+1, m.Version)
+			}
+			if strings.Contains(m.Up+m.Down, "{{suffix}}") {
+				t.Errorf("%s migration %04d_%s still has a {{suffix}} placeholder", driver, m.Version, m.Name)
+			}
+			versions[driver] = append(versions[driver], m.Version)
+		}
+		if !strings.Contains(migrations[0].Up, "daily_questions_beta") {
+			t.Errorf("%s baseline does not use the table suffix:\n%s", driver, migrations[0].Up)
+		}
+	}
+	if !reflect.DeepEqual(versions[DriverMySQL], versions[DriverSQLite]) {
+		t.Errorf("drivers have different migrations: mysql %v, sqlite %v", versions[DriverMySQL], versions[DriverSQLite])
+	}
+}
+
+func TestMigrateRoundTrip(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Database.Driver = DriverSQLite
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	cfg.TableSuffix = "_testing"
+	db, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	applied := func() int {
+		t.Helper()
+		statuses, err := db.MigrationStatus()
+		if err != nil || len(statuses) != len(db.migrations) {
+			t.Fatalf("MigrationStatus = %d statuses, %v", len(statuses), err)
+		}
+		count := 0
+		for _, status := range statuses {
+			if status.Applied != (status.AppliedAt != nil) {
+				t.Fatalf("migration %d applied %v at %v", status.Version, status.Applied, status.AppliedAt)
+			}
+			if status.Applied {
+				count++
+			}
+		}
+		return count
+	}
+	tables := func() int {
+		t.Helper()
+		var count int
+		if err := db.conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name LIKE '%_testing' AND name != ?", db.migrationsTable).Scan(&count); err != nil {
+			t.Fatalf("count tables: %v", err)
+		}
+		return count
+	}
+
+	if n := applied(); n != 0 {
+		t.Fatalf("%d migrations applied before Migrate", n)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if n := applied(); n != len(db.migrations) {
+		t.Fatalf("%d of %d migrations applied", n, len(db.migrations))
+	}
+	created := tables()
+	if created == 0 {
+		t.Fatalf("Migrate created no tables")
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate again: %v", err)
+	}
+
+	if err := db.MigrateDown(1); err != nil {
+		t.Fatalf("MigrateDown(1): %v", err)
+	}
+	if n := applied(); n != len(db.migrations)-1 {
+		t.Fatalf("%d migrations applied after rolling back one", n)
+	}
+	if err := db.MigrateDown(len(db.migrations)); err != nil {
+		t.Fatalf("MigrateDown(all): %v", err)
+	}
+	if n, left := applied(), tables(); n != 0 || left != 0 {
+		t.Fatalf("after rolling back everything: %d applied, %d tables left", n, left)
+	}
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate after rollback: %v", err)
+	}
+	if n, recreated := applied(), tables(); n != len(db.migrations) || recreated != created {
+		t.Fatalf("after migrating again: %d applied, %d tables; want %d and %d", n, recreated, len(db.migrations), created)
+	}
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Statuses of a banned word removal request
+const (
+	RemovalStatusPending  = "pending"
+	RemovalStatusApproved = "approved"
+	RemovalStatusRejected = "rejected"
+)
+
+// BannedWordRemoval is a request to take an approved banned word off the list
+type BannedWordRemoval struct {
+	ID                    int
+	WordID                int
+	Word                  string
+	Reason                string
+	RequestedBy           string
+	Status                string
+	ApprovalMessageID     *string
+	OpplysarApprovedBy    *string // comma-separated
+	RettskrivarApprovedBy *string // comma-separated
+	RejectedBy            *string
+	RejectionReason       string
+	DecidedAt             *time.Time
+	CreatedAt             time.Time
+}
+
+// removalColumns lists the columns read by scanBannedWordRemoval, in order
+const removalColumns = "id, word_id, word, reason, requested_by, status, approval_message_id, opplysar_approved_by, rettskrivar_approved_by, rejected_by, rejection_reason, decided_at, created_at"
+
+// scanBannedWordRemoval scans a row selected with removalColumns
+func scanBannedWordRemoval(row rowScanner) (*BannedWordRemoval, error) {
+	var r BannedWordRemoval
+	var rejectionReason sql.NullString
+	err := row.Scan(&r.ID, &r.WordID, &r.Word, &r.Reason, &r.RequestedBy, &r.Status, &r.ApprovalMessageID,
+		&r.OpplysarApprovedBy, &r.RettskrivarApprovedBy, &r.RejectedBy, &rejectionReason, &r.DecidedAt, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.RejectionReason = rejectionReason.String
+	return &r, nil
+}
+
+// AddBannedWordRemoval stores a pending request to remove a banned word
+func (db *DB) AddBannedWordRemoval(wordID int, word, reason, requestedBy string) (int64, error) {
+	log.Printf("Adding removal request for banned word %s by %s", word, requestedBy)
+	query := fmt.Sprintf("INSERT INTO %s (word_id, word, reason, requested_by) VALUES (?, ?, ?, ?)", db.removalsTable)
+	result, err := db.conn.Exec(query, wordID, word, reason, requestedBy)
+	if err != nil {
+		log.Printf("Failed to add removal request: %v", err)
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetBannedWordRemoval returns a removal request, or sql.ErrNoRows if there is none
+func (db *DB) GetBannedWordRemoval(removalID int) (*BannedWordRemoval, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", removalColumns, db.removalsTable)
+	return scanBannedWordRemoval(db.conn.QueryRow(query, removalID))
+}
+
+// GetPendingBannedWordRemoval returns the open removal request for a word, or sql.ErrNoRows if there is none
+func (db *DB) GetPendingBannedWordRemoval(wordID int) (*BannedWordRemoval, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE word_id = ? AND status = 'pending' ORDER BY id DESC LIMIT 1", removalColumns, db.removalsTable)
+	return scanBannedWordRemoval(db.conn.QueryRow(query, wordID))
+}
+
+// UpdateBannedWordRemovalMessageID stores the ID of the approval post of a removal request
+func (db *DB) UpdateBannedWordRemovalMessageID(removalID int, approvalMessageID string) error {
+	query := fmt.Sprintf("UPDATE %s SET approval_message_id = ? WHERE id = ?", db.removalsTable)
+	if _, err := db.conn.Exec(query, approvalMessageID, removalID); err != nil {
+		log.Printf("Failed to update approval message ID of removal %d: %v", removalID, err)
+		return err
+	}
+	return nil
+}
+
+// ApproveBannedWordRemoval marks a pending removal request as approved by both roles and
+// deletes the word in the same transaction, so a request is never approved with the word kept
+func (db *DB) ApproveBannedWordRemoval(removalID int, opplysarApprovers, rettskrivarApprovers []string) error {
+	log.Printf("Approving removal request %d", removalID)
+	tx, err := db.conn.Begin()
+	if err != nil {
+		log.Printf("Failed to begin approval of removal request %d: %v", removalID, err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf("UPDATE %s SET status = 'approved', opplysar_approved_by = ?, rettskrivar_approved_by = ?, decided_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'pending'", db.removalsTable)
+	result, err := tx.Exec(query, strings.Join(opplysarApprovers, ","), strings.Join(rettskrivarApprovers, ","), removalID)
+	if err != nil {
+		log.Printf("Failed to approve removal request %d: %v", removalID, err)
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("no pending removal request found for approval")
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE id IN (SELECT word_id FROM %s WHERE id = ?)", db.bannedWordsTable, db.removalsTable)
+	if _, err := tx.Exec(query, removalID); err != nil {
+		log.Printf("Failed to remove banned word of removal request %d: %v", removalID, err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// RejectBannedWordRemoval marks a pending removal request as rejected, keeping the word
+func (db *DB) RejectBannedWordRemoval(removalID int, rejectorID, reason string) error {
+	log.Printf("Rejecting removal request %d by %s", removalID, rejectorID)
+	query := fmt.Sprintf("UPDATE %s SET status = 'rejected', rejected_by = ?, rejection_reason = ?, decided_at = CURRENT_TIMESTAMP WHERE id = ? AND status = 'pending'", db.removalsTable)
+	result, err := db.conn.Exec(query, rejectorID, reason, removalID)
+	if err != nil {
+		log.Printf("Failed to reject removal request %d: %v", removalID, err)
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("no pending removal request found for rejection")
+	}
+	return nil
+}
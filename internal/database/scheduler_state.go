@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SchedulerState is what the daily question scheduler last did
+type SchedulerState struct {
+	LastPostDay    string // calendar day of the last daily question in the scheduler's timezone, "" if none
+	LastPostAt     *time.Time
+	LastQuestionID *int
+	LastMessageID  *string
+	LastActivityAt *time.Time
+}
+
+// GetSchedulerState returns the stored scheduler state
+func (db *DB) GetSchedulerState() (*SchedulerState, error) {
+	var state SchedulerState
+	var day sql.NullString
+	query := fmt.Sprintf("SELECT last_post_day, last_post_at, last_question_id, last_message_id, last_activity_at FROM %s WHERE id = 1", db.schedulerTable)
+	err := db.conn.QueryRow(query).Scan(&day, &state.LastPostAt, &state.LastQuestionID, &state.LastMessageID, &state.LastActivityAt)
+	if err != nil {
+		log.Printf("Failed to get scheduler state: %v", err)
+		return nil, err
+	}
+	state.LastPostDay = day.String
+	return &state, nil
+}
+
+// ClaimDailyPost marks day as the day of the last daily question and reports whether
+// this call did so, so of two schedulers racing for the same day only one posts
+func (db *DB) ClaimDailyPost(day string, at time.Time) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET last_post_day = ?, last_post_at = ?, last_question_id = NULL, last_message_id = NULL WHERE id = 1 AND (last_post_day IS NULL OR last_post_day <> ?)", db.schedulerTable)
+	result, err := db.conn.Exec(query, day, at.UTC(), day)
+	if err != nil {
+		log.Printf("Failed to claim daily post for %s: %v", day, err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// RecordDailyPost stores which question the claimed daily post was and where it was posted
+func (db *DB) RecordDailyPost(questionID int, messageID string) error {
+	query := fmt.Sprintf("UPDATE %s SET last_question_id = ?, last_message_id = ? WHERE id = 1", db.schedulerTable)
+	if _, err := db.conn.Exec(query, questionID, messageID); err != nil {
+		log.Printf("Failed to record daily post: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseDailyPost gives up a claim on day that was not followed by a post, so that a
+// later check or the fallback can still post that day
+func (db *DB) ReleaseDailyPost(day string) error {
+	query := fmt.Sprintf("UPDATE %s SET last_post_day = NULL, last_post_at = NULL WHERE id = 1 AND last_post_day = ? AND last_message_id IS NULL", db.schedulerTable)
+	if _, err := db.conn.Exec(query, day); err != nil {
+		log.Printf("Failed to release daily post for %s: %v", day, err)
+		return err
+	}
+	return nil
+}
+
+// RecordActivity moves the time of the last activity forward to at
+func (db *DB) RecordActivity(at time.Time) error {
+	query := fmt.Sprintf("UPDATE %s SET last_activity_at = ? WHERE id = 1 AND (last_activity_at IS NULL OR last_activity_at < ?)", db.schedulerTable)
+	if _, err := db.conn.Exec(query, at.UTC(), at.UTC()); err != nil {
+		log.Printf("Failed to record activity: %v", err)
+		return err
+	}
+	return nil
+}
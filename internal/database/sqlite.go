@@ -0,0 +1,44 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"askeladden/internal/config"
+	_ "modernc.org/sqlite"
+)
+
+// Supported values for the database.driver config key
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite"
+)
+
+// openSQLite opens (or creates) the SQLite database file at database.path.
+// The same queries as for MySQL are used, so the two backends behave identically
+// apart from the schema, which lives in migrations/sqlite.
+func openSQLite(cfg *config.Config) (*sql.DB, error) {
+	path := cfg.Database.Path
+	if path == "" {
+		path = "askeladden.db"
+	}
+	log.Printf("Opening SQLite database at %s", path)
+
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)", path)
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		log.Printf("Failed to open SQLite database: %v", err)
+		return nil, err
+	}
+
+	// SQLite only allows one writer at a time; serialise access through a single connection
+	conn.SetMaxOpenConns(1)
+
+	if err := conn.Ping(); err != nil {
+		log.Printf("Failed to ping SQLite database: %v", err)
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// How a user gets grammar warnings
+const (
+	WarningModeOn  = "on"  // a reply in the channel
+	WarningModeOff = "off" // no warnings
+	WarningModeDM  = "dm"  // a direct message
+)
+
+// GetWarningMode returns how a user wants grammar warnings, WarningModeOn unless they chose otherwise
+func (db *DB) GetWarningMode(userID string) (string, error) {
+	var mode string
+	query := fmt.Sprintf("SELECT warning_mode FROM %s WHERE user_id = ?", db.settingsTable)
+	err := db.conn.QueryRow(query, userID).Scan(&mode)
+	if err == sql.ErrNoRows {
+		return WarningModeOn, nil
+	}
+	if err != nil {
+		log.Printf("Failed to get warning mode for %s: %v", userID, err)
+		return WarningModeOn, err
+	}
+	return mode, nil
+}
+
+// SetWarningMode stores how a user wants grammar warnings, in one upsert so that two
+// calls for a new user at the same time cannot both insert
+func (db *DB) SetWarningMode(userID, mode string) error {
+	log.Printf("Setting warning mode of %s to %s", userID, mode)
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, warning_mode) VALUES (?, ?) ON DUPLICATE KEY UPDATE warning_mode = VALUES(warning_mode), updated_at = CURRENT_TIMESTAMP", db.settingsTable)
+	if db.driver == DriverSQLite {
+		query = fmt.Sprintf("INSERT INTO %s (user_id, warning_mode) VALUES (?, ?) ON CONFLICT(user_id) DO UPDATE SET warning_mode = excluded.warning_mode, updated_at = CURRENT_TIMESTAMP", db.settingsTable)
+	}
+	if _, err := db.conn.Exec(query, userID, mode); err != nil {
+		log.Printf("Failed to set warning mode: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Warning is a grammar warning the bot sent about a message
+type Warning struct {
+	OriginalMessageID string
+	ChannelID         string // channel of the flagged message
+	WarningMessageID  string
+	WarningChannelID  string // the same channel for a reply, the DM channel for a DM
+	CreatedAt         time.Time
+}
+
+// AddWarning records a warning sent about a message, replacing any earlier one
+func (db *DB) AddWarning(originalMessageID, channelID, warningMessageID, warningChannelID string) error {
+	log.Printf("Recording warning %s for message %s", warningMessageID, originalMessageID)
+	if err := db.RemoveWarning(originalMessageID); err != nil {
+		return err
+	}
+	query := fmt.Sprintf("INSERT INTO %s (original_message_id, channel_id, warning_message_id, warning_channel_id) VALUES (?, ?, ?, ?)", db.warningsTable)
+	if _, err := db.conn.Exec(query, originalMessageID, channelID, warningMessageID, warningChannelID); err != nil {
+		log.Printf("Failed to record warning: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetWarning returns the warning sent about a message, or sql.ErrNoRows if there is none
+func (db *DB) GetWarning(originalMessageID string) (*Warning, error) {
+	var w Warning
+	query := fmt.Sprintf("SELECT original_message_id, channel_id, warning_message_id, warning_channel_id, created_at FROM %s WHERE original_message_id = ?", db.warningsTable)
+	err := db.conn.QueryRow(query, originalMessageID).Scan(&w.OriginalMessageID, &w.ChannelID, &w.WarningMessageID, &w.WarningChannelID, &w.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// RemoveWarning forgets the warning sent about a message
+func (db *DB) RemoveWarning(originalMessageID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE original_message_id = ?", db.warningsTable)
+	if _, err := db.conn.Exec(query, originalMessageID); err != nil {
+		log.Printf("Failed to remove warning for message %s: %v", originalMessageID, err)
+		return err
+	}
+	return nil
+}
+
+// PruneWarnings forgets warnings sent before the given time and returns how many there were
+func (db *DB) PruneWarnings(before time.Time) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", db.warningsTable)
+	result, err := db.conn.Exec(query, before.UTC())
+	if err != nil {
+		log.Printf("Failed to prune warnings: %v", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
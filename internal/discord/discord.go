@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Client is the subset of the Discord API that Askeladden uses.
+// *Session implements it on top of a real discordgo session, and
+// fake.Client implements it in memory for tests.
+type Client interface {
+	// BotUser returns the bot's own user, or nil if it is not known yet
+	BotUser() *discordgo.User
+	// GuildCount returns the number of guilds the bot is a member of
+	GuildCount() int
+
+	// Messages
+	ChannelMessage(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error
+
+	// Reactions
+	MessageReactionAdd(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error
+	MessageReactions(channelID, messageID, emojiID string, limit int, beforeID, afterID string, options ...discordgo.RequestOption) ([]*discordgo.User, error)
+
+	// Channels and threads
+	Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelEdit(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ForumThreadStart(channelID, name string, archiveDuration int, content string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+
+	// Users, members and roles
+	User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)
+	GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error)
+	GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error)
+	GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+	GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+
+	// Interactions and application commands
+	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	InteractionResponse(interaction *discordgo.Interaction, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+}
+
+// Session adapts a discordgo session to the Client interface.
+type Session struct {
+	*discordgo.Session
+}
+
+// Session implements the Client interface
+var _ Client = (*Session)(nil)
+
+// Wrap returns a Client backed by the given discordgo session
+func Wrap(s *discordgo.Session) *Session {
+	return &Session{Session: s}
+}
+
+// BotUser returns the bot user from the session state, falling back to the REST API
+func (s *Session) BotUser() *discordgo.User {
+	if s.State != nil && s.State.User != nil {
+		return s.State.User
+	}
+	user, err := s.User("@me")
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// GuildCount returns the number of guilds in the session state
+func (s *Session) GuildCount() int {
+	if s.State == nil {
+		return 0
+	}
+	return len(s.State.Guilds)
+}
+
+// IsSelf reports whether userID is the bot itself
+func IsSelf(c Client, userID string) bool {
+	botUser := c.BotUser()
+	return botUser != nil && botUser.ID == userID
+}
@@ -0,0 +1,240 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// Gateway opcodes used by the server
+const (
+	opDispatch     = 0
+	opHeartbeat    = 1
+	opIdentify     = 2
+	opResume       = 6
+	opHello        = 10
+	opHeartbeatAck = 11
+)
+
+// heartbeatInterval is long enough that discordgo never gives up on a test run
+const heartbeatInterval = 45000
+
+type gatewayPayload struct {
+	Op       int             `json:"op"`
+	Sequence int64           `json:"s,omitempty"`
+	Type     string          `json:"t,omitempty"`
+	Data     json.RawMessage `json:"d"`
+}
+
+// serveGateway upgrades the connection and speaks just enough of the gateway
+// protocol for discordgo: hello, identify/resume, heartbeats and dispatches.
+func (s *Server) serveGateway(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[DISCORDTEST] Gateway upgrade failed: %v", err)
+		return
+	}
+
+	s.connMu.Lock()
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.conn = conn
+	s.connMu.Unlock()
+
+	s.send(opHello, "", map[string]int{"heartbeat_interval": heartbeatInterval})
+
+	for {
+		var payload gatewayPayload
+		if err := conn.ReadJSON(&payload); err != nil {
+			return
+		}
+
+		switch payload.Op {
+		case opHeartbeat:
+			s.send(opHeartbeatAck, "", nil)
+		case opIdentify:
+			var identify struct {
+				Token   string           `json:"token"`
+				Intents discordgo.Intent `json:"intents"`
+			}
+			json.Unmarshal(payload.Data, &identify)
+			if identify.Token != "Bot "+Token {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4004, "Authentication failed."))
+				conn.Close()
+				return
+			}
+			s.mu.Lock()
+			s.intents = identify.Intents
+			s.mu.Unlock()
+			s.Dispatch("READY", &discordgo.Ready{
+				Version:     9,
+				SessionID:   "test-session",
+				User:        s.bot,
+				Application: &discordgo.Application{ID: BotID},
+				Guilds:      []*discordgo.Guild{{ID: s.GuildID, Unavailable: true}},
+			})
+		case opResume:
+			s.Dispatch("RESUMED", map[string]interface{}{})
+		}
+	}
+}
+
+// send writes a gateway payload to the connected client, if any
+func (s *Server) send(op int, eventType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[DISCORDTEST] Failed to encode %s payload: %v", eventType, err)
+		return
+	}
+
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.conn == nil {
+		return
+	}
+
+	payload := gatewayPayload{Op: op, Type: eventType, Data: raw}
+	if op == opDispatch {
+		s.sequence++
+		payload.Sequence = s.sequence
+	}
+	if err := s.conn.WriteJSON(payload); err != nil {
+		log.Printf("[DISCORDTEST] Failed to send %s: %v", eventType, err)
+	}
+}
+
+// closeGateway drops the gateway connection
+func (s *Server) closeGateway() {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Dispatch sends a gateway event such as "MESSAGE_CREATE" to the bot
+func (s *Server) Dispatch(eventType string, data interface{}) {
+	s.send(opDispatch, eventType, data)
+}
+
+// SendMessage posts a message as a guild member and dispatches MESSAGE_CREATE.
+// replyTo, if not empty, makes the message a reply to that message.
+func (s *Server) SendMessage(channelID, userID, content, replyTo string) *discordgo.Message {
+	s.mu.Lock()
+	msg := &discordgo.Message{ChannelID: channelID, Content: content, Author: s.users[userID]}
+	if member, exists := s.members[userID]; exists {
+		msg.Member = &discordgo.Member{Roles: member.Roles}
+	}
+	if replyTo != "" {
+		msg.MessageReference = &discordgo.MessageReference{MessageID: replyTo, ChannelID: channelID, GuildID: s.GuildID}
+		if referenced, exists := s.messages[replyTo]; exists {
+			msg.ReferencedMessage = s.render(referenced)
+		}
+	}
+	created := s.storeMessage(msg)
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_CREATE", created)
+	return created
+}
+
+// React adds a member's reaction to a message and dispatches MESSAGE_REACTION_ADD
+func (s *Server) React(messageID, userID, emoji string) {
+	s.mu.Lock()
+	msg, exists := s.messages[messageID]
+	if !exists || !s.addReaction(messageID, emoji, userID) {
+		s.mu.Unlock()
+		return
+	}
+	event := &discordgo.MessageReactionAdd{
+		MessageReaction: &discordgo.MessageReaction{
+			UserID: userID, MessageID: messageID, ChannelID: msg.ChannelID, GuildID: msg.GuildID, Emoji: discordgo.Emoji{Name: emoji},
+		},
+		Member: s.memberCopy(userID),
+	}
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_REACTION_ADD", event)
+}
+
+// Unreact removes a member's reaction from a message and dispatches MESSAGE_REACTION_REMOVE
+func (s *Server) Unreact(messageID, userID, emoji string) {
+	s.mu.Lock()
+	msg, exists := s.messages[messageID]
+	if !exists || !s.removeReaction(messageID, emoji, userID) {
+		s.mu.Unlock()
+		return
+	}
+	event := &discordgo.MessageReactionRemove{MessageReaction: &discordgo.MessageReaction{
+		UserID: userID, MessageID: messageID, ChannelID: msg.ChannelID, GuildID: msg.GuildID, Emoji: discordgo.Emoji{Name: emoji},
+	}}
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_REACTION_REMOVE", event)
+}
+
+// Interact dispatches INTERACTION_CREATE for a member. The interaction's ID,
+// token, guild and member are filled in; set Type, Data, ChannelID and Message.
+// The bot's responses are available from InteractionResponses.
+func (s *Server) Interact(userID string, interaction *discordgo.Interaction) *discordgo.Interaction {
+	s.mu.Lock()
+	interaction.ID = s.newID()
+	interaction.Token = "token-" + interaction.ID
+	interaction.AppID = BotID
+	interaction.GuildID = s.GuildID
+	interaction.Member = s.memberCopy(userID)
+	if interaction.Member == nil {
+		interaction.User = s.users[userID]
+	}
+	if interaction.Message != nil {
+		if msg, exists := s.messages[interaction.Message.ID]; exists {
+			interaction.Message = s.render(msg)
+		}
+	}
+	s.interactions[interaction.ID] = interaction
+	s.mu.Unlock()
+
+	s.Dispatch("INTERACTION_CREATE", interaction)
+	return interaction
+}
+
+// modalSubmitData is a modal submission as Discord sends it. discordgo's
+// ModalSubmitInteractionData does not marshal its components, so Interact
+// would dispatch the modal without the submitted values.
+type modalSubmitData struct {
+	CustomID   string                       `json:"custom_id"`
+	Components []discordgo.MessageComponent `json:"components"`
+}
+
+func (modalSubmitData) Type() discordgo.InteractionType {
+	return discordgo.InteractionModalSubmit
+}
+
+// SubmitModal dispatches the submission of the modal customID by a member, with one text input per row
+func (s *Server) SubmitModal(userID, channelID, customID string, inputs ...discordgo.TextInput) *discordgo.Interaction {
+	data := modalSubmitData{CustomID: customID}
+	for _, input := range inputs {
+		data.Components = append(data.Components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{input}})
+	}
+	return s.Interact(userID, &discordgo.Interaction{
+		Type:      discordgo.InteractionModalSubmit,
+		ChannelID: channelID,
+		Data:      data,
+	})
+}
+
+// memberCopy returns a copy of a member with its user, or nil. Callers must hold s.mu.
+func (s *Server) memberCopy(userID string) *discordgo.Member {
+	member, exists := s.members[userID]
+	if !exists {
+		return nil
+	}
+	copied := *member
+	copied.Roles = append([]string(nil), member.Roles...)
+	return &copied
+}
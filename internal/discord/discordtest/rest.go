@@ -0,0 +1,601 @@
+package discordtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord JSON error codes returned for missing resources
+const (
+	codeUnknownChannel     = 10003
+	codeUnknownMember      = 10007
+	codeUnknownMessage     = 10008
+	codeUnknownRole        = 10011
+	codeUnknownUser        = 10013
+	codeUnknownInteraction = 10062
+)
+
+// routes builds the REST and gateway handlers
+func (s *Server) routes() http.Handler {
+	api := "/api/v" + discordgo.APIVersion
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+api+"/gateway", s.handleGateway)
+	mux.HandleFunc("GET "+api+"/gateway/bot", s.handleGateway)
+	mux.HandleFunc("GET /ws/", s.serveGateway)
+
+	mux.HandleFunc("GET "+api+"/users/{user}", s.authed(s.getUser))
+	mux.HandleFunc("POST "+api+"/users/@me/channels", s.authed(s.createDM))
+
+	mux.HandleFunc("GET "+api+"/channels/{channel}", s.authed(s.getChannel))
+	mux.HandleFunc("PATCH "+api+"/channels/{channel}", s.authed(s.editChannel))
+	mux.HandleFunc("POST "+api+"/channels/{channel}/messages", s.authed(s.createMessage))
+	mux.HandleFunc("GET "+api+"/channels/{channel}/messages/{message}", s.authed(s.getMessage))
+	mux.HandleFunc("PATCH "+api+"/channels/{channel}/messages/{message}", s.authed(s.editMessage))
+	mux.HandleFunc("DELETE "+api+"/channels/{channel}/messages/{message}", s.authed(s.deleteMessage))
+	mux.HandleFunc("GET "+api+"/channels/{channel}/messages/{message}/reactions/{emoji}", s.authed(s.getReactions))
+	mux.HandleFunc("PUT "+api+"/channels/{channel}/messages/{message}/reactions/{emoji}/@me", s.authed(s.addOwnReaction))
+	mux.HandleFunc("POST "+api+"/channels/{channel}/threads", s.authed(s.startThread))
+
+	mux.HandleFunc("GET "+api+"/guilds/{guild}/members/{user}", s.authed(s.getMember))
+	mux.HandleFunc("GET "+api+"/guilds/{guild}/roles", s.authed(s.getRoles))
+	mux.HandleFunc("PUT "+api+"/guilds/{guild}/members/{user}/roles/{role}", s.authed(s.addMemberRole))
+	mux.HandleFunc("DELETE "+api+"/guilds/{guild}/members/{user}/roles/{role}", s.authed(s.removeMemberRole))
+
+	mux.HandleFunc("POST "+api+"/interactions/{interaction}/{token}/callback", s.authed(s.respondInteraction))
+	mux.HandleFunc("GET "+api+"/webhooks/{app}/{token}/messages/@original", s.authed(s.getInteractionResponse))
+	mux.HandleFunc("PATCH "+api+"/webhooks/{app}/{token}/messages/@original", s.authed(s.editInteractionResponse))
+	mux.HandleFunc("PUT "+api+"/applications/{app}/commands", s.authed(s.overwriteCommands))
+	mux.HandleFunc("PUT "+api+"/applications/{app}/guilds/{guild}/commands", s.authed(s.overwriteCommands))
+
+	return mux
+}
+
+// authed rejects requests that do not carry the bot token
+func (s *Server) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bot "+Token {
+			writeError(w, http.StatusUnauthorized, 0, "401: Unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "message": message})
+}
+
+// decodeComponents decodes message components through discordgo's own unmarshaller
+func decodeComponents(raw json.RawMessage) []discordgo.MessageComponent {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var holder discordgo.Message
+	data, _ := json.Marshal(map[string]json.RawMessage{"components": raw})
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil
+	}
+	return holder.Components
+}
+
+func (s *Server) handleGateway(w http.ResponseWriter, r *http.Request) {
+	url := "ws" + strings.TrimPrefix(s.http.URL, "http") + "/ws"
+	writeJSON(w, http.StatusOK, map[string]interface{}{"url": url, "shards": 1})
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("user")
+	if id == "@me" {
+		id = BotID
+	}
+	user, exists := s.users[id]
+	if !exists {
+		writeError(w, http.StatusNotFound, codeUnknownUser, "Unknown User")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) createDM(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RecipientID string `json:"recipient_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipient, exists := s.users[body.RecipientID]
+	if !exists {
+		writeError(w, http.StatusNotFound, codeUnknownUser, "Unknown User")
+		return
+	}
+	id, exists := s.dms[recipient.ID]
+	if !exists {
+		id = s.newID()
+		s.dms[recipient.ID] = id
+		s.channels[id] = &discordgo.Channel{ID: id, Type: discordgo.ChannelTypeDM, Recipients: []*discordgo.User{recipient}}
+	}
+	writeJSON(w, http.StatusOK, s.channels[id])
+}
+
+func (s *Server) getChannel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, exists := s.channels[r.PathValue("channel")]
+	if !exists {
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	writeJSON(w, http.StatusOK, channel)
+}
+
+func (s *Server) editChannel(w http.ResponseWriter, r *http.Request) {
+	var body discordgo.ChannelEdit
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, exists := s.channels[r.PathValue("channel")]
+	if !exists {
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	if body.Name != "" {
+		channel.Name = body.Name
+	}
+	if body.Archived != nil || body.Locked != nil {
+		if channel.ThreadMetadata == nil {
+			channel.ThreadMetadata = &discordgo.ThreadMetadata{}
+		}
+		if body.Archived != nil {
+			channel.ThreadMetadata.Archived = *body.Archived
+		}
+		if body.Locked != nil {
+			channel.ThreadMetadata.Locked = *body.Locked
+		}
+	}
+	writeJSON(w, http.StatusOK, channel)
+}
+
+// messageBody is the subset of discordgo.MessageSend and MessageEdit the server understands
+type messageBody struct {
+	Content    *string                     `json:"content"`
+	Embeds     *[]*discordgo.MessageEmbed  `json:"embeds"`
+	Components json.RawMessage             `json:"components"`
+	Reference  *discordgo.MessageReference `json:"message_reference"`
+	Flags      discordgo.MessageFlags      `json:"flags"`
+}
+
+func (s *Server) createMessage(w http.ResponseWriter, r *http.Request) {
+	var body messageBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	channelID := r.PathValue("channel")
+	if _, exists := s.channels[channelID]; !exists {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	msg := &discordgo.Message{ChannelID: channelID, Author: s.bot, Flags: body.Flags}
+	if body.Content != nil {
+		msg.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		msg.Embeds = *body.Embeds
+	}
+	msg.Components = decodeComponents(body.Components)
+	if body.Reference != nil {
+		msg.MessageReference = body.Reference
+		if referenced, exists := s.messages[body.Reference.MessageID]; exists {
+			msg.ReferencedMessage = s.render(referenced)
+		}
+	}
+	created := s.storeMessage(msg)
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_CREATE", created)
+	writeJSON(w, http.StatusOK, created)
+}
+
+func (s *Server) getMessage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, exists := s.messages[r.PathValue("message")]
+	if !exists || msg.ChannelID != r.PathValue("channel") {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.render(msg))
+}
+
+func (s *Server) editMessage(w http.ResponseWriter, r *http.Request) {
+	var body messageBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	msg, exists := s.messages[r.PathValue("message")]
+	if !exists || msg.ChannelID != r.PathValue("channel") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	if body.Content != nil {
+		msg.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		msg.Embeds = *body.Embeds
+	}
+	if body.Components != nil {
+		msg.Components = decodeComponents(body.Components)
+	}
+	edited := s.render(msg)
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_UPDATE", edited)
+	writeJSON(w, http.StatusOK, edited)
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	msg, exists := s.messages[r.PathValue("message")]
+	if !exists || msg.ChannelID != r.PathValue("channel") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	delete(s.messages, msg.ID)
+	s.mu.Unlock()
+
+	s.Dispatch("MESSAGE_DELETE", &discordgo.Message{ID: msg.ID, ChannelID: msg.ChannelID, GuildID: msg.GuildID})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getReactions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.messages[r.PathValue("message")]; !exists {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	users := []*discordgo.User{}
+	for _, id := range s.reactions[r.PathValue("message")][r.PathValue("emoji")] {
+		if user, exists := s.users[id]; exists {
+			users = append(users, user)
+		}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) addOwnReaction(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	msg, exists := s.messages[r.PathValue("message")]
+	if !exists || msg.ChannelID != r.PathValue("channel") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	emoji := r.PathValue("emoji")
+	added := s.addReaction(msg.ID, emoji, BotID)
+	s.mu.Unlock()
+
+	if added {
+		s.Dispatch("MESSAGE_REACTION_ADD", &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+			UserID: BotID, MessageID: msg.ID, ChannelID: msg.ChannelID, GuildID: msg.GuildID, Emoji: discordgo.Emoji{Name: emoji},
+		}})
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) startThread(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		discordgo.ThreadStart
+		Message *messageBody `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	parent, exists := s.channels[r.PathValue("channel")]
+	if !exists {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	thread := &discordgo.Channel{
+		ID:       s.newID(),
+		GuildID:  parent.GuildID,
+		ParentID: parent.ID,
+		Name:     body.Name,
+		Type:     discordgo.ChannelTypeGuildPublicThread,
+		OwnerID:  BotID,
+	}
+	s.channels[thread.ID] = thread
+	s.threads = append(s.threads, thread)
+
+	var starter *discordgo.Message
+	if body.Message != nil {
+		starter = &discordgo.Message{ChannelID: thread.ID, Author: s.bot}
+		if body.Message.Content != nil {
+			starter.Content = *body.Message.Content
+		}
+		if body.Message.Embeds != nil {
+			starter.Embeds = *body.Message.Embeds
+		}
+		starter = s.storeMessage(starter)
+	}
+	s.mu.Unlock()
+
+	s.Dispatch("THREAD_CREATE", thread)
+	if starter != nil {
+		s.Dispatch("MESSAGE_CREATE", starter)
+	}
+	writeJSON(w, http.StatusCreated, thread)
+}
+
+func (s *Server) getMember(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, exists := s.members[r.PathValue("user")]
+	if r.PathValue("guild") != s.GuildID || !exists {
+		writeError(w, http.StatusNotFound, codeUnknownMember, "Unknown Member")
+		return
+	}
+	writeJSON(w, http.StatusOK, member)
+}
+
+func (s *Server) getRoles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.PathValue("guild") != s.GuildID {
+		writeError(w, http.StatusNotFound, 10004, "Unknown Guild")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.sortedRoles())
+}
+
+func (s *Server) addMemberRole(w http.ResponseWriter, r *http.Request) {
+	s.changeMemberRole(w, r, true)
+}
+
+func (s *Server) removeMemberRole(w http.ResponseWriter, r *http.Request) {
+	s.changeMemberRole(w, r, false)
+}
+
+// changeMemberRole adds or removes a role, enforcing the role hierarchy like Discord does
+func (s *Server) changeMemberRole(w http.ResponseWriter, r *http.Request, add bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member, exists := s.members[r.PathValue("user")]
+	if r.PathValue("guild") != s.GuildID || !exists {
+		writeError(w, http.StatusNotFound, codeUnknownMember, "Unknown Member")
+		return
+	}
+	roleID := r.PathValue("role")
+	var target *discordgo.Role
+	for _, role := range s.roles {
+		if role.ID == roleID {
+			target = role
+		}
+	}
+	if target == nil {
+		writeError(w, http.StatusNotFound, codeUnknownRole, "Unknown Role")
+		return
+	}
+	if s.highestBotRole() <= target.Position {
+		writeError(w, http.StatusForbidden, 50013, "Missing Permissions")
+		return
+	}
+
+	kept := member.Roles[:0]
+	for _, id := range member.Roles {
+		if id != roleID {
+			kept = append(kept, id)
+		}
+	}
+	member.Roles = kept
+	if add {
+		member.Roles = append(member.Roles, roleID)
+	}
+	s.roleChanges = append(s.roleChanges, RoleChange{GuildID: s.GuildID, UserID: member.User.ID, RoleID: roleID, Added: add})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// highestBotRole returns the position of the bot's highest role. Callers must hold s.mu.
+func (s *Server) highestBotRole() int {
+	highest := 0
+	for _, id := range s.members[BotID].Roles {
+		for _, role := range s.roles {
+			if role.ID == id && role.Position > highest {
+				highest = role.Position
+			}
+		}
+	}
+	return highest
+}
+
+func (s *Server) respondInteraction(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+		Data *struct {
+			discordgo.InteractionResponseData
+			Components json.RawMessage `json:"components"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	interaction, exists := s.interactions[r.PathValue("interaction")]
+	if !exists || interaction.Token != r.PathValue("token") {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownInteraction, "Unknown interaction")
+		return
+	}
+
+	response := InteractionResponse{InteractionID: interaction.ID, Type: body.Type}
+	if body.Data != nil {
+		data := body.Data.InteractionResponseData
+		data.Components = decodeComponents(body.Data.Components)
+		response.Data = &data
+	}
+	s.interactionResponses = append(s.interactionResponses, response)
+
+	// Replies and message updates show up in the channel like they do on Discord
+	var event string
+	var msg *discordgo.Message
+	switch {
+	case body.Type == discordgo.InteractionResponseChannelMessageWithSource && response.Data != nil:
+		msg = s.storeMessage(&discordgo.Message{
+			ChannelID:  interaction.ChannelID,
+			Author:     s.bot,
+			Content:    response.Data.Content,
+			Embeds:     response.Data.Embeds,
+			Components: response.Data.Components,
+			Flags:      response.Data.Flags,
+		})
+		s.originals[interaction.ID] = msg.ID
+		event = "MESSAGE_CREATE"
+	case body.Type == discordgo.InteractionResponseUpdateMessage && response.Data != nil && interaction.Message != nil:
+		if original, exists := s.messages[interaction.Message.ID]; exists {
+			if response.Data.Content != "" {
+				original.Content = response.Data.Content
+			}
+			if response.Data.Embeds != nil {
+				original.Embeds = response.Data.Embeds
+			}
+			if response.Data.Components != nil {
+				original.Components = response.Data.Components
+			}
+			msg = s.render(original)
+			event = "MESSAGE_UPDATE"
+		}
+	}
+	s.mu.Unlock()
+
+	if msg != nil {
+		s.Dispatch(event, msg)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getInteractionResponse(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, interaction := range s.interactions {
+		if interaction.Token != r.PathValue("token") {
+			continue
+		}
+		if msg, exists := s.messages[s.originals[id]]; exists {
+			writeJSON(w, http.StatusOK, s.render(msg))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+}
+
+// editInteractionResponse edits an interaction reply; the first edit of a deferred
+// reply posts it, as the "thinking" message turns into the reply on Discord
+func (s *Server) editInteractionResponse(w http.ResponseWriter, r *http.Request) {
+	var body messageBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	var interaction *discordgo.Interaction
+	for _, candidate := range s.interactions {
+		if candidate.Token == r.PathValue("token") {
+			interaction = candidate
+		}
+	}
+	if interaction == nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownInteraction, "Unknown interaction")
+		return
+	}
+
+	event := "MESSAGE_UPDATE"
+	msg, exists := s.messages[s.originals[interaction.ID]]
+	if !exists {
+		msg = &discordgo.Message{ChannelID: interaction.ChannelID, Author: s.bot}
+		event = "MESSAGE_CREATE"
+	}
+	if body.Content != nil {
+		msg.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		msg.Embeds = *body.Embeds
+	}
+	if body.Components != nil {
+		msg.Components = decodeComponents(body.Components)
+	}
+	var edited *discordgo.Message
+	if exists {
+		edited = s.render(msg)
+	} else {
+		edited = s.storeMessage(msg)
+		s.originals[interaction.ID] = edited.ID
+	}
+	s.mu.Unlock()
+
+	s.Dispatch(event, edited)
+	writeJSON(w, http.StatusOK, edited)
+}
+
+func (s *Server) overwriteCommands(w http.ResponseWriter, r *http.Request) {
+	var commands []*discordgo.ApplicationCommand
+	if err := json.NewDecoder(r.Body).Decode(&commands); err != nil {
+		writeError(w, http.StatusBadRequest, 50035, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, command := range commands {
+		command.ID = s.newID()
+		command.ApplicationID = r.PathValue("app")
+		command.GuildID = r.PathValue("guild")
+	}
+	s.commands = commands
+	writeJSON(w, http.StatusOK, commands)
+}
@@ -0,0 +1,410 @@
+// Package discordtest runs a local stand-in for the parts of the Discord REST
+// API and gateway that Askeladden uses, so a real discordgo session can be
+// driven end to end without a bot token.
+//
+// NewServer points discordgo's endpoint variables at the server for the
+// duration of a test. Seed the guild with AddChannel, AddUser, AddMember and
+// AddRole, open a session with the server's Token, then script the guild with
+// SendMessage, React, Unreact and Interact and inspect what the bot did.
+package discordtest
+
+import (
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// Token is the bot token the server accepts
+const Token = "test-token"
+
+// BotID is the user ID of the bot account
+const BotID = "100"
+
+// RoleChange records a role added to or removed from a member
+type RoleChange struct {
+	GuildID string
+	UserID  string
+	RoleID  string
+	Added   bool
+}
+
+// InteractionResponse records the bot's response to an interaction
+type InteractionResponse struct {
+	InteractionID string
+	Type          discordgo.InteractionResponseType
+	Data          *discordgo.InteractionResponseData
+}
+
+// Server emulates Discord for one bot account in one guild.
+// All methods are safe for concurrent use.
+type Server struct {
+	GuildID string
+
+	mu sync.Mutex
+
+	http     *httptest.Server
+	upgrader websocket.Upgrader
+	conn     *websocket.Conn
+	connMu   sync.Mutex // serialises writes to conn
+	sequence int64
+	intents  discordgo.Intent
+
+	nextID   int64
+	bot      *discordgo.User
+	users    map[string]*discordgo.User
+	members  map[string]*discordgo.Member
+	roles    []*discordgo.Role
+	channels map[string]*discordgo.Channel
+	dms      map[string]string // user ID -> DM channel ID
+
+	messages      map[string]*discordgo.Message
+	messageOrder  []string
+	reactions     map[string]map[string][]string // message ID -> emoji -> user IDs
+	reactionOrder map[string][]string            // message ID -> emojis in first-use order
+
+	interactions         map[string]*discordgo.Interaction
+	interactionResponses []InteractionResponse
+	originals            map[string]string // interaction ID -> response message ID
+	commands             []*discordgo.ApplicationCommand
+	roleChanges          []RoleChange
+	threads              []*discordgo.Channel
+}
+
+// NewServer starts a server and points discordgo at it until the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		GuildID:       "1",
+		nextID:        1000,
+		bot:           &discordgo.User{ID: BotID, Username: "Askeladden", Bot: true},
+		users:         make(map[string]*discordgo.User),
+		members:       make(map[string]*discordgo.Member),
+		channels:      make(map[string]*discordgo.Channel),
+		dms:           make(map[string]string),
+		messages:      make(map[string]*discordgo.Message),
+		reactions:     make(map[string]map[string][]string),
+		reactionOrder: make(map[string][]string),
+		interactions:  make(map[string]*discordgo.Interaction),
+		originals:     make(map[string]string),
+	}
+	s.users[BotID] = s.bot
+	s.members[BotID] = &discordgo.Member{GuildID: s.GuildID, User: s.bot}
+
+	s.http = httptest.NewServer(s.routes())
+	restore := useEndpoints(s.http.URL + "/")
+	t.Cleanup(func() {
+		s.closeGateway()
+		s.http.Close()
+		restore()
+	})
+	return s
+}
+
+// URL returns the server's base URL
+func (s *Server) URL() string {
+	return s.http.URL
+}
+
+// Bot returns the bot's user
+func (s *Server) Bot() *discordgo.User {
+	return s.bot
+}
+
+// useEndpoints points discordgo's API endpoints at base and returns a function restoring them
+func useEndpoints(base string) func() {
+	saved := []string{
+		discordgo.EndpointDiscord, discordgo.EndpointAPI, discordgo.EndpointGuilds, discordgo.EndpointChannels,
+		discordgo.EndpointUsers, discordgo.EndpointGateway, discordgo.EndpointGatewayBot, discordgo.EndpointWebhooks,
+		discordgo.EndpointApplications, discordgo.EndpointGuildCreate,
+	}
+
+	discordgo.EndpointDiscord = base
+	discordgo.EndpointAPI = base + "api/v" + discordgo.APIVersion + "/"
+	discordgo.EndpointGuilds = discordgo.EndpointAPI + "guilds/"
+	discordgo.EndpointChannels = discordgo.EndpointAPI + "channels/"
+	discordgo.EndpointUsers = discordgo.EndpointAPI + "users/"
+	discordgo.EndpointGateway = discordgo.EndpointAPI + "gateway"
+	discordgo.EndpointGatewayBot = discordgo.EndpointGateway + "/bot"
+	discordgo.EndpointWebhooks = discordgo.EndpointAPI + "webhooks/"
+	discordgo.EndpointApplications = discordgo.EndpointAPI + "applications"
+	discordgo.EndpointGuildCreate = discordgo.EndpointAPI + "guilds"
+
+	return func() {
+		discordgo.EndpointDiscord, discordgo.EndpointAPI, discordgo.EndpointGuilds, discordgo.EndpointChannels,
+			discordgo.EndpointUsers, discordgo.EndpointGateway, discordgo.EndpointGatewayBot, discordgo.EndpointWebhooks,
+			discordgo.EndpointApplications, discordgo.EndpointGuildCreate =
+			saved[0], saved[1], saved[2], saved[3], saved[4], saved[5], saved[6], saved[7], saved[8], saved[9]
+	}
+}
+
+// newID returns a fresh snowflake. Callers must hold s.mu.
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.FormatInt(s.nextID, 10)
+}
+
+// AddChannel creates a guild text channel
+func (s *Server) AddChannel(id, name string) *discordgo.Channel {
+	return s.addChannel(&discordgo.Channel{ID: id, GuildID: s.GuildID, Name: name, Type: discordgo.ChannelTypeGuildText})
+}
+
+// AddForum creates a guild forum channel
+func (s *Server) AddForum(id, name string) *discordgo.Channel {
+	return s.addChannel(&discordgo.Channel{ID: id, GuildID: s.GuildID, Name: name, Type: discordgo.ChannelTypeGuildForum})
+}
+
+func (s *Server) addChannel(channel *discordgo.Channel) *discordgo.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels[channel.ID] = channel
+	return channel
+}
+
+// AddUser creates a user who is not a guild member
+func (s *Server) AddUser(id, username string) *discordgo.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user := &discordgo.User{ID: id, Username: username}
+	s.users[id] = user
+	return user
+}
+
+// AddMember creates a user and makes them a guild member with the given roles
+func (s *Server) AddMember(id, username string, roleIDs ...string) *discordgo.Member {
+	user := s.AddUser(id, username)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	member := &discordgo.Member{GuildID: s.GuildID, User: user, Roles: append([]string(nil), roleIDs...)}
+	s.members[id] = member
+	return member
+}
+
+// AddRole creates a guild role
+func (s *Server) AddRole(id, name string, position int) *discordgo.Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	role := &discordgo.Role{ID: id, Name: name, Position: position}
+	s.roles = append(s.roles, role)
+	return role
+}
+
+// SetBotRoles gives the bot's member the given roles.
+// Like Discord, the server only lets the bot add or remove roles below its highest role.
+func (s *Server) SetBotRoles(roleIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members[BotID].Roles = append([]string(nil), roleIDs...)
+}
+
+// Member returns a copy of a guild member
+func (s *Server) Member(userID string) *discordgo.Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	member, exists := s.members[userID]
+	if !exists {
+		return nil
+	}
+	copied := *member
+	copied.Roles = append([]string(nil), member.Roles...)
+	return &copied
+}
+
+// Messages returns copies of the messages in a channel, oldest first
+func (s *Server) Messages(channelID string) []*discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []*discordgo.Message
+	for _, id := range s.messageOrder {
+		if msg, exists := s.messages[id]; exists && msg.ChannelID == channelID {
+			messages = append(messages, s.render(msg))
+		}
+	}
+	return messages
+}
+
+// Message returns a copy of a message, or nil if it does not exist
+func (s *Server) Message(messageID string) *discordgo.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, exists := s.messages[messageID]
+	if !exists {
+		return nil
+	}
+	return s.render(msg)
+}
+
+// DMChannel returns the ID of the DM channel with a user, or "" if the bot never opened one
+func (s *Server) DMChannel(userID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dms[userID]
+}
+
+// Reactions returns the IDs of the users who reacted to a message with emoji
+func (s *Server) Reactions(messageID, emoji string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.reactions[messageID][emoji]...)
+}
+
+// RoleChanges returns every role change the bot made
+func (s *Server) RoleChanges() []RoleChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RoleChange(nil), s.roleChanges...)
+}
+
+// Threads returns every thread the bot started
+func (s *Server) Threads() []*discordgo.Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.Channel(nil), s.threads...)
+}
+
+// InteractionResponses returns every interaction response the bot sent
+func (s *Server) InteractionResponses() []InteractionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]InteractionResponse(nil), s.interactionResponses...)
+}
+
+// Commands returns the application commands the bot registered
+func (s *Server) Commands() []*discordgo.ApplicationCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*discordgo.ApplicationCommand(nil), s.commands...)
+}
+
+// Intents returns the intents the bot identified with
+func (s *Server) Intents() discordgo.Intent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.intents
+}
+
+// WaitFor polls cond until it returns true, failing the test after five seconds.
+// The bot handles gateway events asynchronously, so scripted steps wait for
+// their effects rather than assuming them.
+func (s *Server) WaitFor(t testing.TB, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// WaitForMessage waits for a message in channelID that matches and returns it
+func (s *Server) WaitForMessage(t testing.TB, channelID string, match func(*discordgo.Message) bool) *discordgo.Message {
+	t.Helper()
+	var found *discordgo.Message
+	s.WaitFor(t, "message in channel "+channelID, func() bool {
+		for _, msg := range s.Messages(channelID) {
+			if match(msg) {
+				found = msg
+				return true
+			}
+		}
+		return false
+	})
+	return found
+}
+
+// HasEmbedTitle matches messages with an embed whose title contains title
+func HasEmbedTitle(title string) func(*discordgo.Message) bool {
+	return func(msg *discordgo.Message) bool {
+		for _, embed := range msg.Embeds {
+			if strings.Contains(embed.Title, title) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// storeMessage saves a message and returns a rendered copy. Callers must hold s.mu.
+func (s *Server) storeMessage(msg *discordgo.Message) *discordgo.Message {
+	if msg.ID == "" {
+		msg.ID = s.newID()
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	if channel, exists := s.channels[msg.ChannelID]; exists && msg.GuildID == "" {
+		msg.GuildID = channel.GuildID
+	}
+	s.messages[msg.ID] = msg
+	s.messageOrder = append(s.messageOrder, msg.ID)
+	return s.render(msg)
+}
+
+// render returns a copy of a stored message with its reaction counts filled in.
+// Callers must hold s.mu.
+func (s *Server) render(msg *discordgo.Message) *discordgo.Message {
+	copied := *msg
+	copied.Reactions = nil
+	for _, emoji := range s.reactionOrder[msg.ID] {
+		users := s.reactions[msg.ID][emoji]
+		if len(users) == 0 {
+			continue
+		}
+		me := false
+		for _, id := range users {
+			if id == BotID {
+				me = true
+			}
+		}
+		copied.Reactions = append(copied.Reactions, &discordgo.MessageReactions{
+			Count: len(users),
+			Me:    me,
+			Emoji: &discordgo.Emoji{Name: emoji},
+		})
+	}
+	return &copied
+}
+
+// addReaction records a reaction and reports whether it is new. Callers must hold s.mu.
+func (s *Server) addReaction(messageID, emoji, userID string) bool {
+	if s.reactions[messageID] == nil {
+		s.reactions[messageID] = make(map[string][]string)
+	}
+	for _, id := range s.reactions[messageID][emoji] {
+		if id == userID {
+			return false
+		}
+	}
+	if _, seen := s.reactions[messageID][emoji]; !seen {
+		s.reactionOrder[messageID] = append(s.reactionOrder[messageID], emoji)
+	}
+	s.reactions[messageID][emoji] = append(s.reactions[messageID][emoji], userID)
+	return true
+}
+
+// removeReaction removes a reaction and reports whether it existed. Callers must hold s.mu.
+func (s *Server) removeReaction(messageID, emoji, userID string) bool {
+	users := s.reactions[messageID][emoji]
+	for i, id := range users {
+		if id == userID {
+			s.reactions[messageID][emoji] = append(users[:i:i], users[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// sortedRoles returns the guild roles ordered by position. Callers must hold s.mu.
+func (s *Server) sortedRoles() []*discordgo.Role {
+	roles := append([]*discordgo.Role(nil), s.roles...)
+	sort.SliceStable(roles, func(i, j int) bool { return roles[i].Position < roles[j].Position })
+	return roles
+}
@@ -0,0 +1,599 @@
+// Package fake provides an in-memory Discord client that records every call,
+// so handlers, commands and services can be tested without a Discord connection.
+package fake
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"askeladden/internal/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Edit records a message edit
+type Edit struct {
+	ChannelID  string
+	MessageID  string
+	Embeds     []*discordgo.MessageEmbed
+	Components []discordgo.MessageComponent
+	Content    *string
+}
+
+// Deletion records a deleted message
+type Deletion struct {
+	ChannelID string
+	MessageID string
+}
+
+// Reaction records a reaction added by the bot
+type Reaction struct {
+	ChannelID string
+	MessageID string
+	Emoji     string
+}
+
+// RoleChange records a role added to or removed from a member
+type RoleChange struct {
+	GuildID string
+	UserID  string
+	RoleID  string
+	Added   bool
+}
+
+// InteractionResponse records a response to an interaction
+type InteractionResponse struct {
+	Interaction *discordgo.Interaction
+	Response    *discordgo.InteractionResponse
+}
+
+// Client is a recording implementation of discord.Client.
+// Seed it with users, members, roles, channels and messages, run the code
+// under test, then inspect the recorded fields. All methods are safe for
+// concurrent use; read the recorded fields only after the code under test is done.
+type Client struct {
+	mu sync.Mutex
+
+	Bot    *discordgo.User
+	Guilds int
+
+	Users    map[string]*discordgo.User
+	Members  map[string]map[string]*discordgo.Member // guild ID -> user ID -> member
+	Roles    map[string][]*discordgo.Role            // guild ID -> roles
+	Channels map[string]*discordgo.Channel
+	Messages map[string]*discordgo.Message // message ID -> message, including messages sent by the bot
+	// ReactionUsers holds who reacted to a message: message ID -> emoji -> users
+	ReactionUsers map[string]map[string][]*discordgo.User
+
+	// Recorded calls
+	Sent                 []*discordgo.Message
+	Edits                []Edit
+	Deletions            []Deletion
+	Reactions            []Reaction
+	RoleChanges          []RoleChange
+	Threads              []*discordgo.Channel
+	InteractionResponses []InteractionResponse
+	// ApplicationCommands holds the commands from the last bulk overwrite
+	ApplicationCommands []*discordgo.ApplicationCommand
+
+	// Errors makes the named method fail, e.g. Errors["GuildMemberRoleAdd"] = errors.New("missing permissions")
+	Errors map[string]error
+
+	nextID    int
+	originals map[string]string // interaction ID -> response message ID
+}
+
+// Client implements the discord.Client interface
+var _ discord.Client = (*Client)(nil)
+
+// New creates an empty fake client whose bot user has the given ID
+func New(botID string) *Client {
+	bot := &discordgo.User{ID: botID, Username: "Askeladden", Bot: true}
+	return &Client{
+		Bot:           bot,
+		Users:         map[string]*discordgo.User{botID: bot},
+		Members:       make(map[string]map[string]*discordgo.Member),
+		Roles:         make(map[string][]*discordgo.Role),
+		Channels:      make(map[string]*discordgo.Channel),
+		Messages:      make(map[string]*discordgo.Message),
+		ReactionUsers: make(map[string]map[string][]*discordgo.User),
+		Errors:        make(map[string]error),
+		nextID:        1000,
+		originals:     make(map[string]string),
+	}
+}
+
+// AddUser registers a user and returns it
+func (c *Client) AddUser(id, username string) *discordgo.User {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	user := &discordgo.User{ID: id, Username: username}
+	c.Users[id] = user
+	return user
+}
+
+// AddMember registers a guild member with the given roles
+func (c *Client) AddMember(guildID, userID string, roleIDs ...string) *discordgo.Member {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	user, exists := c.Users[userID]
+	if !exists {
+		user = &discordgo.User{ID: userID, Username: userID}
+		c.Users[userID] = user
+	}
+	if c.Members[guildID] == nil {
+		c.Members[guildID] = make(map[string]*discordgo.Member)
+	}
+	member := &discordgo.Member{GuildID: guildID, User: user, Roles: append([]string(nil), roleIDs...)}
+	c.Members[guildID][userID] = member
+	return member
+}
+
+// AddRole registers a guild role
+func (c *Client) AddRole(guildID, roleID, name string, position int) *discordgo.Role {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	role := &discordgo.Role{ID: roleID, Name: name, Position: position}
+	c.Roles[guildID] = append(c.Roles[guildID], role)
+	return role
+}
+
+// AddChannel registers a guild channel
+func (c *Client) AddChannel(guildID, channelID, name string) *discordgo.Channel {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	channel := &discordgo.Channel{ID: channelID, GuildID: guildID, Name: name}
+	c.Channels[channelID] = channel
+	return channel
+}
+
+// AddMessage stores a message so it can be fetched with ChannelMessage
+func (c *Client) AddMessage(msg *discordgo.Message) *discordgo.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Messages[msg.ID] = msg
+	return msg
+}
+
+// React records that user reacted to a message with emoji, updating the message's reaction counts
+func (c *Client) React(messageID, emoji string, user *discordgo.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.react(messageID, emoji, user)
+}
+
+func (c *Client) react(messageID, emoji string, user *discordgo.User) {
+	if c.ReactionUsers[messageID] == nil {
+		c.ReactionUsers[messageID] = make(map[string][]*discordgo.User)
+	}
+	c.ReactionUsers[messageID][emoji] = append(c.ReactionUsers[messageID][emoji], user)
+
+	msg, exists := c.Messages[messageID]
+	if !exists {
+		return
+	}
+	for _, r := range msg.Reactions {
+		if r.Emoji.Name == emoji {
+			r.Count++
+			return
+		}
+	}
+	msg.Reactions = append(msg.Reactions, &discordgo.MessageReactions{Count: 1, Emoji: &discordgo.Emoji{Name: emoji}})
+}
+
+// SentTo returns the messages the bot sent to a channel, in order
+func (c *Client) SentTo(channelID string) []*discordgo.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var messages []*discordgo.Message
+	for _, msg := range c.Sent {
+		if msg.ChannelID == channelID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// failure returns the configured error for a method, if any
+func (c *Client) failure(method string) error {
+	return c.Errors[method]
+}
+
+func (c *Client) newID() string {
+	c.nextID++
+	return strconv.Itoa(c.nextID)
+}
+
+func notFound(kind, id string) error {
+	return fmt.Errorf("fake: unknown %s %q", kind, id)
+}
+
+// BotUser returns the bot user
+func (c *Client) BotUser() *discordgo.User {
+	return c.Bot
+}
+
+// GuildCount returns the configured guild count
+func (c *Client) GuildCount() int {
+	return c.Guilds
+}
+
+// ChannelMessage returns a stored message
+func (c *Client) ChannelMessage(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChannelMessage"); err != nil {
+		return nil, err
+	}
+	msg, exists := c.Messages[messageID]
+	if !exists || msg.ChannelID != channelID {
+		return nil, notFound("message", messageID)
+	}
+	return msg, nil
+}
+
+// ChannelMessageSend records a plain text message
+func (c *Client) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return c.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content})
+}
+
+// ChannelMessageSendEmbed records an embed message
+func (c *Client) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	return c.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}})
+}
+
+// ChannelMessageSendComplex records a message and stores it for later lookups
+func (c *Client) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChannelMessageSend"); err != nil {
+		return nil, err
+	}
+	return c.send(channelID, data), nil
+}
+
+// send stores a message from the bot. Callers must hold c.mu.
+func (c *Client) send(channelID string, data *discordgo.MessageSend) *discordgo.Message {
+	embeds := append([]*discordgo.MessageEmbed(nil), data.Embeds...)
+	if data.Embed != nil {
+		embeds = append(embeds, data.Embed)
+	}
+	msg := &discordgo.Message{
+		ID:         c.newID(),
+		ChannelID:  channelID,
+		Content:    data.Content,
+		Embeds:     embeds,
+		Components: data.Components,
+		Author:     c.Bot,
+		Timestamp:  time.Now(),
+	}
+	if channel, exists := c.Channels[channelID]; exists {
+		msg.GuildID = channel.GuildID
+	}
+	if data.Reference != nil {
+		msg.MessageReference = data.Reference
+		msg.ReferencedMessage = c.Messages[data.Reference.MessageID]
+	}
+	// Sent keeps the message as it was sent; later edits only change the stored copy
+	sent := *msg
+	c.Sent = append(c.Sent, &sent)
+	c.Messages[msg.ID] = msg
+	return msg
+}
+
+// ChannelMessageEditEmbed records an embed edit
+func (c *Client) ChannelMessageEditEmbed(channelID, messageID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	embeds := []*discordgo.MessageEmbed{embed}
+	return c.ChannelMessageEditComplex(&discordgo.MessageEdit{Channel: channelID, ID: messageID, Embeds: &embeds})
+}
+
+// ChannelMessageEditComplex records an edit and applies it to the stored message
+func (c *Client) ChannelMessageEditComplex(m *discordgo.MessageEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChannelMessageEdit"); err != nil {
+		return nil, err
+	}
+
+	edit := Edit{ChannelID: m.Channel, MessageID: m.ID, Content: m.Content}
+	if m.Embeds != nil {
+		edit.Embeds = *m.Embeds
+	}
+	if m.Components != nil {
+		edit.Components = *m.Components
+	}
+	c.Edits = append(c.Edits, edit)
+
+	msg, exists := c.Messages[m.ID]
+	if !exists {
+		msg = &discordgo.Message{ID: m.ID, ChannelID: m.Channel, Author: c.Bot}
+		c.Messages[m.ID] = msg
+	}
+	if m.Content != nil {
+		msg.Content = *m.Content
+	}
+	if m.Embeds != nil {
+		msg.Embeds = *m.Embeds
+	}
+	if m.Components != nil {
+		msg.Components = *m.Components
+	}
+	return msg, nil
+}
+
+// ChannelMessageDelete records a deletion and forgets the stored message
+func (c *Client) ChannelMessageDelete(channelID, messageID string, options ...discordgo.RequestOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChannelMessageDelete"); err != nil {
+		return err
+	}
+	c.Deletions = append(c.Deletions, Deletion{ChannelID: channelID, MessageID: messageID})
+	delete(c.Messages, messageID)
+	return nil
+}
+
+// MessageReactionAdd records a reaction added by the bot
+func (c *Client) MessageReactionAdd(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("MessageReactionAdd"); err != nil {
+		return err
+	}
+	c.Reactions = append(c.Reactions, Reaction{ChannelID: channelID, MessageID: messageID, Emoji: emojiID})
+	c.react(messageID, emojiID, c.Bot)
+	return nil
+}
+
+// MessageReactions returns the users who reacted with an emoji
+func (c *Client) MessageReactions(channelID, messageID, emojiID string, limit int, beforeID, afterID string, options ...discordgo.RequestOption) ([]*discordgo.User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("MessageReactions"); err != nil {
+		return nil, err
+	}
+	users := c.ReactionUsers[messageID][emojiID]
+	if limit > 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return append([]*discordgo.User(nil), users...), nil
+}
+
+// Channel returns a registered channel
+func (c *Client) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("Channel"); err != nil {
+		return nil, err
+	}
+	channel, exists := c.Channels[channelID]
+	if !exists {
+		return nil, notFound("channel", channelID)
+	}
+	return channel, nil
+}
+
+// UserChannelCreate returns a DM channel with ID "dm-<userID>"
+func (c *Client) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("UserChannelCreate"); err != nil {
+		return nil, err
+	}
+	id := "dm-" + recipientID
+	channel, exists := c.Channels[id]
+	if !exists {
+		channel = &discordgo.Channel{ID: id, Type: discordgo.ChannelTypeDM}
+		c.Channels[id] = channel
+	}
+	return channel, nil
+}
+
+// ChannelEdit applies the name, archived and locked settings to a registered channel
+func (c *Client) ChannelEdit(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChannelEdit"); err != nil {
+		return nil, err
+	}
+	channel, exists := c.Channels[channelID]
+	if !exists {
+		return nil, notFound("channel", channelID)
+	}
+	if data.Name != "" {
+		channel.Name = data.Name
+	}
+	if data.Archived != nil || data.Locked != nil {
+		if channel.ThreadMetadata == nil {
+			channel.ThreadMetadata = &discordgo.ThreadMetadata{}
+		}
+		if data.Archived != nil {
+			channel.ThreadMetadata.Archived = *data.Archived
+		}
+		if data.Locked != nil {
+			channel.ThreadMetadata.Locked = *data.Locked
+		}
+	}
+	return channel, nil
+}
+
+// ForumThreadStart records a new forum thread
+func (c *Client) ForumThreadStart(channelID, name string, archiveDuration int, content string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ForumThreadStart"); err != nil {
+		return nil, err
+	}
+	thread := &discordgo.Channel{
+		ID:       c.newID(),
+		ParentID: channelID,
+		Name:     name,
+		Type:     discordgo.ChannelTypeGuildPublicThread,
+	}
+	if parent, exists := c.Channels[channelID]; exists {
+		thread.GuildID = parent.GuildID
+	}
+	c.Threads = append(c.Threads, thread)
+	c.Channels[thread.ID] = thread
+	return thread, nil
+}
+
+// User returns a registered user; "@me" is the bot
+func (c *Client) User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("User"); err != nil {
+		return nil, err
+	}
+	if userID == "@me" {
+		return c.Bot, nil
+	}
+	user, exists := c.Users[userID]
+	if !exists {
+		return nil, notFound("user", userID)
+	}
+	return user, nil
+}
+
+// GuildMember returns a registered member
+func (c *Client) GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GuildMember"); err != nil {
+		return nil, err
+	}
+	member, exists := c.Members[guildID][userID]
+	if !exists {
+		return nil, notFound("member", userID)
+	}
+	return member, nil
+}
+
+// GuildRoles returns the registered roles of a guild
+func (c *Client) GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GuildRoles"); err != nil {
+		return nil, err
+	}
+	return append([]*discordgo.Role(nil), c.Roles[guildID]...), nil
+}
+
+// GuildMemberRoleAdd records a role change and gives the member the role
+func (c *Client) GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GuildMemberRoleAdd"); err != nil {
+		return err
+	}
+	c.RoleChanges = append(c.RoleChanges, RoleChange{GuildID: guildID, UserID: userID, RoleID: roleID, Added: true})
+	if member, exists := c.Members[guildID][userID]; exists {
+		member.Roles = append(member.Roles, roleID)
+	}
+	return nil
+}
+
+// GuildMemberRoleRemove records a role change and removes the role from the member
+func (c *Client) GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("GuildMemberRoleRemove"); err != nil {
+		return err
+	}
+	c.RoleChanges = append(c.RoleChanges, RoleChange{GuildID: guildID, UserID: userID, RoleID: roleID, Added: false})
+	if member, exists := c.Members[guildID][userID]; exists {
+		kept := member.Roles[:0]
+		for _, id := range member.Roles {
+			if id != roleID {
+				kept = append(kept, id)
+			}
+		}
+		member.Roles = kept
+	}
+	return nil
+}
+
+// InteractionRespond records an interaction response. Replies are stored as
+// bot messages in the interaction's channel, like Discord shows them.
+func (c *Client) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("InteractionRespond"); err != nil {
+		return err
+	}
+	c.InteractionResponses = append(c.InteractionResponses, InteractionResponse{Interaction: interaction, Response: resp})
+	if resp.Type == discordgo.InteractionResponseChannelMessageWithSource && resp.Data != nil {
+		msg := c.send(interaction.ChannelID, &discordgo.MessageSend{
+			Content:    resp.Data.Content,
+			Embeds:     resp.Data.Embeds,
+			Components: resp.Data.Components,
+		})
+		c.originals[interaction.ID] = msg.ID
+	}
+	return nil
+}
+
+// InteractionResponseEdit edits an interaction reply. The reply to a deferred
+// interaction is sent by its first edit.
+func (c *Client) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("InteractionResponseEdit"); err != nil {
+		return nil, err
+	}
+	msg, exists := c.Messages[c.originals[interaction.ID]]
+	if !exists {
+		data := &discordgo.MessageSend{}
+		if newresp.Content != nil {
+			data.Content = *newresp.Content
+		}
+		if newresp.Embeds != nil {
+			data.Embeds = *newresp.Embeds
+		}
+		if newresp.Components != nil {
+			data.Components = *newresp.Components
+		}
+		msg = c.send(interaction.ChannelID, data)
+		c.originals[interaction.ID] = msg.ID
+		return msg, nil
+	}
+
+	edit := Edit{ChannelID: msg.ChannelID, MessageID: msg.ID, Content: newresp.Content}
+	if newresp.Content != nil {
+		msg.Content = *newresp.Content
+	}
+	if newresp.Embeds != nil {
+		msg.Embeds, edit.Embeds = *newresp.Embeds, *newresp.Embeds
+	}
+	if newresp.Components != nil {
+		msg.Components, edit.Components = *newresp.Components, *newresp.Components
+	}
+	c.Edits = append(c.Edits, edit)
+	return msg, nil
+}
+
+// InteractionResponse returns the message created by an interaction reply
+func (c *Client) InteractionResponse(interaction *discordgo.Interaction, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("InteractionResponse"); err != nil {
+		return nil, err
+	}
+	msg, exists := c.Messages[c.originals[interaction.ID]]
+	if !exists {
+		return nil, notFound("interaction response", interaction.ID)
+	}
+	return msg, nil
+}
+
+// ApplicationCommandBulkOverwrite records the registered application commands
+func (c *Client) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ApplicationCommandBulkOverwrite"); err != nil {
+		return nil, err
+	}
+	c.ApplicationCommands = commands
+	return commands, nil
+}
@@ -6,7 +6,8 @@ import (
 	"strings"
 
 	"askeladden/internal/config"
-	"github.com/bwmarrin/discordgo"
+	"askeladden/internal/database"
+	"askeladden/internal/discord"
 )
 
 // PermissionManager handles role-based permissions and approvals
@@ -32,7 +33,7 @@ const (
 )
 
 // GetUserRole returns the user's role(s)
-func (pm *PermissionManager) GetUserRole(s *discordgo.Session, guildID, userID string) UserRole {
+func (pm *PermissionManager) GetUserRole(s discord.Client, guildID, userID string) UserRole {
 	member, err := s.GuildMember(guildID, userID)
 	if err != nil {
 		log.Printf("Failed to get guild member: %v", err)
@@ -63,13 +64,13 @@ func (pm *PermissionManager) GetUserRole(s *discordgo.Session, guildID, userID s
 }
 
 // HasOpplysarRole checks if user has opplysar role
-func (pm *PermissionManager) HasOpplysarRole(s *discordgo.Session, guildID, userID string) bool {
+func (pm *PermissionManager) HasOpplysarRole(s discord.Client, guildID, userID string) bool {
 	role := pm.GetUserRole(s, guildID, userID)
 	return role == RoleOpplysar || role == RoleBoth
 }
 
 // HasRettskrivarRole checks if user has rettskrivar role
-func (pm *PermissionManager) HasRettskrivarRole(s *discordgo.Session, guildID, userID string) bool {
+func (pm *PermissionManager) HasRettskrivarRole(s discord.Client, guildID, userID string) bool {
 	role := pm.GetUserRole(s, guildID, userID)
 	return role == RoleRettskrivar || role == RoleBoth
 }
@@ -82,51 +83,47 @@ type ApprovalState struct {
 	RettskrivarApprovers   []string
 }
 
-// CheckCombinedApproval checks all reactions on a message to see if both roles are represented
-func (pm *PermissionManager) CheckCombinedApproval(s *discordgo.Session, channelID, messageID, emoji string) (*ApprovalState, error) {
-	// Get all users who reacted with the approval emoji
-	users, err := s.MessageReactions(channelID, messageID, emoji, 100, "", "")
-	if err != nil {
-		return nil, err
+// ApprovalRoles returns the approval roles a user with this role approves under
+func (r UserRole) ApprovalRoles() []string {
+	switch r {
+	case RoleOpplysar:
+		return []string{database.ApprovalRoleOpplysar}
+	case RoleRettskrivar:
+		return []string{database.ApprovalRoleRettskrivar}
+	case RoleBoth:
+		return []string{database.ApprovalRoleOpplysar, database.ApprovalRoleRettskrivar}
 	}
+	return nil
+}
 
+// CanHandle reports whether a user with this role may approve, reject or edit an entity.
+// Questions are handled by opplysarar; banned words by opplysarar and rettskrivarar.
+func (r UserRole) CanHandle(entityType string) bool {
+	if entityType == database.ApprovalEntityQuestion {
+		return r == RoleOpplysar || r == RoleBoth
+	}
+	return r != RoleNone
+}
+
+// NewApprovalState builds the combined approval state from stored approvals
+func NewApprovalState(approvals []*database.Approval) *ApprovalState {
 	state := &ApprovalState{
 		OpplysarApprovers:    make([]string, 0),
 		RettskrivarApprovers: make([]string, 0),
 	}
 
-	// Get guild ID from channel
-	channel, err := s.Channel(channelID)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check each user's roles
-	for _, user := range users {
-		// Skip bot users
-		if user.Bot {
-			continue
-		}
-
-		role := pm.GetUserRole(s, channel.GuildID, user.ID)
-
-		switch role {
-		case RoleOpplysar:
-			state.HasOpplysarApproval = true
-			state.OpplysarApprovers = append(state.OpplysarApprovers, user.ID)
-		case RoleRettskrivar:
-			state.HasRettskrivarApproval = true
-			state.RettskrivarApprovers = append(state.RettskrivarApprovers, user.ID)
-		case RoleBoth:
-			// User has both roles, count for both
+	for _, approval := range approvals {
+		switch approval.Role {
+		case database.ApprovalRoleOpplysar:
 			state.HasOpplysarApproval = true
+			state.OpplysarApprovers = append(state.OpplysarApprovers, approval.UserID)
+		case database.ApprovalRoleRettskrivar:
 			state.HasRettskrivarApproval = true
-			state.OpplysarApprovers = append(state.OpplysarApprovers, user.ID)
-			state.RettskrivarApprovers = append(state.RettskrivarApprovers, user.ID)
+			state.RettskrivarApprovers = append(state.RettskrivarApprovers, approval.UserID)
 		}
 	}
 
-	return state, nil
+	return state
 }
 
 // IsFullyApproved checks if both required roles have approved
@@ -135,7 +132,7 @@ func (state *ApprovalState) IsFullyApproved() bool {
 }
 
 // GetApprovalSummary returns a simple approval status summary
-func (state *ApprovalState) GetApprovalSummary(s *discordgo.Session) string {
+func (state *ApprovalState) GetApprovalSummary(s discord.Client) string {
 	var parts []string
 
 	// Show opplysar approvals
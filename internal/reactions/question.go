@@ -5,6 +5,7 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -14,7 +15,7 @@ func RegisterQuestionReaction(b *bot.Bot) {
 	Register(emoji, "Spør eit spørsmål.", handleQuestionReaction)
 }
 
-func handleQuestionReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd, bot *bot.Bot) {
+func handleQuestionReaction(s discord.Client, r *discordgo.MessageReactionAdd, bot *bot.Bot) {
 	// Fetch the message
 	msg, err := s.ChannelMessage(r.ChannelID, r.MessageID)
 	if err != nil {
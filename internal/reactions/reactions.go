@@ -2,6 +2,7 @@ package reactions
 
 import (
 	"askeladden/internal/bot"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -9,8 +10,8 @@ import (
 type Reaction struct {
 	emoji         string
 	description   string
-	handler       func(s *discordgo.Session, r *discordgo.MessageReactionAdd, b *bot.Bot)
-	removeHandler func(s *discordgo.Session, r *discordgo.MessageReactionRemove, b *bot.Bot)
+	handler       func(s discord.Client, r *discordgo.MessageReactionAdd, b *bot.Bot)
+	removeHandler func(s discord.Client, r *discordgo.MessageReactionRemove, b *bot.Bot)
 	adminOnly     bool
 }
 
@@ -18,7 +19,7 @@ type Reaction struct {
 var reactions = make(map[string]Reaction)
 
 // Register registers a new reaction handler.
-func Register(emoji string, description string, handler func(s *discordgo.Session, r *discordgo.MessageReactionAdd, b *bot.Bot)) Reaction {
+func Register(emoji string, description string, handler func(s discord.Client, r *discordgo.MessageReactionAdd, b *bot.Bot)) Reaction {
 	r := Reaction{
 		emoji:         emoji,
 		description:   description,
@@ -38,14 +39,14 @@ func (r Reaction) SetAdminOnly() Reaction {
 }
 
 // SetRemoveHandler adds a handler for when the reaction is removed.
-func (r Reaction) SetRemoveHandler(handler func(s *discordgo.Session, r *discordgo.MessageReactionRemove, b *bot.Bot)) Reaction {
+func (r Reaction) SetRemoveHandler(handler func(s discord.Client, r *discordgo.MessageReactionRemove, b *bot.Bot)) Reaction {
 	r.removeHandler = handler
 	reactions[r.emoji] = r // Update in map
 	return r
 }
 
 // MatchAndRunReaction finds and executes a reaction based on its emoji.
-func MatchAndRunReaction(emoji string, s *discordgo.Session, r *discordgo.MessageReactionAdd, b *bot.Bot) {
+func MatchAndRunReaction(emoji string, s discord.Client, r *discordgo.MessageReactionAdd, b *bot.Bot) {
 	if reaction, exists := reactions[emoji]; exists {
 		reaction.handler(s, r, b)
 		return
@@ -53,7 +54,7 @@ func MatchAndRunReaction(emoji string, s *discordgo.Session, r *discordgo.Messag
 }
 
 // MatchAndRunReactionRemove finds and executes a reaction removal handler based on its emoji.
-func MatchAndRunReactionRemove(emoji string, s *discordgo.Session, r *discordgo.MessageReactionRemove, b *bot.Bot) {
+func MatchAndRunReactionRemove(emoji string, s discord.Client, r *discordgo.MessageReactionRemove, b *bot.Bot) {
 	if reaction, exists := reactions[emoji]; exists && reaction.removeHandler != nil {
 		reaction.removeHandler(s, r, b)
 		return
@@ -76,9 +77,5 @@ func InitializeReactions(b *bot.Bot) {
 	// Register question reaction
 	RegisterQuestionReaction(b)
 
-	// Register approval reaction (static emoji)
-	Register("👍", "Godkjenn eit spørsmål.", handleApprovalReaction).SetAdminOnly()
-
-	// Register reject reaction (static emoji)
-	Register("👎", "Avvis eit spørsmål.", handleRejectReaction).SetAdminOnly()
+	// Approvals use the buttons on the approval posts, see services.HandleApprovalInteraction
 }
@@ -0,0 +1,130 @@
+package reactions
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"askeladden/internal/bot"
+	"askeladden/internal/config"
+	"askeladden/internal/database"
+	"askeladden/internal/discord/fake"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	testGuild     = "guild"
+	testChannel   = "general"
+	testStarboard = "starboard"
+	testMessage   = "original"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// newStarboardBot returns a bot with a starboard threshold of two stars
+func newStarboardBot(t *testing.T) (*bot.Bot, *fake.Client) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Starboard.ChannelID = testStarboard
+	cfg.Starboard.Emoji = "⭐"
+	cfg.Starboard.Threshold = 2
+
+	client := fake.New("bot")
+	client.AddChannel(testGuild, testChannel, "general")
+	client.AddChannel(testGuild, testStarboard, "stjernebrett")
+	client.AddMessage(&discordgo.Message{
+		ID:        testMessage,
+		ChannelID: testChannel,
+		GuildID:   testGuild,
+		Content:   "Dette er ei fin melding",
+		Author:    client.AddUser("author", "kari"),
+	})
+
+	b := bot.New(cfg, database.NewMemory(), nil)
+	b.Discord = client
+	return b, client
+}
+
+// setStars sets the star count on the original message
+func setStars(client *fake.Client, stars int) {
+	msg := client.Messages[testMessage]
+	msg.Reactions = nil
+	if stars > 0 {
+		msg.Reactions = []*discordgo.MessageReactions{{Count: stars, Emoji: &discordgo.Emoji{Name: "⭐"}}}
+	}
+}
+
+func TestStarboard(t *testing.T) {
+	// Each step sets the star count, fires a reaction event and checks the recorded calls
+	steps := []struct {
+		name          string
+		stars         int
+		remove        bool
+		userID        string
+		wantSent      int
+		wantEdits     int
+		wantDeletions int
+		wantPosted    bool
+	}{
+		{name: "below threshold", stars: 1, userID: "u1"},
+		{name: "bot reaction ignored", stars: 2, userID: "bot"},
+		{name: "reaches threshold", stars: 2, userID: "u2", wantSent: 1, wantPosted: true},
+		{name: "more stars edit the post", stars: 3, userID: "u3", wantSent: 1, wantEdits: 1, wantPosted: true},
+		{name: "drops below threshold", stars: 1, remove: true, userID: "u3", wantSent: 1, wantEdits: 1, wantDeletions: 1},
+	}
+
+	b, client := newStarboardBot(t)
+	for _, step := range steps {
+		setStars(client, step.stars)
+		if step.remove {
+			handleStarReactionRemove(client, &discordgo.MessageReactionRemove{MessageReaction: &discordgo.MessageReaction{
+				UserID: step.userID, MessageID: testMessage, ChannelID: testChannel, GuildID: testGuild, Emoji: discordgo.Emoji{Name: "⭐"},
+			}}, b)
+		} else {
+			handleStarReaction(client, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+				UserID: step.userID, MessageID: testMessage, ChannelID: testChannel, GuildID: testGuild, Emoji: discordgo.Emoji{Name: "⭐"},
+			}}, b)
+		}
+
+		if got := len(client.SentTo(testStarboard)); got != step.wantSent {
+			t.Fatalf("%s: starboard posts = %d, want %d", step.name, got, step.wantSent)
+		}
+		if len(client.Edits) != step.wantEdits || len(client.Deletions) != step.wantDeletions {
+			t.Fatalf("%s: edits = %d, deletions = %d; want %d, %d", step.name, len(client.Edits), len(client.Deletions), step.wantEdits, step.wantDeletions)
+		}
+
+		posted, err := b.Database.GetStarboardMessage(testMessage)
+		if err != nil {
+			t.Fatalf("%s: GetStarboardMessage: %v", step.name, err)
+		}
+		if (posted != "") != step.wantPosted {
+			t.Fatalf("%s: starboard mapping = %q, want posted: %v", step.name, posted, step.wantPosted)
+		}
+	}
+
+	post := client.SentTo(testStarboard)[0]
+	if footer := post.Embeds[0].Footer.Text; footer != "⭐ 2 | #general" {
+		t.Errorf("starboard footer = %q", footer)
+	}
+	if footer := client.Edits[0].Embeds[0].Footer.Text; footer != "⭐ 3 | #general" {
+		t.Errorf("edited footer = %q", footer)
+	}
+}
+
+func TestStarboardIgnoresStarboardChannel(t *testing.T) {
+	b, client := newStarboardBot(t)
+	setStars(client, 5)
+
+	handleStarReaction(client, &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+		UserID: "u1", MessageID: testMessage, ChannelID: testStarboard, GuildID: testGuild, Emoji: discordgo.Emoji{Name: "⭐"},
+	}}, b)
+
+	if len(client.Sent) != 0 {
+		t.Fatalf("reposted a message from the starboard channel")
+	}
+}
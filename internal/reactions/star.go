@@ -5,6 +5,7 @@ import (
 
 	"askeladden/internal/bot"
 	"askeladden/internal/bot/services"
+	"askeladden/internal/discord"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -14,8 +15,8 @@ func RegisterStarboardReaction(b *bot.Bot) {
 	Register(emoji, "Legg til ei melding på stjernebrettet", handleStarReaction).SetRemoveHandler(handleStarReactionRemove)
 }
 
-func handleStarReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd, b *bot.Bot) {
-	if r.UserID == s.State.User.ID { // Ignore bot's own reactions
+func handleStarReaction(s discord.Client, r *discordgo.MessageReactionAdd, b *bot.Bot) {
+	if discord.IsSelf(s, r.UserID) { // Ignore bot's own reactions
 		return
 	}
 	// Don't process reactions in the starboard channel itself
@@ -26,8 +27,8 @@ func handleStarReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd, b
 	handleStarboardUpdate(s, r.ChannelID, r.MessageID, r.GuildID, b)
 }
 
-func handleStarReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove, b *bot.Bot) {
-	if r.UserID == s.State.User.ID { // Ignore bot's own reactions
+func handleStarReactionRemove(s discord.Client, r *discordgo.MessageReactionRemove, b *bot.Bot) {
+	if discord.IsSelf(s, r.UserID) { // Ignore bot's own reactions
 		return
 	}
 	// Don't process reactions in the starboard channel itself
@@ -38,7 +39,7 @@ func handleStarReactionRemove(s *discordgo.Session, r *discordgo.MessageReaction
 	handleStarboardUpdate(s, r.ChannelID, r.MessageID, r.GuildID, b)
 }
 
-func handleStarboardUpdate(s *discordgo.Session, channelID, messageID, guildID string, b *bot.Bot) {
+func handleStarboardUpdate(s discord.Client, channelID, messageID, guildID string, b *bot.Bot) {
 	// Fetch message
 	msg, err := s.ChannelMessage(channelID, messageID)
 	if err != nil {
@@ -108,7 +109,7 @@ func handleStarboardUpdate(s *discordgo.Session, channelID, messageID, guildID s
 }
 
 // getChannelName returns the channel name for a given channel ID
-func getChannelName(s *discordgo.Session, channelID string) string {
+func getChannelName(s discord.Client, channelID string) string {
 	channel, err := s.Channel(channelID)
 	if err != nil {
 		return "unknown-channel"
@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression with the five standard fields: minute, hour,
+// day of month, month and day of week
+type Schedule struct {
+	expr    string
+	minute  bits
+	hour    bits
+	dom     bits
+	month   bits
+	dow     bits
+	anyDay  bool // day of month is *, so only day of week restricts the day
+	anyWeek bool // day of week is *, so only day of month restricts the day
+}
+
+// bits holds the allowed values of one field, bit n for value n
+type bits uint64
+
+func (b bits) has(n int) bool {
+	return b&(1<<uint(n)) != 0
+}
+
+// field is the valid range and names of one cron field
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minutt", min: 0, max: 59}
+	hourField   = field{name: "time", min: 0, max: 23}
+	domField    = field{name: "dag", min: 1, max: 31}
+	monthField  = field{name: "månad", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 for Sunday as well as 0
+	dowField = field{name: "vekedag", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors are the @-shorthands for common schedules
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression like "0 8 * * *" or "*/30 * * * mon-fri". Fields
+// take *, numbers, ranges (1-5), steps (*/15, 1-5/2), lists (1,15) and English month and
+// day names, and @daily, @hourly and the like stand in for a whole expression. When both
+// day of month and day of week are restricted, a day matching either one counts, as in cron.
+func ParseCron(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	fields := strings.Fields(expr)
+	if len(fields) == 1 {
+		if expanded, ok := descriptors[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(expanded)
+		}
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron-uttrykket %q må ha fem felt, har %d", expr, len(fields))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.dow.has(7) {
+		s.dow |= 1
+	}
+	s.anyDay = fields[2] == "*"
+	s.anyWeek = fields[4] == "*"
+	return s, nil
+}
+
+// MustParseCron is ParseCron for expressions known to be valid
+func MustParseCron(expr string) *Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first time after t that the schedule matches, in t's location.
+// Wall-clock times skipped by a daylight saving change are not run that day, and
+// times repeated by one run only once. It returns the zero time if nothing matches
+// within five years, e.g. for 30 February.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+	for !s.month.has(int(t.Month())) {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for !s.hour.has(t.Hour()) {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if next.Day() != t.Day() {
+			t = next
+			goto wrap
+		}
+		t = next
+	}
+	for !s.minute.has(t.Minute()) {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		if next.Hour() != t.Hour() {
+			t = next
+			goto wrap
+		}
+		t = next
+	}
+	return t
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom.has(t.Day())
+	dow := s.dow.has(int(t.Weekday()))
+	if s.anyDay || s.anyWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// parse reads one field: a comma-separated list of *, values or ranges, each with an optional step
+func (f field) parse(text string) (bits, error) {
+	var result bits
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("ugyldig steg %q i feltet %s", stepText, f.name)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangeText == "*":
+			low, high = f.min, f.max
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = f.value(lowText); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(highText); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("ugyldig intervall %q i feltet %s", rangeText, f.name)
+			}
+		default:
+			var err error
+			if low, err = f.value(rangeText); err != nil {
+				return 0, err
+			}
+			high = low
+			// "5/15" means every 15 from 5, as in cron
+			if hasStep {
+				high = f.max
+			}
+		}
+
+		for n := low; n <= high; n += step {
+			result |= 1 << uint(n)
+		}
+	}
+	return result, nil
+}
+
+// value reads one number or name of the field and checks its range
+func (f field) value(text string) (int, error) {
+	if n, ok := f.names[strings.ToLower(text)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("ugyldig verdi %q i feltet %s (%d–%d)", text, f.name, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 8 * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"x * * * *",
+		"@sometimes",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, oslo)
+	}
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 8 * * *", at(5, 17, 7, 59), at(5, 17, 8, 0)},
+		{"0 8 * * *", at(5, 17, 8, 0), at(5, 18, 8, 0)},
+		{"@daily", at(12, 31, 12, 0), time.Date(2025, 1, 1, 0, 0, 0, 0, oslo)},
+		{"*/30 * * * *", at(5, 17, 8, 10), at(5, 17, 8, 30)},
+		{"*/30 * * * *", at(5, 17, 8, 45), at(5, 17, 9, 0)},
+		{"15,45 9-10 * * *", at(5, 17, 9, 50), at(5, 17, 10, 15)},
+		{"5/20 * * * *", at(5, 17, 8, 30), at(5, 17, 8, 45)},
+		// 17 May 2024 is a Friday
+		{"0 9 * * mon-fri", at(5, 17, 10, 0), at(5, 20, 9, 0)},
+		{"0 9 * * 7", at(5, 17, 10, 0), at(5, 19, 9, 0)},
+		{"0 0 1 feb *", at(5, 17, 10, 0), time.Date(2025, 2, 1, 0, 0, 0, 0, oslo)},
+		// Day of month or day of week when both are given: the 20th or a Sunday
+		{"0 12 20 * sun", at(5, 17, 10, 0), at(5, 19, 12, 0)},
+		{"0 12 29 2 *", at(5, 17, 10, 0), time.Date(2028, 2, 29, 12, 0, 0, 0, oslo)},
+		// 02:30 does not exist on 31 March 2024 in Oslo, and happens twice on 27 October
+		{"30 2 * * *", at(3, 31, 1, 0), time.Date(2024, 4, 1, 2, 30, 0, 0, oslo)},
+		{"0 3 * * *", at(3, 31, 1, 0), at(3, 31, 3, 0)},
+		{"30 2 * * *", time.Date(2024, 10, 27, 2, 30, 0, 0, oslo), time.Date(2024, 10, 28, 2, 30, 0, 0, oslo)},
+	}
+	for _, tt := range tests {
+		got := MustParseCron(tt.expr).Next(tt.after)
+		if !got.Equal(tt.want) {
+			t.Errorf("%q after %s = %s, want %s", tt.expr, tt.after, got, tt.want)
+		}
+	}
+
+	if got := MustParseCron("0 0 30 2 *").Next(at(1, 1, 0, 0)); !got.IsZero() {
+		t.Errorf("30 February = %s, want never", got)
+	}
+}
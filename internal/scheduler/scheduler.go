@@ -0,0 +1,298 @@
+// Package scheduler runs named background jobs on cron schedules in a configured
+// timezone, remembering in the database when each job last ran.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"askeladden/internal/database"
+)
+
+// CatchUp is what a job does about runs missed while the bot was down
+type CatchUp int
+
+const (
+	SkipMissed    CatchUp = iota // wait for the next scheduled run
+	RunMissedOnce                // run once at startup if any run was missed
+)
+
+var (
+	ErrUnknownJob = errors.New("ukjend jobb")
+	ErrJobRunning = errors.New("jobben køyrer allereie")
+)
+
+// Job is a named task run on a schedule. Run should return early once ctx is done.
+type Job struct {
+	Name        string
+	Description string
+	Schedule    *Schedule
+	CatchUp     CatchUp
+	Run         func(ctx context.Context) error
+}
+
+// Status is what the scheduler knows about one job
+type Status struct {
+	Name        string
+	Description string
+	Schedule    string
+	LastRun     time.Time // zero if the job has never run
+	LastError   string
+	Next        time.Time
+	Running     bool
+}
+
+// Store persists when jobs last ran; database.DatabaseIface satisfies it
+type Store interface {
+	GetJobRuns() (map[string]*database.JobRun, error)
+	RecordJobRun(name string, at time.Time, runErr string) error
+}
+
+// entry is a registered job and its state; fields other than job are guarded by Scheduler.mu
+type entry struct {
+	job       Job
+	next      time.Time
+	lastRun   time.Time
+	lastError string
+	running   bool
+}
+
+// Scheduler runs registered jobs when they are due. Jobs run in their own goroutines,
+// but a job is never started again while it is still running.
+type Scheduler struct {
+	store    Store
+	location *time.Location
+
+	mu      sync.Mutex
+	entries []*entry
+	byName  map[string]*entry
+
+	ctx     context.Context // cancelled by Stop or by the context given to Start
+	cancel  context.CancelFunc
+	stopped chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New returns a scheduler evaluating schedules in location
+func New(store Store, location *time.Location) *Scheduler {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Scheduler{
+		store:    store,
+		location: location,
+		byName:   make(map[string]*entry),
+		ctx:      context.Background(),
+	}
+}
+
+// Location returns the timezone schedules are evaluated in
+func (s *Scheduler) Location() *time.Location {
+	return s.location
+}
+
+// Register adds a job. Names must be unique; jobs registered after Start are picked up
+// from their next scheduled run.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("job %q needs a name, a schedule and a function", job.Name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.byName[job.Name]; exists {
+		return fmt.Errorf("job %q is already registered", job.Name)
+	}
+	e := &entry{job: job, next: job.Schedule.Next(time.Now().In(s.location))}
+	s.entries = append(s.entries, e)
+	s.byName[job.Name] = e
+	log.Printf("[SCHEDULER] Registered job %s (%s)", job.Name, job.Schedule)
+	return nil
+}
+
+// Start restores the last runs from the store, catches up on missed runs and starts
+// running jobs in the background until ctx is done or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.restore(time.Now())
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.stopped = make(chan struct{})
+	go s.loop()
+}
+
+// Stop stops scheduling jobs, cancels the context of running ones and waits up to
+// timeout for them to return. It reports whether they all did.
+func (s *Scheduler) Stop(timeout time.Duration) bool {
+	if s.cancel == nil {
+		return true
+	}
+	s.cancel()
+	<-s.stopped
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+		log.Printf("[SCHEDULER] Jobs still running after %v, stopping anyway", timeout)
+		return false
+	}
+}
+
+// Jobs returns the status of every job, in the order they were registered
+func (s *Scheduler) Jobs() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		statuses = append(statuses, Status{
+			Name:        e.job.Name,
+			Description: e.job.Description,
+			Schedule:    e.job.Schedule.String(),
+			LastRun:     e.lastRun,
+			LastError:   e.lastError,
+			Next:        e.next,
+			Running:     e.running,
+		})
+	}
+	return statuses
+}
+
+// RunNow runs a job straight away under ctx and returns its error. It does not change
+// when the job runs next.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.Lock()
+	e, exists := s.byName[name]
+	if !exists {
+		s.mu.Unlock()
+		return ErrUnknownJob
+	}
+	if e.running {
+		s.mu.Unlock()
+		return ErrJobRunning
+	}
+	e.running = true
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	log.Printf("[SCHEDULER] Running job %s on request", name)
+	return s.run(ctx, e, time.Now())
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.stopped)
+	for {
+		now := time.Now()
+		s.startDue(now)
+
+		timer := time.NewTimer(s.untilNext(now))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// restore schedules every job from now, loads the last runs and makes jobs that catch
+// up due straight away if they missed a run
+func (s *Scheduler) restore(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		e.next = e.job.Schedule.Next(now.In(s.location))
+	}
+
+	runs, err := s.store.GetJobRuns()
+	if err != nil {
+		log.Printf("[SCHEDULER] Failed to load job runs, not catching up: %v", err)
+		return
+	}
+	for _, e := range s.entries {
+		run, exists := runs[e.job.Name]
+		if !exists {
+			continue
+		}
+		e.lastRun = run.LastRunAt
+		e.lastError = run.LastError
+		missed := e.job.Schedule.Next(run.LastRunAt.In(s.location))
+		if e.job.CatchUp == RunMissedOnce && !missed.IsZero() && !missed.After(now) {
+			log.Printf("[SCHEDULER] Job %s missed its run at %s, catching up", e.job.Name, missed.Format(time.RFC3339))
+			e.next = now
+		}
+	}
+}
+
+// startDue starts every job due at now and schedules its next run
+func (s *Scheduler) startDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return
+	}
+	for _, e := range s.entries {
+		if e.next.IsZero() || e.next.After(now) {
+			continue
+		}
+		e.next = e.job.Schedule.Next(now.In(s.location))
+		if e.running {
+			log.Printf("[SCHEDULER] Job %s is still running, skipping this run", e.job.Name)
+			continue
+		}
+		e.running = true
+		s.wg.Add(1)
+		go s.run(s.ctx, e, now)
+	}
+}
+
+// untilNext returns how long to wait for the next job, at most a minute so that
+// jobs registered later and clock changes are noticed
+func (s *Scheduler) untilNext(now time.Time) time.Duration {
+	wait := time.Minute
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if !e.next.IsZero() && e.next.Sub(now) < wait {
+			wait = e.next.Sub(now)
+		}
+	}
+	return max(wait, 0)
+}
+
+// run runs a job marked as running and records the run; the caller has added it to s.wg
+func (s *Scheduler) run(ctx context.Context, e *entry, at time.Time) error {
+	defer s.wg.Done()
+	err := safeRun(ctx, e.job)
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+		log.Printf("[SCHEDULER] Job %s failed: %v", e.job.Name, err)
+	}
+	if err := s.store.RecordJobRun(e.job.Name, at, errText); err != nil {
+		log.Printf("[SCHEDULER] Failed to record run of job %s: %v", e.job.Name, err)
+	}
+
+	s.mu.Lock()
+	e.lastRun = at
+	e.lastError = errText
+	e.running = false
+	s.mu.Unlock()
+	return err
+}
+
+// safeRun runs a job, turning a panic into an error so one job cannot stop the others
+func safeRun(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
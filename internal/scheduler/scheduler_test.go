@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"askeladden/internal/database"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// counter is a job function that counts its runs and fails with err
+type counter struct {
+	runs int
+	err  error
+}
+
+func (c *counter) run(context.Context) error {
+	c.runs++
+	return c.err
+}
+
+func TestStartDue(t *testing.T) {
+	db := database.NewMemory()
+	s := New(db, time.UTC)
+	daily, failing := &counter{}, &counter{err: errors.New("ingen spørsmål")}
+	s.Register(Job{Name: "dagleg", Schedule: MustParseCron("0 8 * * *"), Run: daily.run})
+	s.Register(Job{Name: "feilar", Schedule: MustParseCron("*/30 * * * *"), Run: failing.run})
+	if err := s.Register(Job{Name: "dagleg", Schedule: MustParseCron("@hourly"), Run: daily.run}); err == nil {
+		t.Errorf("registered the same name twice")
+	}
+
+	morning := time.Date(2024, 5, 17, 7, 50, 0, 0, time.UTC)
+	s.restore(morning)
+	for _, minutes := range []int{0, 10, 11, 40} {
+		s.startDue(morning.Add(time.Duration(minutes) * time.Minute))
+		s.wg.Wait()
+	}
+	if daily.runs != 1 || failing.runs != 2 {
+		t.Fatalf("runs = %d daily, %d failing; want 1 and 2", daily.runs, failing.runs)
+	}
+
+	jobs := s.Jobs()
+	if len(jobs) != 2 || jobs[0].Name != "dagleg" || !jobs[0].LastRun.Equal(morning.Add(10*time.Minute)) ||
+		!jobs[0].Next.Equal(time.Date(2024, 5, 18, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily status = %+v", jobs[0])
+	}
+	if jobs[1].LastError != "ingen spørsmål" || !jobs[1].Next.Equal(morning.Add(70*time.Minute)) {
+		t.Errorf("failing status = %+v", jobs[1])
+	}
+	runs, _ := db.GetJobRuns()
+	if run := runs["feilar"]; run == nil || run.LastError != "ingen spørsmål" || !run.LastRunAt.Equal(morning.Add(40*time.Minute)) {
+		t.Errorf("stored run = %+v", run)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	db := database.NewMemory()
+	lastRun := time.Date(2024, 5, 16, 4, 0, 0, 0, time.UTC)
+	db.RecordJobRun("rydding", lastRun, "")
+	db.RecordJobRun("hoppar", lastRun, "")
+	db.RecordJobRun("nyleg", lastRun.Add(48*time.Hour), "")
+
+	s := New(db, time.UTC)
+	catchUp, skip, recent := &counter{}, &counter{}, &counter{}
+	s.Register(Job{Name: "rydding", Schedule: MustParseCron("0 4 * * *"), CatchUp: RunMissedOnce, Run: catchUp.run})
+	s.Register(Job{Name: "hoppar", Schedule: MustParseCron("0 4 * * *"), Run: skip.run})
+	s.Register(Job{Name: "nyleg", Schedule: MustParseCron("0 4 * * *"), CatchUp: RunMissedOnce, Run: recent.run})
+
+	// Started after two missed runs of the first two jobs, and none of the last
+	now := time.Date(2024, 5, 18, 12, 0, 0, 0, time.UTC)
+	s.restore(now)
+	s.startDue(now)
+	s.wg.Wait()
+	if catchUp.runs != 1 || skip.runs != 0 || recent.runs != 0 {
+		t.Fatalf("runs = %d, %d, %d; want 1, 0, 0", catchUp.runs, skip.runs, recent.runs)
+	}
+	if next := s.Jobs()[0].Next; !next.Equal(time.Date(2024, 5, 19, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("next run after catching up = %s", next)
+	}
+}
+
+func TestRunNow(t *testing.T) {
+	s := New(database.NewMemory(), time.UTC)
+	job := &counter{}
+	s.Register(Job{Name: "rydding", Schedule: MustParseCron("0 4 * * *"), Run: job.run})
+	s.Register(Job{Name: "panikk", Schedule: MustParseCron("0 4 * * *"), Run: func(context.Context) error { panic("oi") }})
+
+	if err := s.RunNow(context.Background(), "rydding"); err != nil || job.runs != 1 {
+		t.Fatalf("RunNow = %v after %d runs", err, job.runs)
+	}
+	if err := s.RunNow(context.Background(), "ukjend"); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("RunNow(unknown) = %v", err)
+	}
+	if err := s.RunNow(context.Background(), "panikk"); err == nil || s.Jobs()[1].Running {
+		t.Errorf("RunNow(panicking) = %v, status %+v", err, s.Jobs()[1])
+	}
+
+	s.byName["rydding"].running = true
+	if err := s.RunNow(context.Background(), "rydding"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("RunNow(running) = %v", err)
+	}
+}
+
+func TestStopCancelsJobs(t *testing.T) {
+	db := database.NewMemory()
+	db.RecordJobRun("ventar", time.Now().Add(-48*time.Hour), "")
+	s := New(db, time.UTC)
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	s.Register(Job{Name: "ventar", Schedule: MustParseCron("@daily"), CatchUp: RunMissedOnce, Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}})
+
+	// The missed run starts straight away and runs until the scheduler stops
+	s.Start(context.Background())
+	<-started
+	if !s.Stop(time.Second) {
+		t.Fatalf("Stop timed out")
+	}
+	select {
+	case <-cancelled:
+	default:
+		t.Fatalf("the running job was not cancelled")
+	}
+	if runs, _ := db.GetJobRuns(); runs["ventar"].LastError != context.Canceled.Error() {
+		t.Errorf("stored run = %+v", runs["ventar"])
+	}
+}
+
+func TestStartStopsWithContext(t *testing.T) {
+	s := New(database.NewMemory(), time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	cancel()
+	select {
+	case <-s.stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("scheduler kept running after its context was cancelled")
+	}
+	if !s.Stop(time.Second) {
+		t.Errorf("Stop timed out")
+	}
+}